@@ -0,0 +1,165 @@
+package archive
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	export "github.com/teran/eos-1v-tagger/export"
+	merge "github.com/teran/eos-1v-tagger/merge"
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+// ErrNotFound is returned when the film is not present in the archive
+var ErrNotFound = errors.New("film not found")
+
+// Archive is a file based store of parsed films keyed by camera ID and
+// film ID
+type Archive struct {
+	path  string
+	films map[string]*types.Film
+}
+
+// ImportReport summarizes an import into the archive
+type ImportReport struct {
+	Films      int
+	NewFilms   int
+	NewFrames  int
+	Duplicates int
+	Conflicts  []merge.Conflict
+}
+
+// Open reads the archive from the file. The archive is stored as JSON
+// export document, missing file is treated as empty archive and is created
+// on Save.
+func Open(path string) (*Archive, error) {
+	a := &Archive{
+		path:  path,
+		films: map[string]*types.Film{},
+	}
+
+	fp, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return a, nil
+		}
+		return nil, err
+	}
+	defer fp.Close()
+
+	doc, err := export.Decode(fp, types.DataFormatJSON)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading archive %s", path)
+	}
+
+	for _, f := range doc.Films {
+		a.films[f.FullID()] = f
+	}
+
+	return a, nil
+}
+
+// Path returns the path of the archive file
+func (a *Archive) Path() string {
+	return a.path
+}
+
+// Import merges films into the archive. Frames already present in the
+// archive are skipped so importing overlapping exports is idempotent,
+// conflicting frames are replaced with the imported ones and reported.
+func (a *Archive) Import(source string, films []*types.Film) *ImportReport {
+	before := a.List()
+	framesBefore := 0
+	for _, f := range before {
+		framesBefore += len(f.Frames)
+	}
+
+	merged, mr := merge.Films([]merge.Input{
+		{Source: a.path, Films: before},
+		{Source: source, Films: films},
+	})
+
+	report := &ImportReport{
+		Films:      len(films),
+		Duplicates: mr.Duplicates,
+		Conflicts:  mr.Conflicts,
+	}
+
+	for _, f := range merged {
+		if _, ok := a.films[f.FullID()]; !ok {
+			report.NewFilms++
+		}
+		report.NewFrames += len(f.Frames)
+		a.films[f.FullID()] = f
+	}
+	report.NewFrames -= framesBefore
+
+	return report
+}
+
+// List returns all the films in the archive sorted by camera ID and film ID,
+// films missing any of the IDs go last
+func (a *Archive) List() []*types.Film {
+	films := make([]*types.Film, 0, len(a.films))
+	for _, f := range a.films {
+		films = append(films, f)
+	}
+
+	sort.Slice(films, func(i, j int) bool {
+		fi, fj := films[i], films[j]
+		if fi.CameraID == nil || fj.CameraID == nil {
+			if fi.CameraID != nil || fj.CameraID != nil {
+				return fj.CameraID == nil
+			}
+		} else if *fi.CameraID != *fj.CameraID {
+			return *fi.CameraID < *fj.CameraID
+		}
+
+		if fi.ID == nil || fj.ID == nil {
+			if fi.ID != nil || fj.ID != nil {
+				return fj.ID == nil
+			}
+			return false
+		}
+		return *fi.ID < *fj.ID
+	})
+
+	return films
+}
+
+// Get returns the film by camera ID and film ID
+func (a *Archive) Get(cameraID uint8, filmID int64) (*types.Film, error) {
+	f, ok := a.films[(&types.Film{CameraID: &cameraID, ID: &filmID}).FullID()]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return f, nil
+}
+
+// Save writes the archive to the file. The file is replaced atomically so
+// the archive is never left half-written.
+func (a *Archive) Save() error {
+	dir := filepath.Dir(a.path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	fp, err := ioutil.TempFile(dir, "."+filepath.Base(a.path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(fp.Name())
+
+	if err := export.Encode(fp, types.DataFormatJSON, export.New(a.List())); err != nil {
+		fp.Close()
+		return err
+	}
+	if err := fp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(fp.Name(), a.path)
+}
@@ -0,0 +1,191 @@
+package archive
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	merge "github.com/teran/eos-1v-tagger/merge"
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+func TestArchive(t *testing.T) {
+	r := require.New(t)
+
+	dir, err := ioutil.TempDir("", "archive")
+	r.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "nested", "archive.json")
+
+	a, err := Open(path)
+	r.NoError(err)
+	r.Empty(a.List())
+
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	r.NoError(err)
+
+	frame := func(n int64) *types.Frame {
+		return &types.Frame{
+			Number:    types.PtrInt64(n),
+			Tv:        types.PtrString("1/125"),
+			Av:        types.PtrAperture(5.6),
+			AFMode:    types.PtrAFMode(types.AFModeOneShotAF),
+			Timestamp: types.PtrTime(time.Date(2019, 10, 7, 20, 2, int(n), 0, moscow)),
+		}
+	}
+
+	export := []*types.Film{
+		{
+			ID:       types.PtrInt64(139),
+			CameraID: types.PtrUint8(1),
+			Title:    types.PtrString("Kodak Portra 400"),
+			Frames:   []*types.Frame{frame(1), frame(2)},
+		},
+	}
+
+	report := a.Import("first.csv", export)
+	r.Equal(&ImportReport{Films: 1, NewFilms: 1, NewFrames: 2, Conflicts: []merge.Conflict{}}, report)
+
+	r.NoError(a.Save())
+
+	a, err = Open(path)
+	r.NoError(err)
+	r.Len(a.List(), 1)
+
+	// overlapping export: one known frame, one new frame and a new film
+	overlap := []*types.Film{
+		{
+			ID:       types.PtrInt64(139),
+			CameraID: types.PtrUint8(1),
+			Frames:   []*types.Frame{frame(2), frame(3)},
+		},
+		{
+			ID:       types.PtrInt64(12),
+			CameraID: types.PtrUint8(1),
+			Frames:   []*types.Frame{frame(1)},
+		},
+	}
+
+	report = a.Import("second.csv", overlap)
+	r.Equal(2, report.Films)
+	r.Equal(1, report.NewFilms)
+	r.Equal(2, report.NewFrames)
+	r.Equal(1, report.Duplicates)
+	r.Empty(report.Conflicts)
+
+	// importing the same export again is a no-op
+	report = a.Import("second.csv", overlap)
+	r.Equal(0, report.NewFilms)
+	r.Equal(0, report.NewFrames)
+	r.Equal(3, report.Duplicates)
+
+	films := a.List()
+	r.Len(films, 2)
+	r.Equal("01-012", films[0].FullID())
+	r.Equal("01-139", films[1].FullID())
+
+	f, err := a.Get(1, 139)
+	r.NoError(err)
+	r.Equal("Kodak Portra 400", *f.Title)
+	r.Len(f.Frames, 3)
+	r.True(frame(1).Timestamp.Equal(*f.Frames[0].Timestamp))
+	r.Equal(types.AFModeOneShotAF, *f.Frames[0].AFMode)
+
+	_, err = a.Get(1, 140)
+	r.Equal(ErrNotFound, err)
+}
+
+func TestImportTwice(t *testing.T) {
+	r := require.New(t)
+
+	dir, err := ioutil.TempDir("", "archive")
+	r.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "archive.json")
+
+	ts := func(sec int) *time.Time {
+		return types.PtrTime(time.Date(2019, 10, 7, 20, 2, sec, 0, time.UTC))
+	}
+
+	// frames without number are repeated on purpose
+	films := []*types.Film{
+		{
+			ID:       types.PtrInt64(139),
+			CameraID: types.PtrUint8(1),
+			Frames: []*types.Frame{
+				{Number: types.PtrInt64(1), Tv: types.PtrString("1/125"), Timestamp: ts(1)},
+				{Tv: types.PtrString("1/60"), Timestamp: ts(2)},
+				{Tv: types.PtrString("1/60"), Timestamp: ts(3)},
+				{Remarks: types.PtrString("unreadable")},
+				{Remarks: types.PtrString("unreadable")},
+			},
+		},
+	}
+
+	a, err := Open(path)
+	r.NoError(err)
+
+	report := a.Import("films.csv", films)
+	r.Equal(5, report.NewFrames)
+	r.NoError(a.Save())
+
+	before, err := ioutil.ReadFile(path)
+	r.NoError(err)
+
+	a, err = Open(path)
+	r.NoError(err)
+
+	report = a.Import("films.csv", films)
+	r.Equal(0, report.NewFilms)
+	r.Equal(0, report.NewFrames)
+	r.Equal(5, report.Duplicates)
+	r.Empty(report.Conflicts)
+	r.NoError(a.Save())
+
+	after, err := ioutil.ReadFile(path)
+	r.NoError(err)
+	r.Equal(string(before), string(after))
+}
+
+func TestOpenInvalid(t *testing.T) {
+	r := require.New(t)
+
+	dir, err := ioutil.TempDir("", "archive")
+	r.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "archive.json")
+	r.NoError(ioutil.WriteFile(path, []byte(`{"version": 99, "films": []}`), 0644))
+
+	_, err = Open(path)
+	r.Error(err)
+	r.Contains(err.Error(), "unsupported document version 99")
+}
+
+func TestListMissingIDs(t *testing.T) {
+	r := require.New(t)
+
+	a := &Archive{films: map[string]*types.Film{}}
+	for _, f := range []*types.Film{
+		{CameraID: types.PtrUint8(1), ID: types.PtrInt64(140)},
+		{ID: types.PtrInt64(5)},
+		{CameraID: types.PtrUint8(1)},
+		{CameraID: types.PtrUint8(1), ID: types.PtrInt64(139)},
+	} {
+		a.films[f.FullID()] = f
+	}
+
+	films := a.List()
+	r.Len(films, 4)
+	r.Equal(int64(139), *films[0].ID)
+	r.Equal(int64(140), *films[1].ID)
+	r.Nil(films[2].ID)
+	r.Equal(uint8(1), *films[2].CameraID)
+	r.Nil(films[3].CameraID)
+}
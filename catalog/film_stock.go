@@ -0,0 +1,102 @@
+package catalog
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+// AssignFilmStocks sets Stock for every film either by explicit assignment
+// of stock catalog key to the film ID (`01-139`) or by the first stock
+// (in catalog key order) which title pattern matches the film title
+func AssignFilmStocks(films []*types.Film, stocks map[string]types.FilmStock, assignments map[string]string) error {
+	keys := make([]string, 0, len(stocks))
+	for k := range stocks {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	patterns := map[string]*regexp.Regexp{}
+	for _, k := range keys {
+		if stocks[k].TitlePattern == "" {
+			continue
+		}
+
+		re, err := regexp.Compile(stocks[k].TitlePattern)
+		if err != nil {
+			return errors.Wrapf(err, "film stock `%s`: error compiling title pattern", k)
+		}
+		patterns[k] = re
+	}
+
+	for _, film := range films {
+		if key, ok := assignments[film.FullID()]; ok {
+			stock, ok := stocks[key]
+			if !ok {
+				return errors.Errorf("film %s is assigned to unknown film stock `%s`", film.FullID(), key)
+			}
+			stock.ID = key
+			film.Stock = &stock
+			continue
+		}
+
+		if film.Title == nil {
+			continue
+		}
+
+		for _, k := range keys {
+			re, ok := patterns[k]
+			if !ok || !re.MatchString(*film.Title) {
+				continue
+			}
+
+			stock := stocks[k]
+			stock.ID = k
+			film.Stock = &stock
+			break
+		}
+	}
+
+	return nil
+}
+
+// FilmStockKeywords returns keywords describing the stock the frame was
+// shot on: stock name, process, format and push/pull if any. Push/pull
+// set for the film explicitly takes precedence over the one computed
+// from frame ISO and box speed.
+func FilmStockKeywords(film *types.Film, f *types.Frame) []string {
+	stock := film.Stock
+	if stock == nil {
+		return nil
+	}
+
+	kws := []string{stock.String()}
+
+	if stock.Process != "" {
+		kws = append(kws, string(stock.Process))
+	}
+
+	if stock.Format != "" {
+		kws = append(kws, stock.Format)
+	}
+
+	var pp float64
+	switch {
+	case film.PushPull != nil:
+		pp = *film.PushPull
+	case f.ISO != nil:
+		pp = stock.PushPull(*f.ISO)
+	}
+
+	switch {
+	case pp > 0:
+		kws = append(kws, "push "+types.FormatStops(pp))
+	case pp < 0:
+		kws = append(kws, "pull "+types.FormatStops(pp))
+	}
+
+	return kws
+}
@@ -0,0 +1,123 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+func TestAssignFilmStocks(t *testing.T) {
+	r := require.New(t)
+
+	stocks := map[string]types.FilmStock{
+		"portra400": {
+			Manufacturer: "Kodak",
+			Name:         "Portra 400",
+			BoxSpeed:     400,
+			Process:      types.FilmProcessC41,
+			TitlePattern: "(?i)portra",
+		},
+		"trix": {
+			Manufacturer: "Kodak",
+			Name:         "Tri-X 400",
+			BoxSpeed:     400,
+			Process:      types.FilmProcessBW,
+			TitlePattern: "(?i)tri-?x",
+		},
+		"hp5": {
+			Manufacturer: "Ilford",
+			Name:         "HP5 Plus",
+			BoxSpeed:     400,
+			Process:      types.FilmProcessBW,
+		},
+	}
+
+	films := []*types.Film{
+		{ID: types.PtrInt64(139), CameraID: types.PtrUint8(1), Title: types.PtrString("Portra in Paris")},
+		{ID: types.PtrInt64(140), CameraID: types.PtrUint8(1), Title: types.PtrString("TriX pushed")},
+		{ID: types.PtrInt64(141), CameraID: types.PtrUint8(1), Title: types.PtrString("Portra, actually HP5")},
+		{ID: types.PtrInt64(142), CameraID: types.PtrUint8(1)},
+	}
+
+	err := AssignFilmStocks(films, stocks, map[string]string{"01-141": "hp5"})
+	r.NoError(err)
+
+	r.Equal("portra400", films[0].Stock.ID)
+	r.Equal("trix", films[1].Stock.ID)
+	r.Equal("hp5", films[2].Stock.ID)
+	r.Equal("Ilford HP5 Plus", films[2].Stock.String())
+	r.Nil(films[3].Stock)
+
+	err = AssignFilmStocks(films, stocks, map[string]string{"01-142": "ektar"})
+	r.Error(err)
+	r.Equal("film 01-142 is assigned to unknown film stock `ektar`", err.Error())
+
+	err = AssignFilmStocks(films, map[string]types.FilmStock{"broken": {TitlePattern: "("}}, nil)
+	r.Error(err)
+}
+
+func TestFilmStockKeywords(t *testing.T) {
+	r := require.New(t)
+
+	stock := &types.FilmStock{
+		Manufacturer: "Kodak",
+		Name:         "Tri-X 400",
+		BoxSpeed:     400,
+		Process:      types.FilmProcessBW,
+		Format:       "135",
+	}
+
+	type testCase struct {
+		name        string
+		stock       *types.FilmStock
+		pushPull    *float64
+		frame       types.Frame
+		expKeywords []string
+	}
+
+	tcs := []testCase{
+		{
+			name:        "no stock",
+			frame:       types.Frame{ISO: types.PtrInt64(400)},
+			expKeywords: nil,
+		},
+		{
+			name:        "box speed",
+			stock:       stock,
+			frame:       types.Frame{ISO: types.PtrInt64(400)},
+			expKeywords: []string{"Kodak Tri-X 400", "B&W", "135"},
+		},
+		{
+			name:        "pushed",
+			stock:       stock,
+			frame:       types.Frame{ISO: types.PtrInt64(1600)},
+			expKeywords: []string{"Kodak Tri-X 400", "B&W", "135", "push +2"},
+		},
+		{
+			name:        "pulled by 2/3 stop",
+			stock:       stock,
+			frame:       types.Frame{ISO: types.PtrInt64(250)},
+			expKeywords: []string{"Kodak Tri-X 400", "B&W", "135", "pull -2/3"},
+		},
+		{
+			name:        "explicitly pushed",
+			stock:       stock,
+			pushPull:    types.PtrFloat64(1),
+			frame:       types.Frame{ISO: types.PtrInt64(400)},
+			expKeywords: []string{"Kodak Tri-X 400", "B&W", "135", "push +1"},
+		},
+		{
+			name:        "no ISO",
+			stock:       &types.FilmStock{Name: "Unknown"},
+			frame:       types.Frame{},
+			expKeywords: []string{"Unknown"},
+		},
+	}
+
+	for _, tc := range tcs {
+		film := &types.Film{Stock: tc.stock, PushPull: tc.pushPull}
+		r.Equalf(tc.expKeywords, FilmStockKeywords(film, &tc.frame), tc.name)
+	}
+}
@@ -0,0 +1,90 @@
+package catalog
+
+import (
+	"time"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+// apertureTolerance covers rounding of max aperture values recorded by camera
+const apertureTolerance = 0.05
+
+// MatchLens returns the lenses from the catalog which could have been used
+// to take the frame judging by focal length, max aperture and ownership
+// dates
+func MatchLens(lenses []types.Lens, f *types.Frame) []types.Lens {
+	if f.FocalLength == nil {
+		return nil
+	}
+
+	matches := []types.Lens{}
+	for _, l := range lenses {
+		if !l.FocalLength.Contains(float64(*f.FocalLength), 0) {
+			continue
+		}
+
+		if f.MaxAperture != nil && !l.MaxAperture.Contains(float64(*f.MaxAperture), apertureTolerance) {
+			continue
+		}
+
+		if f.Timestamp != nil && !owned(l, *f.Timestamp) {
+			continue
+		}
+
+		matches = append(matches, l)
+	}
+
+	return matches
+}
+
+// ChooseLens picks the lens of the frame: the only lens of the catalog
+// matching the frame or the default lens if it's among several matches.
+// The default lens is also used if the frame has neither focal length nor
+// max aperture recorded. The lenses matched are returned if no lens is
+// chosen so the ambiguity or mismatch with the default lens could be
+// reported.
+func ChooseLens(lenses []types.Lens, defaultLens *types.Lens, f *types.Frame) (*types.Lens, []types.Lens) {
+	if defaultLens != nil && f.FocalLength == nil && f.MaxAperture == nil {
+		return defaultLens, nil
+	}
+
+	matches := MatchLens(lenses, f)
+	if len(matches) == 1 {
+		return &matches[0], nil
+	}
+
+	if defaultLens != nil {
+		for i := range matches {
+			if matches[i].Name == defaultLens.Name {
+				return &matches[i], nil
+			}
+		}
+	}
+
+	return nil, matches
+}
+
+// FindLens returns the lens from the catalog by name or nil if there's
+// no such lens
+func FindLens(lenses []types.Lens, name string) *types.Lens {
+	for i := range lenses {
+		if lenses[i].Name == name {
+			return &lenses[i]
+		}
+	}
+	return nil
+}
+
+func owned(l types.Lens, ts time.Time) bool {
+	if len(l.Owned) == 0 {
+		return true
+	}
+
+	for _, r := range l.Owned {
+		if r.Contains(ts) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,222 @@
+package catalog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+func TestMatchLens(t *testing.T) {
+	r := require.New(t)
+
+	lenses := []types.Lens{
+		{
+			Name:        "EF24-70mm f/2.8L USM",
+			FocalLength: types.Range{Min: 24, Max: 70},
+			MaxAperture: types.Range{Min: 2.8, Max: 2.8},
+		},
+		{
+			Name:        "EF24-105mm f/3.5-5.6 IS STM",
+			FocalLength: types.Range{Min: 24, Max: 105},
+			MaxAperture: types.Range{Min: 3.5, Max: 5.6},
+		},
+		{
+			Name:        "EF50mm f/1.4 USM",
+			FocalLength: types.Range{Min: 50, Max: 50},
+			MaxAperture: types.Range{Min: 1.4, Max: 1.4},
+			Owned: []types.DateRange{
+				{To: types.PtrTime(time.Date(2019, 5, 31, 0, 0, 0, 0, time.UTC))},
+			},
+		},
+		{
+			Name:        "Sigma 50mm f/1.4 DG HSM Art",
+			FocalLength: types.Range{Min: 50, Max: 50},
+			MaxAperture: types.Range{Min: 1.4, Max: 1.4},
+			Owned: []types.DateRange{
+				{From: types.PtrTime(time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC))},
+			},
+		},
+	}
+
+	type testCase struct {
+		name       string
+		frame      types.Frame
+		expMatches []string
+	}
+
+	tcs := []testCase{
+		{
+			name:       "no focal length",
+			frame:      types.Frame{MaxAperture: types.PtrAperture(2.8)},
+			expMatches: nil,
+		},
+		{
+			name: "zoom by max aperture",
+			frame: types.Frame{
+				FocalLength: types.PtrInt64(35),
+				MaxAperture: types.PtrAperture(2.8),
+			},
+			expMatches: []string{"EF24-70mm f/2.8L USM"},
+		},
+		{
+			name: "variable aperture zoom",
+			frame: types.Frame{
+				FocalLength: types.PtrInt64(85),
+				MaxAperture: types.PtrAperture(5),
+			},
+			expMatches: []string{"EF24-105mm f/3.5-5.6 IS STM"},
+		},
+		{
+			name: "ambiguous without max aperture",
+			frame: types.Frame{
+				FocalLength: types.PtrInt64(35),
+			},
+			expMatches: []string{"EF24-70mm f/2.8L USM", "EF24-105mm f/3.5-5.6 IS STM"},
+		},
+		{
+			name: "prime by ownership dates",
+			frame: types.Frame{
+				FocalLength: types.PtrInt64(50),
+				MaxAperture: types.PtrAperture(1.4),
+				Timestamp:   types.PtrTime(time.Date(2019, 10, 7, 20, 2, 18, 0, time.UTC)),
+			},
+			expMatches: []string{"Sigma 50mm f/1.4 DG HSM Art"},
+		},
+		{
+			name: "prime on the last day of ownership",
+			frame: types.Frame{
+				FocalLength: types.PtrInt64(50),
+				MaxAperture: types.PtrAperture(1.4),
+				Timestamp:   types.PtrTime(time.Date(2019, 5, 31, 23, 59, 0, 0, time.UTC)),
+			},
+			expMatches: []string{"EF50mm f/1.4 USM"},
+		},
+		{
+			name: "no lens matches",
+			frame: types.Frame{
+				FocalLength: types.PtrInt64(300),
+				MaxAperture: types.PtrAperture(4),
+			},
+			expMatches: []string{},
+		},
+	}
+
+	for _, tc := range tcs {
+		matches := MatchLens(lenses, &tc.frame)
+		if tc.expMatches == nil {
+			r.Nilf(matches, tc.name)
+			continue
+		}
+
+		names := []string{}
+		for _, l := range matches {
+			names = append(names, l.Name)
+		}
+		r.Equalf(tc.expMatches, names, tc.name)
+	}
+}
+
+func TestFindLens(t *testing.T) {
+	r := require.New(t)
+
+	lenses := []types.Lens{
+		{Name: "EF24-70mm f/2.8L USM"},
+		{Name: "EF50mm f/1.4 USM"},
+	}
+
+	r.Equal(&lenses[1], FindLens(lenses, "EF50mm f/1.4 USM"))
+	r.Nil(FindLens(lenses, "EF85mm f/1.8 USM"))
+}
+
+func TestChooseLens(t *testing.T) {
+	r := require.New(t)
+
+	lenses := []types.Lens{
+		{
+			Name:        "EF24-70mm f/2.8L USM",
+			FocalLength: types.Range{Min: 24, Max: 70},
+			MaxAperture: types.Range{Min: 2.8, Max: 2.8},
+		},
+		{
+			Name:        "EF35mm f/2 IS USM",
+			FocalLength: types.Range{Min: 35, Max: 35},
+			MaxAperture: types.Range{Min: 2, Max: 2},
+		},
+		{
+			Name:        "EF50mm f/1.4 USM",
+			FocalLength: types.Range{Min: 50, Max: 50},
+			MaxAperture: types.Range{Min: 1.4, Max: 1.4},
+		},
+		{
+			Name:        "EF200mm f/2.8L II USM",
+			FocalLength: types.Range{Min: 200, Max: 200},
+			MaxAperture: types.Range{Min: 2.8, Max: 2.8},
+		},
+	}
+
+	type testCase struct {
+		name         string
+		defaultLens  *types.Lens
+		frame        types.Frame
+		expLens      string
+		expAmbiguous []string
+	}
+
+	tcs := []testCase{
+		{
+			name:        "single match wins over default lens",
+			defaultLens: &lenses[2],
+			frame:       types.Frame{FocalLength: types.PtrInt64(200)},
+			expLens:     "EF200mm f/2.8L II USM",
+		},
+		{
+			name:        "default lens among several matches",
+			defaultLens: &lenses[1],
+			frame:       types.Frame{FocalLength: types.PtrInt64(35)},
+			expLens:     "EF35mm f/2 IS USM",
+		},
+		{
+			name:         "default lens is not among several matches",
+			defaultLens:  &lenses[2],
+			frame:        types.Frame{FocalLength: types.PtrInt64(35)},
+			expAmbiguous: []string{"EF24-70mm f/2.8L USM", "EF35mm f/2 IS USM"},
+		},
+		{
+			name:         "focal length contradicts default lens",
+			defaultLens:  &lenses[2],
+			frame:        types.Frame{FocalLength: types.PtrInt64(100)},
+			expAmbiguous: []string{},
+		},
+		{
+			name:        "no focal length nor aperture",
+			defaultLens: &lenses[2],
+			frame:       types.Frame{},
+			expLens:     "EF50mm f/1.4 USM",
+		},
+		{
+			name:         "several matches without default lens",
+			frame:        types.Frame{FocalLength: types.PtrInt64(35)},
+			expAmbiguous: []string{"EF24-70mm f/2.8L USM", "EF35mm f/2 IS USM"},
+		},
+	}
+
+	for _, tc := range tcs {
+		l, matches := ChooseLens(lenses, tc.defaultLens, &tc.frame)
+		if tc.expLens != "" {
+			r.NotNilf(l, tc.name)
+			r.Equalf(tc.expLens, l.Name, tc.name)
+			r.Nilf(matches, tc.name)
+			continue
+		}
+
+		r.Nilf(l, tc.name)
+		names := []string{}
+		for _, l := range matches {
+			names = append(names, l.Name)
+		}
+		r.Equalf(tc.expAmbiguous, names, tc.name)
+	}
+}
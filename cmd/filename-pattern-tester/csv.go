@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	format "github.com/teran/eos-1v-tagger/format"
+	parser "github.com/teran/eos-1v-tagger/parser"
+	sidecar "github.com/teran/eos-1v-tagger/sidecar"
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+// checkCSV renders the pattern for every frame in the CSV file and, if
+// scansDir is set, checks every rendered filename against the directory
+// printing the results to w. It returns false if any of the frames has no
+// file or any file in the directory has no frame. Films and frames missing
+// IDs are reported and skipped, these fail the check as well. Film stocks
+// are assigned from the configuration and sidecars as tagger does.
+func checkCSV(w io.Writer, p *format.Pattern, cfg types.Config, csvPath, scansDir, timestampFormat string, location *time.Location) (bool, error) {
+	t, err := parser.New(csvPath, timestampFormat, func(uint8) *time.Location {
+		return location
+	})
+	if err != nil {
+		return false, err
+	}
+	defer t.Close()
+
+	films, err := t.Parse()
+	if err != nil {
+		return false, err
+	}
+
+	sidecars, err := sidecar.LoadDir(cfg.GetSidecarDir())
+	if err != nil {
+		return false, err
+	}
+	if err := sidecar.Apply(films, sidecars, cfg.GetFilmStocks(), cfg.GetLenses()); err != nil {
+		return false, err
+	}
+	err = sidecar.AssignFilmStocks(films, sidecars, cfg.GetFilmStocks(), cfg.GetFilmStockAssignments())
+	if err != nil {
+		return false, err
+	}
+
+	return checkFilms(w, p, films, scansDir)
+}
+
+// checkFilms renders the pattern for every frame of the films, see checkCSV
+func checkFilms(w io.Writer, p *format.Pattern, films []*types.Film, scansDir string) (bool, error) {
+	files := map[string]bool{}
+	if scansDir != "" {
+		fis, err := ioutil.ReadDir(scansDir)
+		if err != nil {
+			return false, err
+		}
+		for _, fi := range fis {
+			if fi.Mode().IsRegular() {
+				files[fi.Name()] = false
+			}
+		}
+	}
+
+	ok := true
+	missing := 0
+	skipped := 0
+	for i, film := range films {
+		if film.CameraID == nil || film.ID == nil {
+			fmt.Fprintf(w, "film #%d: camera ID or film ID is missing, skipped\n", i+1)
+			skipped++
+			ok = false
+			continue
+		}
+
+		fmt.Fprintf(w, "cameraID = %d\n", *film.CameraID)
+		fmt.Fprintf(w, "  filmID = %d\n", *film.ID)
+		for j, f := range film.Frames {
+			if f.Number == nil {
+				fmt.Fprintf(w, "    frame #%d: frame number is missing, skipped\n", j+1)
+				skipped++
+				ok = false
+				continue
+			}
+
+			subst, err := format.FrameSubstitutions(p, film, f)
+			if err != nil {
+				return false, err
+			}
+
+			filename, err := p.Render(subst)
+			if err != nil {
+				return false, err
+			}
+
+			if scansDir == "" {
+				fmt.Fprintf(w, "    %d: %s\n", *f.Number, filename)
+				continue
+			}
+
+			status := "present"
+			rel := filepath.Clean(filename)
+			if _, exists := files[rel]; exists {
+				files[rel] = true
+			} else if !fileExists(filepath.Join(scansDir, filename)) {
+				status = "MISSING"
+				missing++
+				ok = false
+			}
+			fmt.Fprintf(w, "    %d: %s [%s]\n", *f.Number, filename, status)
+		}
+	}
+
+	if skipped > 0 {
+		fmt.Fprintf(w, "\nfilms and frames skipped for missing IDs: %d\n", skipped)
+	}
+
+	if scansDir == "" {
+		return ok, nil
+	}
+
+	unmatched := []string{}
+	for name, matched := range files {
+		if !matched {
+			unmatched = append(unmatched, name)
+		}
+	}
+	sort.Strings(unmatched)
+
+	fmt.Fprintf(w, "\nfiles in %s not mapped to any frame: %d\n", scansDir, len(unmatched))
+	for _, name := range unmatched {
+		fmt.Fprintf(w, "  %s\n", name)
+	}
+	fmt.Fprintf(w, "frames without file: %d\n", missing)
+
+	if len(unmatched) > 0 {
+		ok = false
+	}
+
+	return ok, nil
+}
+
+func fileExists(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.Mode().IsRegular()
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	config "github.com/teran/eos-1v-tagger/config"
+	format "github.com/teran/eos-1v-tagger/format"
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+const testPattern = "${cameraID:02d}-${filmID:03d}-${frameNo:02d}.dng"
+
+func TestCheckFilms(t *testing.T) {
+	r := require.New(t)
+
+	p, err := format.Compile(testPattern, format.FrameVariables)
+	r.NoError(err)
+
+	type testCase struct {
+		name     string
+		films    []*types.Film
+		scansDir string
+		expOK    bool
+		expOut   string
+	}
+
+	tcs := []testCase{
+		{
+			name: "film ID missing",
+			films: []*types.Film{
+				{CameraID: types.PtrUint8(1), Frames: []*types.Frame{{Number: types.PtrInt64(1)}}},
+				{ID: types.PtrInt64(140), CameraID: types.PtrUint8(1), Frames: []*types.Frame{{Number: types.PtrInt64(1)}}},
+			},
+			expOK: false,
+			expOut: `film #1: camera ID or film ID is missing, skipped
+cameraID = 1
+  filmID = 140
+    1: 01-140-01.dng
+
+films and frames skipped for missing IDs: 1
+`,
+		},
+		{
+			name: "frame number missing",
+			films: []*types.Film{
+				{
+					ID:       types.PtrInt64(139),
+					CameraID: types.PtrUint8(1),
+					Frames:   []*types.Frame{{Number: types.PtrInt64(1)}, {}, {Number: types.PtrInt64(3)}},
+				},
+			},
+			scansDir: "testdata/scans",
+			expOK:    false,
+			expOut: `cameraID = 1
+  filmID = 139
+    1: 01-139-01.dng [present]
+    frame #2: frame number is missing, skipped
+    3: 01-139-03.dng [present]
+
+films and frames skipped for missing IDs: 1
+
+files in testdata/scans not mapped to any frame: 1
+  01-140-01.dng
+frames without file: 0
+`,
+		},
+		{
+			name: "all IDs present",
+			films: []*types.Film{
+				{ID: types.PtrInt64(139), CameraID: types.PtrUint8(1), Frames: []*types.Frame{{Number: types.PtrInt64(1)}, {Number: types.PtrInt64(3)}}},
+				{ID: types.PtrInt64(140), CameraID: types.PtrUint8(1), Frames: []*types.Frame{{Number: types.PtrInt64(1)}}},
+			},
+			scansDir: "testdata/scans",
+			expOK:    true,
+			expOut: `cameraID = 1
+  filmID = 139
+    1: 01-139-01.dng [present]
+    3: 01-139-03.dng [present]
+cameraID = 1
+  filmID = 140
+    1: 01-140-01.dng [present]
+
+files in testdata/scans not mapped to any frame: 0
+frames without file: 0
+`,
+		},
+	}
+
+	for _, tc := range tcs {
+		buf := &bytes.Buffer{}
+		ok, err := checkFilms(buf, p, tc.films, tc.scansDir)
+		r.NoErrorf(err, tc.name)
+		r.Equalf(tc.expOK, ok, tc.name)
+		r.Equalf(tc.expOut, buf.String(), tc.name)
+	}
+}
+
+func TestCheckCSVMissingFilmID(t *testing.T) {
+	r := require.New(t)
+
+	p, err := format.Compile(testPattern, format.FrameVariables)
+	r.NoError(err)
+
+	buf := &bytes.Buffer{}
+	ok, err := checkCSV(buf, p, config.NewDefaultConfig(), "testdata/missing-film-id.csv", "testdata/scans", types.TimestampFormatUS.TimeLayout(), time.UTC)
+	r.NoError(err)
+	r.False(ok)
+	r.Equal(`film #1: camera ID or film ID is missing, skipped
+cameraID = 1
+  filmID = 140
+    1: 01-140-01.dng [present]
+
+films and frames skipped for missing IDs: 1
+
+files in testdata/scans not mapped to any frame: 2
+  01-139-01.dng
+  01-139-03.dng
+frames without file: 0
+`, buf.String())
+}
@@ -8,7 +8,11 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/pkg/errors"
+
+	config "github.com/teran/eos-1v-tagger/config"
 	format "github.com/teran/eos-1v-tagger/format"
+	types "github.com/teran/eos-1v-tagger/types"
 )
 
 // LD vars
@@ -26,6 +30,11 @@ func main() {
 		endFilmID      int
 		startFrameNo   int
 		endFrameNo     int
+		csvPath        string
+		configPath     string
+		scansDir       string
+		tsFormat       = types.TimestampFormatUS
+		timezone       string
 		displayVersion bool
 	)
 
@@ -39,7 +48,8 @@ func main() {
 
 	flag.Usage = func() {
 		fmt.Printf("Trivial tool to test filename pattern\n")
-		fmt.Printf("The tool allows to generate amount of cameraID's, filmID's and frameID's to visually check the pattern\n\n")
+		fmt.Printf("The tool allows to generate amount of cameraID's, filmID's and frameID's to visually check the pattern\n")
+		fmt.Printf("or to render the pattern for every frame of ES-E1 CSV file and check the scans directory against it\n\n")
 		flag.PrintDefaults()
 		fmt.Print(versionString)
 	}
@@ -51,6 +61,11 @@ func main() {
 	flag.IntVar(&endFilmID, "end-film-id", 101, "generate filmID's to this ID")
 	flag.IntVar(&startFrameNo, "start-frame-no", 9, "generate frameNo's starting this No")
 	flag.IntVar(&endFrameNo, "end-frame-no", 11, "generate frameNo's to this No")
+	flag.StringVar(&csvPath, "csv", "", "ES-E1 CSV file to render the pattern for every frame from")
+	flag.StringVar(&configPath, "config", "", "tagger configuration file to read film stocks from instead of the discovered ones (requires -csv)")
+	flag.StringVar(&scansDir, "scans-dir", "", "directory with scans to check rendered filenames against (requires -csv)")
+	flag.Var(&tsFormat, "timestamp-format", "the timestamp format used in CSV file. Allowed values: 'US', 'EU'")
+	flag.StringVar(&timezone, "timezone", "UTC", "location or timezone name used while setting time on EOS 1V")
 	flag.BoolVar(&displayVersion, "version", false, "display version and exit")
 	flag.Parse()
 
@@ -59,20 +74,105 @@ func main() {
 		os.Exit(1)
 	}
 
+	p, err := format.Compile(pattern, format.FrameVariables)
+	if err != nil {
+		fmt.Printf("error parsing filename pattern: %s\n", err)
+		os.Exit(1)
+	}
+
 	fmt.Printf("rendering pattern: '%s'\n\n", pattern)
 
+	if csvPath != "" {
+		location, err := time.LoadLocation(timezone)
+		if err != nil {
+			fmt.Printf("error looking up timezone: %s\n", err)
+			os.Exit(1)
+		}
+
+		cfg, err := loadConfig(configPath)
+		if err != nil {
+			fmt.Printf("error: %s\n", err)
+			os.Exit(1)
+		}
+
+		ok, err := checkCSV(os.Stdout, p, cfg, csvPath, scansDir, tsFormat.TimeLayout(), location)
+		if err != nil {
+			fmt.Printf("error: %s\n", err)
+			os.Exit(1)
+		}
+		if !ok {
+			os.Exit(2)
+		}
+		return
+	}
+
+	if scansDir != "" {
+		fmt.Printf("-scans-dir requires -csv to be specified\n")
+		os.Exit(1)
+	}
+
+	if configPath != "" {
+		fmt.Printf("-config requires -csv to be specified\n")
+		os.Exit(1)
+	}
+
 	for cameraID := startCameraID; cameraID < endCameraID; cameraID++ {
 		fmt.Printf("cameraID = %d\n", cameraID)
 		for filmID := startFilmID; filmID < endFilmID; filmID++ {
 			fmt.Printf("  filmID = %d\n", filmID)
 			for frameNo := startFrameNo; frameNo < endFrameNo; frameNo++ {
-				filename := format.Format(pattern, map[string]interface{}{
-					"filmID":   filmID,
-					"cameraID": cameraID,
-					"frameNo":  frameNo,
-				})
+				film := &types.Film{
+					ID:       types.PtrInt64(int64(filmID)),
+					CameraID: types.PtrUint8(uint8(cameraID)),
+				}
+				frame := &types.Frame{
+					Number: types.PtrInt64(int64(frameNo)),
+				}
+
+				subst, err := format.FrameSubstitutions(p, film, frame)
+				if err != nil {
+					fmt.Printf("error rendering filename pattern: %s\n", err)
+					os.Exit(1)
+				}
+
+				filename, err := p.Render(subst)
+				if err != nil {
+					fmt.Printf("error rendering filename pattern: %s\n", err)
+					os.Exit(1)
+				}
 				fmt.Printf("    %d: %s\n", frameNo, filename)
 			}
 		}
 	}
 }
+
+// loadConfig reads tagger configuration the same way tagger does to get the
+// film stock catalog and sidecars, flags are not applied
+func loadConfig(configPath string) (types.Config, error) {
+	cfg := config.NewDefaultConfig()
+
+	configFiles := []string{configPath}
+	if configPath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, errors.Wrap(err, "error obtaining user home directory")
+		}
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, errors.Wrap(err, "error obtaining working directory")
+		}
+		configFiles = config.DiscoverFiles(wd, home, os.Getenv("XDG_CONFIG_HOME"))
+	}
+
+	for _, p := range configFiles {
+		if err := cfg.FillFromYaml(p); err != nil {
+			return nil, errors.Wrapf(err, "error reading config file %s", p)
+		}
+	}
+
+	if err := cfg.FillFromEnv(os.Environ()); err != nil {
+		return nil, errors.Wrap(err, "error handling environment variables")
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	archive "github.com/teran/eos-1v-tagger/archive"
+	export "github.com/teran/eos-1v-tagger/export"
+	inspect "github.com/teran/eos-1v-tagger/inspect"
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+const archiveUsage = `Usage: %[1]v archive [OPTIONS] <command> [arguments]
+
+Commands:
+  import file.csv [file ...|directory|glob ...]  import films from any supported input format into the archive
+  list                                           list films in the archive
+  show <camera ID>-<film ID>                     print film and its frames
+  export [<camera ID>-<film ID> ...]             print films as JSON or YAML document (all films by default)
+  stats [<camera ID>-<film ID> ...]              print shooting statistics of the films (all films by default)
+
+Options:
+`
+
+// runArchive handles `archive` subcommand: persistent store of every film
+// ever parsed
+func runArchive(binary string, args []string) {
+	fs := flag.NewFlagSet("archive", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Printf(archiveUsage, binary)
+		fs.PrintDefaults()
+	}
+
+	archivePath := fs.String("archive", defaultArchivePath(), "archive file to use")
+	configPath := fs.String("config", "", "configuration file to use instead of the discovered ones")
+	format := types.DataFormatJSON
+	fs.Var(&format, "format", "export format. Allowed values: 'json', 'yaml'")
+	statsJSON := fs.Bool("json", false, "print statistics report as JSON")
+	inputFormat := types.InputFormatAuto
+	fs.Var(&inputFormat, "input-format", "format of imported files (default: 'auto', detected by file content)")
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(exitUsage)
+	}
+
+	a, err := archive.Open(*archivePath)
+	if err != nil {
+		log.Fatalf("error opening archive: %s", err)
+	}
+
+	cmd, args := fs.Arg(0), fs.Args()[1:]
+	switch cmd {
+	case "import":
+		if len(args) == 0 {
+			fs.Usage()
+			os.Exit(exitUsage)
+		}
+		cfg := loadConfig(*configPath)
+		mustValidate(cfg)
+		archiveImport(a, cfg, inputFormat, args)
+	case "list":
+		archiveList(a)
+	case "show":
+		if len(args) != 1 {
+			fs.Usage()
+			os.Exit(exitUsage)
+		}
+		archiveShow(getArchivedFilm(a, args[0]))
+	case "export":
+		archiveExport(getArchivedFilms(a, args), format)
+	case "stats":
+		printStats(getArchivedFilms(a, args), *statsJSON)
+	default:
+		log.Printf("unknown archive command `%s`", cmd)
+		fs.Usage()
+		os.Exit(exitUsage)
+	}
+}
+
+func defaultArchivePath() string {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "archive.json"
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, "tagger", "archive.json")
+}
+
+// getArchivedFilms returns the films by IDs or all the films if no IDs are
+// given
+func getArchivedFilms(a *archive.Archive, ids []string) []*types.Film {
+	if len(ids) == 0 {
+		return a.List()
+	}
+
+	films := make([]*types.Film, len(ids))
+	for i, id := range ids {
+		films[i] = getArchivedFilm(a, id)
+	}
+	return films
+}
+
+func getArchivedFilm(a *archive.Archive, id string) *types.Film {
+	cameraID, filmID, err := types.ParseFullID(id)
+	if err != nil {
+		log.Fatalf("%s", err)
+	}
+
+	f, err := a.Get(cameraID, filmID)
+	if err != nil {
+		log.Fatalf("film %s: %s", id, err)
+	}
+	return f
+}
+
+func archiveImport(a *archive.Archive, cfg types.Config, inputFormat types.InputFormat, args []string) {
+	inputFiles, err := expandInputs(args)
+	if err != nil {
+		log.Fatalf("error looking up input files: %s", err)
+	}
+
+	for _, fn := range inputFiles {
+		films, err := parseFilms(cfg, fn, inputFormat)
+		if err != nil {
+			log.Fatalf("error parsing %s: %s", fn, err)
+		}
+		r := a.Import(fn, films)
+		log.Printf("import: %s: %d films (%d new), %d new frames, %d duplicate frames skipped",
+			fn, r.Films, r.NewFilms, r.NewFrames, r.Duplicates)
+		for _, c := range r.Conflicts {
+			log.Printf("import: conflict: %s", c)
+		}
+	}
+
+	if err := a.Save(); err != nil {
+		log.Fatalf("error saving archive: %s", err)
+	}
+}
+
+func archiveList(a *archive.Archive) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tLOADED\tFRAMES\tSTOCK\tTITLE")
+	for _, f := range a.List() {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n",
+			f.FullID(), formatTime(f.FilmLoadedTimestamp, "2006-01-02"), len(f.Frames), filmStock(f), strValue(f.Title))
+	}
+	tw.Flush()
+}
+
+func archiveShow(f *types.Film) {
+	err := inspect.Write(os.Stdout, []*types.Film{f}, inspect.Options{
+		Camera: func(f *types.Film) string {
+			return cameraName(types.CameraProfile{Make: f.Make, Model: f.Model})
+		},
+	})
+	if err != nil {
+		log.Fatalf("error printing film: %s", err)
+	}
+}
+
+func archiveExport(films []*types.Film, format types.DataFormat) {
+	if err := export.Encode(os.Stdout, format, export.New(films)); err != nil {
+		log.Fatalf("error exporting films: %s", err)
+	}
+}
+
+func filmStock(f *types.Film) string {
+	if f.Stock == nil {
+		return ""
+	}
+	return f.Stock.String()
+}
+
+func strValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return strings.TrimSpace(*s)
+}
+
+func formatTime(t *time.Time, layout string) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format(layout)
+}
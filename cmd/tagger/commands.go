@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	config "github.com/teran/eos-1v-tagger/config"
+	contactsheet "github.com/teran/eos-1v-tagger/contactsheet"
+	export "github.com/teran/eos-1v-tagger/export"
+	format "github.com/teran/eos-1v-tagger/format"
+	inspect "github.com/teran/eos-1v-tagger/inspect"
+	parser "github.com/teran/eos-1v-tagger/parser"
+	stats "github.com/teran/eos-1v-tagger/stats"
+	types "github.com/teran/eos-1v-tagger/types"
+	validate "github.com/teran/eos-1v-tagger/validate"
+)
+
+// runInspect prints films and frames
+func runInspect(binary string, cmd config.Command, args []string) {
+	f, cfg := setup(binary, cmd, args)
+
+	opts := inspect.Options{}
+	if f.GetColumns() != "" {
+		columns, err := inspect.ParseColumns(f.GetColumns())
+		if err != nil {
+			usageError(f, "%s", err)
+		}
+		opts.Columns = columns
+	}
+
+	p := mustPrepare(cfg, f)
+	opts.Camera = filmCamera(cfg)
+
+	if err := inspect.Write(os.Stdout, p.films, opts); err != nil {
+		log.Fatalf("error printing films: %s", err)
+	}
+}
+
+// runContactSheet writes HTML contact sheets of the films with thumbnails
+// of the scans matched by filename pattern
+func runContactSheet(binary string, cmd config.Command, args []string) {
+	f, cfg := setup(binary, cmd, args)
+
+	if f.GetOutput() == "" {
+		usageError(f, "output directory is required")
+	}
+
+	if f.GetThumbnailSize() < 0 {
+		usageError(f, "thumbnail size must be positive")
+	}
+
+	pattern, err := format.Compile(cfg.GetFilenamePattern(), format.FrameVariables)
+	if err != nil {
+		log.Fatalf("error parsing filename pattern: %s", err)
+	}
+
+	p := mustPrepare(cfg, f)
+	report, err := contactsheet.Generate(f.GetOutput(), p.films, contactsheet.Options{
+		Scan: func(film *types.Film, frame *types.Frame) string {
+			subst, err := format.FrameSubstitutions(pattern, film, frame)
+			if err != nil {
+				log.Fatalf("error rendering filename pattern: %s", err)
+			}
+
+			fn, err := pattern.Render(subst)
+			if err != nil {
+				log.Fatalf("error rendering filename pattern: %s", err)
+			}
+			return fn
+		},
+		Camera:        filmCamera(cfg),
+		ThumbnailSize: f.GetThumbnailSize(),
+	})
+	if err != nil {
+		log.Fatalf("error writing contact sheets: %s", err)
+	}
+
+	for _, err := range report.Errors {
+		log.Printf("contactsheet: %s", err)
+	}
+	log.Printf("contactsheet: %d films, %d frames, %d thumbnails, %d scans missing, %d unreadable; written to %s",
+		report.Films, report.Frames, report.Thumbnails, report.Missing, len(report.Errors), f.GetOutput())
+}
+
+// filmCamera returns camera name of the film: make and model recorded in
+// the film take precedence over the camera profile
+func filmCamera(cfg types.Config) func(*types.Film) string {
+	return func(film *types.Film) string {
+		camera := filmCameraProfile(cfg, film)
+		if film.Make != nil {
+			camera.Make = film.Make
+		}
+		if film.Model != nil {
+			camera.Model = film.Model
+		}
+		return cameraName(camera)
+	}
+}
+
+// cameraName returns make and model of the camera omitting the make if
+// model starts with it
+func cameraName(camera types.CameraProfile) string {
+	name := strings.TrimSpace(strValue(camera.Model))
+	if mk := strValue(camera.Make); mk != "" && !strings.HasPrefix(name, mk) {
+		name = strings.TrimSpace(mk + " " + name)
+	}
+	return name
+}
+
+// runValidate reports configuration, parse and data problems, exits with
+// exitProblems if any error is found
+func runValidate(binary string, cmd config.Command, args []string) {
+	f, cfg := setupUnvalidated(binary, cmd, args)
+
+	failed := false
+	if err := cfg.Validate(); err != nil {
+		fmt.Printf("error: %s\n", err)
+		failed = true
+	}
+
+	// only apply and verify commands run exiftool
+	if err := cfg.ValidateExiftool(); err != nil {
+		fmt.Printf("warning: %s\n", err)
+	}
+
+	inputs := readInputs(cfg, f)
+	for _, in := range inputs {
+		if in.err != nil {
+			fmt.Printf("error: %s: %s\n", in.fn, in.err)
+			failed = true
+		}
+	}
+
+	p, err := prepare(cfg, inputs)
+	if err != nil {
+		fmt.Printf("error: %s\n", err)
+		os.Exit(exitProblems)
+	}
+
+	problems := validate.Films(p.films)
+	for _, pr := range problems {
+		fmt.Println(pr)
+	}
+
+	var frames int
+	for _, film := range p.films {
+		frames += len(film.Frames)
+	}
+	log.Printf("validate: %d files, %d films, %d frames, %d problems found", len(inputs), len(p.films), frames, len(problems))
+
+	if failed || validate.HasErrors(problems) {
+		os.Exit(exitProblems)
+	}
+}
+
+// runStats prints statistics report of the films
+func runStats(binary string, cmd config.Command, args []string) {
+	f, cfg := setup(binary, cmd, args)
+
+	printStats(mustPrepare(cfg, f).films, f.GetJSON())
+}
+
+func printStats(films []*types.Film, asJSON bool) {
+	s := stats.Films(films)
+
+	var err error
+	if asJSON {
+		err = stats.WriteJSON(os.Stdout, s)
+	} else {
+		err = stats.WriteText(os.Stdout, s)
+	}
+	if err != nil {
+		log.Fatalf("error printing statistics: %s", err)
+	}
+}
+
+// runExport prints films as JSON or YAML document or ES-E1 CSV
+func runExport(binary string, cmd config.Command, args []string) {
+	f, cfg := setup(binary, cmd, args)
+
+	films := mustPrepare(cfg, f).films
+
+	var err error
+	if f.GetFormat() == types.ExportFormatCSV {
+		err = parser.NewWriter(os.Stdout, cfg.GetTimestampFormat().TimeLayout(), cameraLocation(cfg)).Write(films)
+	} else {
+		err = export.Encode(os.Stdout, f.GetFormat().DataFormat(), export.New(films))
+	}
+	if err != nil {
+		log.Fatalf("error exporting films: %s", err)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// expandInputs expands directories to the CSV files within and glob
+// patterns to the files they match, other documents have to be listed
+// explicitly. Files are returned in the order of arguments, the ones
+// expanded from a single argument are sorted by name.
+func expandInputs(args []string) ([]string, error) {
+	files := []string{}
+	for _, arg := range args {
+		st, err := os.Stat(arg)
+		switch {
+		case err == nil && st.IsDir():
+			matches, err := filepath.Glob(filepath.Join(arg, "*.[cC][sS][vV]"))
+			if err != nil {
+				return nil, errors.Wrapf(err, "error listing directory %s", arg)
+			}
+			if len(matches) == 0 {
+				return nil, errors.Errorf("no CSV files found in directory %s", arg)
+			}
+			sort.Strings(matches)
+			files = append(files, matches...)
+		case err == nil:
+			files = append(files, arg)
+		case os.IsNotExist(err) && strings.ContainsAny(arg, "*?["):
+			matches, err := filepath.Glob(arg)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error expanding pattern %s", arg)
+			}
+			if len(matches) == 0 {
+				return nil, errors.Errorf("no files match pattern %s", arg)
+			}
+			sort.Strings(matches)
+			files = append(files, matches...)
+		default:
+			return nil, err
+		}
+	}
+
+	seen := map[string]struct{}{}
+	result := files[:0]
+	for _, f := range files {
+		if _, ok := seen[f]; ok {
+			continue
+		}
+		seen[f] = struct{}{}
+		result = append(result, f)
+	}
+
+	return result, nil
+}
@@ -4,13 +4,12 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"path"
+	"text/tabwriter"
 	"time"
 
 	config "github.com/teran/eos-1v-tagger/config"
-	exiftool "github.com/teran/eos-1v-tagger/exiftool"
-	format "github.com/teran/eos-1v-tagger/format"
 	parser "github.com/teran/eos-1v-tagger/parser"
+	types "github.com/teran/eos-1v-tagger/types"
 )
 
 // LD vars
@@ -19,111 +18,316 @@ var (
 	ldTimestamp = "0"
 )
 
+// Exit codes are the same for every command
+const (
+	exitOK = 0
+
+	// exitError is returned on errors, log.Fatalf exits with it as well
+	exitError = 1
+
+	// exitUsage is returned on wrong arguments, flag package exits with it
+	// on unknown flags as well
+	exitUsage = 2
+
+	// exitProblems is returned when validate or verify found problems
+	exitProblems = 3
+)
+
+const inputsUsage = "[OPTIONS] file.csv|film.json [file ...|directory|glob ...]"
+
+type command struct {
+	config.Command
+
+	run func(binary string, cmd config.Command, args []string)
+}
+
+var commands = []command{
+	{
+		Command: config.Command{
+			Name:        "tag",
+			Usage:       inputsUsage,
+			Description: "Print exiftool commands tagging scans with the data of the input files.",
+			Flags:       config.FlagsInput | config.FlagsTagging,
+		},
+		run: runTag,
+	},
+	{
+		Command: config.Command{
+			Name:        "apply",
+			Usage:       inputsUsage,
+			Description: "Tag scans running exiftool commands printed by `tag`.",
+			Flags:       config.FlagsInput | config.FlagsTagging,
+		},
+		run: runApply,
+	},
+	{
+		Command: config.Command{
+			Name:        "verify",
+			Usage:       inputsUsage,
+			Description: "Read tags back from tagged scans and compare them against the input files.",
+			Flags:       config.FlagsInput | config.FlagsTagging,
+		},
+		run: runVerify,
+	},
+	{
+		Command: config.Command{
+			Name:        "inspect",
+			Usage:       inputsUsage,
+			Description: "Print films and frames of the input files marking flagged, empty frames and frames without timestamp.",
+			Flags:       config.FlagsInput | config.FlagsInspect,
+		},
+		run: runInspect,
+	},
+	{
+		Command: config.Command{
+			Name:        "validate",
+			Usage:       inputsUsage,
+			Description: "Parse the input files and report problems found in configuration, films and frames.",
+			Flags:       config.FlagsInput | config.FlagsTagging,
+		},
+		run: runValidate,
+	},
+	{
+		Command: config.Command{
+			Name:        "export",
+			Usage:       inputsUsage,
+			Description: "Print films of the input files as JSON or YAML document or ES-E1 CSV.",
+			Flags:       config.FlagsInput | config.FlagsExport,
+		},
+		run: runExport,
+	},
+	{
+		Command: config.Command{
+			Name:        "stats",
+			Usage:       inputsUsage,
+			Description: "Print shooting statistics of every film and across all the films.",
+			Flags:       config.FlagsInput | config.FlagsStats,
+		},
+		run: runStats,
+	},
+	{
+		Command: config.Command{
+			Name:        "contactsheet",
+			Usage:       inputsUsage,
+			Description: "Write HTML contact sheet of every film with thumbnails of the scans and shooting data.",
+			Flags:       config.FlagsInput | config.FlagsTagging | config.FlagsContactSheet,
+		},
+		run: runContactSheet,
+	},
+	{
+		Command: config.Command{
+			Name:        "archive",
+			Usage:       "[OPTIONS] <import|list|show|export|stats> [arguments]",
+			Description: "Manage persistent store of every film ever imported.",
+		},
+		run: func(binary string, _ config.Command, args []string) {
+			runArchive(binary, args)
+		},
+	},
+}
+
+// legacyCommand is used for invocation without command
+var legacyCommand = config.Command{
+	Usage:       inputsUsage,
+	Description: "Same as `tag` command, kept for compatibility.",
+	Flags:       config.FlagsInput | config.FlagsTagging | config.FlagsLegacy,
+}
+
 func main() {
-	cfg := config.NewDefaultConfig()
+	if len(os.Args) < 1 {
+		log.Fatalf("looks like programmer error: os.Args is less than 1 in length")
+	}
 
-	home, err := os.UserHomeDir()
-	if err != nil {
-		log.Fatalf("error obtaining user home directory: %s", err)
+	binary := os.Args[0]
+	if len(os.Args) == 1 {
+		printUsage(binary)
+		os.Exit(exitUsage)
+	}
+
+	name, args := os.Args[1], os.Args[2:]
+	switch name {
+	case "help", "-h", "--help":
+		if len(args) == 0 {
+			printUsage(binary)
+			os.Exit(exitOK)
+		}
+		cmd := lookupCommand(args[0])
+		if cmd == nil {
+			log.Printf("unknown command `%s`", args[0])
+			printUsage(binary)
+			os.Exit(exitUsage)
+		}
+		cmd.run(binary, cmd.Command, []string{"-help"})
+		return
 	}
-	err = cfg.FillFromYaml(path.Join(home, ".tagger", "config.yaml"))
-	if err != nil && !os.IsNotExist(err) {
-		log.Fatalf("error reading config file: %s", err)
+
+	if cmd := lookupCommand(name); cmd != nil {
+		cmd.run(binary, cmd.Command, args)
+		return
 	}
 
-	if len(os.Args) < 1 {
-		log.Fatalf("looks like programmer error: os.Args is less than 1 in length")
+	runTag(binary, legacyCommand, os.Args[1:])
+}
+
+func lookupCommand(name string) *command {
+	for i := range commands {
+		if commands[i].Name == name {
+			return &commands[i]
+		}
 	}
+	return nil
+}
 
-	f := config.NewFlags(os.Args[0], ldVersion, ldTimestamp)
-	err = cfg.FillFromFlags(f)
-	if err != nil {
-		log.Fatalf("error handling CLI flags: %s", err)
+func printUsage(binary string) {
+	fmt.Printf("Usage: %[1]v <command> [OPTIONS] [arguments]\n       %[1]v [OPTIONS] file.csv|film.json [file ...] (same as `%[1]v tag`)\n\nCommands:\n", binary)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, c := range commands {
+		fmt.Fprintf(tw, "  %s\t%s\n", c.Name, c.Description)
+	}
+	fmt.Fprintf(tw, "  help <command>\tPrint help of the command.\n")
+	tw.Flush()
+
+	fmt.Printf("\nExit codes: %d on success, %d on errors, %d on wrong arguments, %d if validate or verify found problems.\n",
+		exitOK, exitError, exitUsage, exitProblems)
+	fmt.Printf("Run `%s help <command>` for the command options.\n", binary)
+}
+
+// setup parses command flags and builds configuration from config files,
+// environment variables and flags exiting if the configuration is invalid.
+// Help, version and effective configuration requests are handled here.
+func setup(binary string, cmd config.Command, args []string) (types.Flags, types.Config) {
+	f, cfg := setupUnvalidated(binary, cmd, args)
+	mustValidate(cfg)
+	return f, cfg
+}
+
+// setupUnvalidated is setup leaving the configuration validation to the
+// caller
+func setupUnvalidated(binary string, cmd config.Command, args []string) (types.Flags, types.Config) {
+	f := config.NewFlags(binary, cmd, ldVersion, ldTimestamp, args)
+
+	cfg := loadConfig(f.GetConfigPath())
+
+	if err := cfg.FillFromFlags(f); err != nil {
+		log.Printf("error handling CLI flags: %s", err)
+		os.Exit(exitUsage)
+	}
+
+	if f.GetShowConfig() {
+		if err := cfg.Show(os.Stdout); err != nil {
+			log.Fatalf("error printing configuration: %s", err)
+		}
+		os.Exit(exitOK)
 	}
 
 	if cfg.GetDisplayHelp() {
 		f.PrintUsageString()
-		os.Exit(1)
+		os.Exit(exitOK)
 	}
 
 	if cfg.GetDisplayVersion() {
 		f.PrintVersionString()
-		os.Exit(1)
+		os.Exit(exitOK)
 	}
 
-	if f.GetCSVPath() == "" {
-		f.PrintUsageString()
-		os.Exit(1)
+	return f, cfg
+}
+
+// mustValidate exits if the configuration is invalid
+func mustValidate(cfg types.Config) {
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("%s", err)
 	}
+}
+
+// loadConfig builds configuration from config files and environment
+// variables, flags are left to the caller
+func loadConfig(configPath string) types.Config {
+	cfg := config.NewDefaultConfig()
 
-	lookupTzFn := func(cID uint8) *time.Location {
-		tzname := cfg.GetTimezoneByCameraID(cID)
-		location, err := time.LoadLocation(tzname)
+	configFiles := []string{configPath}
+	if configPath == "" {
+		home, err := os.UserHomeDir()
 		if err != nil {
-			log.Printf("ERROR: error looking up timezone: %s; Switching to default: UTC", err)
-			return time.UTC
+			log.Fatalf("error obtaining user home directory: %s", err)
 		}
-
-		return location
+		wd, err := os.Getwd()
+		if err != nil {
+			log.Fatalf("error obtaining working directory: %s", err)
+		}
+		configFiles = config.DiscoverFiles(wd, home, os.Getenv("XDG_CONFIG_HOME"))
 	}
 
-	t, err := parser.New(f.GetCSVPath(), cfg.GetTimestampFormat().TimeLayout(), lookupTzFn)
-	if err != nil {
-		log.Fatalf("error initializing CSV parser: %s", err)
+	for _, p := range configFiles {
+		if err := cfg.FillFromYaml(p); err != nil {
+			log.Fatalf("error reading config file %s: %s", p, err)
+		}
 	}
 
-	films, err := t.Parse()
-	if err != nil {
-		log.Fatalf("error parsing CSV: %s", err)
+	if err := cfg.FillFromEnv(os.Environ()); err != nil {
+		log.Fatalf("error handling environment variables: %s", err)
 	}
 
-	for _, film := range films {
-		for _, f := range film.Frames {
-			filename := format.Format(cfg.GetFilenamePattern(), map[string]interface{}{
-				"filmID":   *film.ID,
-				"cameraID": *film.CameraID,
-				"frameNo":  *f.Number,
-			})
-
-			et := exiftool.NewFromFrame(cfg.GetExiftoolBinary(), filename, f)
-
-			if cfg.GetSetDigitized() {
-				et.SetDateTimeDigitizedFromCreateDate()
-			}
+	for _, w := range cfg.GetWarnings() {
+		log.Printf("WARNING: %s", w)
+	}
 
-			if cfg.GetMakeByCameraID(*film.CameraID) != nil {
-				v := cfg.GetMakeByCameraID(*film.CameraID)
-				et.Make(*v)
-			}
+	return cfg
+}
 
-			if cfg.GetModelByCameraID(*film.CameraID) != nil {
-				v := cfg.GetModelByCameraID(*film.CameraID)
-				et.Model(*v)
-			}
+// parseFilms reads films from the file in the format given or detected
+// from the file, timestamps are parsed using timestamp format and camera
+// timezones from the configuration. Films are marked with the format unless
+// read from export document which keeps the format films came from.
+func parseFilms(cfg types.Config, fn string, format types.InputFormat) ([]*types.Film, error) {
+	s, inputFormat, err := parser.Open(fn, format, parser.Options{
+		TimestampFormat: cfg.GetTimestampFormat().TimeLayout(),
+		TZ:              cameraLocation(cfg),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
 
-			if cfg.GetSerialNumberByCameraID(*film.CameraID) != nil {
-				v := cfg.GetSerialNumberByCameraID(*film.CameraID)
-				et.SerialNumber(*v)
-			}
+	films, err := s.Parse()
+	if err != nil {
+		return nil, err
+	}
 
-			if cfg.GetFileSource() != nil {
-				v := cfg.GetFileSource()
-				et.FileSource(v.String())
+	if inputFormat.Name != types.InputFormatJSON && inputFormat.Name != types.InputFormatYAML {
+		for _, film := range films {
+			if film.Source == nil {
+				film.Source = types.PtrInputFormat(inputFormat.Name)
 			}
+		}
+	}
 
-			if cfg.GetCopyright() != nil {
-				v := cfg.GetCopyright()
-				et.Copyright(*v)
-			}
+	return films, nil
+}
 
-			if cfg.GetGeotag() != nil {
-				v := cfg.GetGeotag()
+// cameraLocation returns function looking up configured camera timezone
+func cameraLocation(cfg types.Config) func(uint8) *time.Location {
+	return func(cID uint8) *time.Location {
+		tzname := cfg.GetCameraProfile(cID).Timezone
+		if tzname == nil {
+			return time.UTC
+		}
+		location, err := time.LoadLocation(*tzname)
+		if err != nil {
+			log.Printf("ERROR: error looking up timezone: %s; Switching to default: UTC", err)
+			return time.UTC
+		}
 
-				et.GeoTime(*f.Timestamp)
-				et.GeoTag(*v)
-			}
+		return location
+	}
+}
 
-			fmt.Println(et.Cmd())
-		}
+// usageError prints the message along with command usage and exits
+func usageError(f types.Flags, format string, args ...interface{}) {
+	if format != "" {
+		log.Printf(format, args...)
 	}
+	f.PrintUsageString()
+	os.Exit(exitUsage)
 }
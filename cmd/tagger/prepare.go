@@ -0,0 +1,165 @@
+package main
+
+import (
+	"log"
+
+	"github.com/pkg/errors"
+
+	correction "github.com/teran/eos-1v-tagger/correction"
+	geotag "github.com/teran/eos-1v-tagger/geotag"
+	merge "github.com/teran/eos-1v-tagger/merge"
+	parser "github.com/teran/eos-1v-tagger/parser"
+	sidecar "github.com/teran/eos-1v-tagger/sidecar"
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+// input is the parsed input file
+type input struct {
+	fn    string
+	films []*types.Film
+	err   error
+}
+
+// prepared are films of all the input files with film stocks, sidecars
+// and corrections applied
+type prepared struct {
+	films []*types.Film
+}
+
+// filmCameraProfile returns effective camera profile of the film: defaults
+// of the format film was read from originally are overridden by the profile
+// configured for the camera
+func filmCameraProfile(cfg types.Config, film *types.Film) types.CameraProfile {
+	var cameraID uint8
+	if film.CameraID != nil {
+		cameraID = *film.CameraID
+	}
+
+	var defaults types.CameraProfile
+	if film.Source != nil {
+		if f, err := parser.LookupFormat(*film.Source); err == nil {
+			defaults = f.Defaults
+		}
+	}
+	return cfg.GetCameraProfileWithDefaults(cameraID, defaults)
+}
+
+// canonTags reports whether Canon maker notes tags could be written for the
+// film: only ES-E1 records them
+func canonTags(film *types.Film) bool {
+	return film.Source != nil && *film.Source == types.InputFormatCSV
+}
+
+// readInputs expands input paths of the command and parses every file,
+// parse errors are returned within inputs
+func readInputs(cfg types.Config, f types.Flags) []input {
+	if len(f.GetInputPaths()) == 0 {
+		usageError(f, "no input files specified")
+	}
+
+	inputFiles, err := expandInputs(f.GetInputPaths())
+	if err != nil {
+		log.Fatalf("error looking up input files: %s", err)
+	}
+
+	inputs := make([]input, len(inputFiles))
+	for i, fn := range inputFiles {
+		inputs[i].fn = fn
+		films, err := parseFilms(cfg, fn, f.GetInputFormat())
+		if err != nil {
+			inputs[i].err = err
+			continue
+		}
+		inputs[i].films = films
+	}
+	return inputs
+}
+
+// mustPrepare reads and prepares films exiting on any error
+func mustPrepare(cfg types.Config, f types.Flags) *prepared {
+	inputs := readInputs(cfg, f)
+	for _, in := range inputs {
+		if in.err != nil {
+			log.Fatalf("error parsing %s: %s", in.fn, in.err)
+		}
+	}
+
+	p, err := prepare(cfg, inputs)
+	if err != nil {
+		log.Fatalf("%s", err)
+	}
+	return p
+}
+
+// prepare merges films of the inputs parsed successfully, applies timezone
+// overrides and sidecars, assigns film stocks, applies clock corrections and
+// locates frames in GPS track log
+func prepare(cfg types.Config, inputs []input) (*prepared, error) {
+	p := &prepared{}
+
+	mergeInputs := []merge.Input{}
+	for _, in := range inputs {
+		if in.err != nil {
+			continue
+		}
+		mergeInputs = append(mergeInputs, merge.Input{Source: in.fn, Films: in.films})
+	}
+
+	films, mergeReport := merge.Films(mergeInputs)
+	if len(mergeInputs) > 1 {
+		log.Printf("merge: %s", mergeReport)
+	}
+	for _, c := range mergeReport.Conflicts {
+		log.Printf("merge: conflict: %s", c)
+	}
+
+	sidecars, err := sidecar.LoadDir(cfg.GetSidecarDir())
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading film sidecars")
+	}
+
+	tzReports, err := correction.ApplyTimezones(films, cfg.GetTimezoneOverrides())
+	if err != nil {
+		return nil, errors.Wrap(err, "error applying timezone overrides")
+	}
+	for _, r := range tzReports {
+		log.Printf("timezone override: %s", r)
+	}
+
+	err = sidecar.Apply(films, sidecars, cfg.GetFilmStocks(), cfg.GetLenses())
+	if err != nil {
+		return nil, err
+	}
+
+	err = sidecar.AssignFilmStocks(films, sidecars, cfg.GetFilmStocks(), cfg.GetFilmStockAssignments())
+	if err != nil {
+		return nil, errors.Wrap(err, "error assigning film stocks")
+	}
+
+	reports, err := correction.ApplyClock(films, func(cameraID uint8) *types.ClockCorrection {
+		return cfg.GetCameraProfile(cameraID).ClockCorrection
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error applying clock correction")
+	}
+	for _, r := range reports {
+		log.Printf("clock correction: %s", r)
+	}
+
+	// frames are located by the corrected timestamps
+	if cfg.GetGeotag() != nil {
+		track, err := geotag.ParseFile(*cfg.GetGeotag())
+		if err != nil {
+			return nil, errors.Wrap(err, "error reading GPS track log")
+		}
+
+		report := geotag.New(track, cfg.GetGeotagMaxGap(), cfg.GetGeotagOffset()).Apply(films)
+		for _, pr := range report.Problems {
+			log.Printf("geotag: %s", pr)
+		}
+		log.Printf("geotag: %s", report)
+	}
+
+	p.films = films
+	return p, nil
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	catalog "github.com/teran/eos-1v-tagger/catalog"
+	config "github.com/teran/eos-1v-tagger/config"
+	exiftool "github.com/teran/eos-1v-tagger/exiftool"
+	export "github.com/teran/eos-1v-tagger/export"
+	format "github.com/teran/eos-1v-tagger/format"
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+// runTag prints exiftool commands for every frame, exiftool is not run so
+// it's only warned about if missing
+func runTag(binary string, cmd config.Command, args []string) {
+	f, cfg := setup(binary, cmd, args)
+
+	if err := cfg.ValidateExiftool(); err != nil && f.GetExport() == "" {
+		log.Printf("WARNING: %s", err)
+	}
+
+	p := mustPrepare(cfg, f)
+
+	if f.GetExport() != "" {
+		if err := export.Encode(os.Stdout, f.GetExport(), export.New(p.films)); err != nil {
+			log.Fatalf("error exporting films: %s", err)
+		}
+		return
+	}
+
+	for _, et := range buildCommands(cfg, p) {
+		fmt.Println(et.Cmd())
+	}
+}
+
+// runApply runs exiftool commands for every frame, the files missing are
+// reported and skipped
+func runApply(binary string, cmd config.Command, args []string) {
+	f, cfg := setup(binary, cmd, args)
+
+	if err := cfg.ValidateExiftool(); err != nil {
+		log.Fatalf("%s", err)
+	}
+
+	var tagged, missing, failed int
+	for _, et := range buildCommands(cfg, mustPrepare(cfg, f)) {
+		if _, err := os.Stat(et.Filename()); err != nil {
+			log.Printf("apply: %s: %s", et.Filename(), err)
+			missing++
+			continue
+		}
+
+		out, err := et.Run()
+		if err != nil {
+			log.Printf("apply: %s: %s: %s", et.Filename(), err, strings.TrimSpace(string(out)))
+			failed++
+			continue
+		}
+		tagged++
+	}
+
+	log.Printf("apply: %d files tagged, %d missing, %d failed", tagged, missing, failed)
+	if missing+failed > 0 {
+		os.Exit(exitError)
+	}
+}
+
+// runVerify reads tags back from the files and compares them against the
+// ones exiftool commands set
+func runVerify(binary string, cmd config.Command, args []string) {
+	f, cfg := setup(binary, cmd, args)
+
+	if err := cfg.ValidateExiftool(); err != nil {
+		log.Fatalf("%s", err)
+	}
+
+	var verified, missing, mismatched int
+	for _, et := range buildCommands(cfg, mustPrepare(cfg, f)) {
+		if _, err := os.Stat(et.Filename()); err != nil {
+			fmt.Printf("%s: %s\n", et.Filename(), err)
+			missing++
+			continue
+		}
+
+		tags, err := et.ReadTags()
+		if err != nil {
+			log.Fatalf("%s", err)
+		}
+
+		mismatches := et.Verify(tags)
+		for _, m := range mismatches {
+			fmt.Printf("%s: %s\n", et.Filename(), m)
+		}
+		if len(mismatches) > 0 {
+			mismatched++
+			continue
+		}
+		verified++
+	}
+
+	log.Printf("verify: %d files match, %d mismatch, %d missing", verified, mismatched, missing)
+	if missing+mismatched > 0 {
+		os.Exit(exitProblems)
+	}
+}
+
+// buildCommands builds exiftool commands for every frame of prepared films
+func buildCommands(cfg types.Config, p *prepared) []*exiftool.ExifTool {
+	pattern, err := format.Compile(cfg.GetFilenamePattern(), format.FrameVariables)
+	if err != nil {
+		log.Fatalf("error parsing filename pattern: %s", err)
+	}
+
+	commands := []*exiftool.ExifTool{}
+	ambiguousLenses := []string{}
+	mismatchedLenses := []string{}
+	for _, film := range p.films {
+		for _, f := range film.Frames {
+			subst, err := format.FrameSubstitutions(pattern, film, f)
+			if err != nil {
+				log.Fatalf("error rendering filename pattern: %s", err)
+			}
+
+			filename, err := pattern.Render(subst)
+			if err != nil {
+				log.Fatalf("error rendering filename pattern: %s", err)
+			}
+
+			et := exiftool.NewFromFrame(cfg.GetExiftoolBinary(), filename, f)
+			if !canonTags(film) {
+				et.RemoveCanonTags()
+			}
+
+			if cfg.GetSetDigitized() {
+				et.SetDateTimeDigitizedFromCreateDate()
+			}
+
+			camera := filmCameraProfile(cfg, film)
+
+			if film.Make != nil {
+				et.Make(*film.Make)
+			} else if camera.Make != nil {
+				et.Make(*camera.Make)
+			}
+
+			if film.Model != nil {
+				et.Model(*film.Model)
+			} else if camera.Model != nil {
+				et.Model(*camera.Model)
+			}
+
+			if camera.SerialNumber != nil {
+				et.SerialNumber(*camera.SerialNumber)
+			}
+
+			if camera.Owner != nil {
+				et.OwnerName(*camera.Owner)
+			}
+
+			if cfg.GetFileSource() != nil {
+				v := cfg.GetFileSource()
+				et.FileSource(v.String())
+			}
+
+			if film.Copyright != nil {
+				et.Copyright(*film.Copyright)
+			} else if cfg.GetCopyright() != nil {
+				v := cfg.GetCopyright()
+				et.Copyright(*v)
+			}
+
+			if camera.Artist != nil {
+				et.Artist(*camera.Artist)
+			}
+
+			if film.Location != nil {
+				et.Location(*film.Location)
+			}
+
+			if film.Title != nil {
+				et.Title(*film.Title)
+			}
+
+			if film.Remarks != nil {
+				et.Description(*film.Remarks)
+			}
+
+			if kws := catalog.FilmStockKeywords(film, f); len(kws) > 0 {
+				et.Keywords(kws...)
+			}
+
+			if film.Developer != nil {
+				et.Keywords("developer: " + *film.Developer)
+			}
+
+			if film.Lab != nil {
+				et.Keywords("lab: " + *film.Lab)
+			}
+
+			var defaultLens *types.Lens
+			if camera.Lens != nil {
+				defaultLens = catalog.FindLens(cfg.GetLenses(), *camera.Lens)
+			}
+
+			if f.Lens != nil {
+				if l := catalog.FindLens(cfg.GetLenses(), *f.Lens); l != nil {
+					et.Lens(*l)
+				} else {
+					et.LensModel(*f.Lens)
+				}
+			} else if film.Lens != nil {
+				et.Lens(*film.Lens)
+			} else if lenses := cfg.GetLenses(); len(lenses) > 0 {
+				l, matches := catalog.ChooseLens(lenses, defaultLens, f)
+				switch {
+				case l != nil:
+					et.Lens(*l)
+				case len(matches) > 1:
+					names := make([]string, len(matches))
+					for i, l := range matches {
+						names[i] = l.Name
+					}
+					ambiguousLenses = append(ambiguousLenses, fmt.Sprintf(
+						"film %s frame %s: %s", film.FullID(), frameNo(f), strings.Join(names, ", ")))
+				case defaultLens != nil:
+					mismatchedLenses = append(mismatchedLenses, fmt.Sprintf(
+						"film %s frame %s: %s", film.FullID(), frameNo(f), defaultLens.Name))
+				}
+			}
+
+			if f.Position != nil {
+				et.GPSPosition(*f.Position)
+			}
+
+			commands = append(commands, et)
+		}
+	}
+
+	if len(ambiguousLenses) > 0 {
+		log.Printf("lens: %d frames match several lenses, lens tags are not set:", len(ambiguousLenses))
+		for _, v := range ambiguousLenses {
+			log.Printf("lens:   %s", v)
+		}
+	}
+
+	if len(mismatchedLenses) > 0 {
+		log.Printf("lens: %d frames don't match camera default lens nor any other lens, lens tags are not set:", len(mismatchedLenses))
+		for _, v := range mismatchedLenses {
+			log.Printf("lens:   %s", v)
+		}
+	}
+
+	return commands
+}
+
+// frameNo returns frame number for log messages, `?` if it's not recorded
+func frameNo(f *types.Frame) string {
+	if f.Number == nil {
+		return "?"
+	}
+	return strconv.FormatInt(*f.Number, 10)
+}
@@ -1,7 +1,10 @@
 package config
 
 import (
+	"fmt"
+	"io"
 	"os"
+	"time"
 
 	yaml "gopkg.in/yaml.v2"
 
@@ -12,46 +15,71 @@ import (
 type config struct {
 	displayHelp     bool
 	displayVersion  bool
+	cameras         map[uint8]types.CameraProfile
 	copyright       *string
 	exiftoolBinary  string
 	filenamePattern string
 	fileSource      *types.FileSource
+	filmStocks      map[string]types.FilmStock
+	stockAssigns    map[string]string
 	geotag          *string
-	make            map[uint8]string
-	model           map[uint8]string
-	serialNumber    map[uint8]string
+	geotagMaxGap    time.Duration
+	geotagOffset    time.Duration
+	lenses          []types.Lens
+	sidecarDir      string
 	setDigitized    bool
 	timestampFormat types.TimestampFormat
-	timezone        map[uint8]types.Timezone
+	tzOverrides     []types.TimezoneOverride
+
+	sources  map[string]string
+	warnings []string
 }
 
 // YamlConfig ...
 type YamlConfig struct {
-	Copyright       *string                  `yaml:"copyright"`
-	ExiftoolBinary  *string                  `yaml:"exiftool-binary"`
-	FilenamePattern *string                  `yaml:"filename-pattern"`
-	FileSource      *types.FileSource        `yaml:"file-source"`
-	Make            map[uint8]string         `yaml:"make"`
-	Model           map[uint8]string         `yaml:"model"`
-	SerialNumber    map[uint8]string         `yaml:"serial-number"`
-	SetDigitized    *bool                    `yaml:"set-digitized"`
-	TimestampFormat *types.TimestampFormat   `yaml:"timestamp-format"`
-	Timezone        map[uint8]types.Timezone `yaml:"timezone"`
+	Cameras         map[uint8]types.CameraProfile `yaml:"cameras"`
+	Copyright       *string                       `yaml:"copyright"`
+	ExiftoolBinary  *string                       `yaml:"exiftool-binary"`
+	FilenamePattern *string                       `yaml:"filename-pattern"`
+	FileSource      *types.FileSource             `yaml:"file-source"`
+	FilmStocks      map[string]types.FilmStock    `yaml:"film-stocks"`
+	StockAssigns    map[string]string             `yaml:"film-stock-assignments"`
+	Geotag          *string                       `yaml:"geotag"`
+	GeotagMaxGap    *time.Duration                `yaml:"geotag-max-gap"`
+	GeotagOffset    *time.Duration                `yaml:"geotag-offset"`
+	Lenses          []types.Lens                  `yaml:"lenses"`
+	SidecarDir      *string                       `yaml:"sidecar-dir"`
+	SetDigitized    *bool                         `yaml:"set-digitized"`
+	TimestampFormat *types.TimestampFormat        `yaml:"timestamp-format"`
+	TzOverrides     []types.TimezoneOverride      `yaml:"timezone-overrides"`
+
+	// Deprecated: per-camera values are set in Cameras
+	ClockCorrection map[uint8]types.ClockCorrection `yaml:"clock-correction"`
+	Make            map[uint8]string                `yaml:"make"`
+	Model           map[uint8]string                `yaml:"model"`
+	SerialNumber    map[uint8]string                `yaml:"serial-number"`
+	Timezone        map[uint8]types.Timezone        `yaml:"timezone"`
 }
 
 // NewDefaultConfig ...
 func NewDefaultConfig() types.Config {
 	c := &config{
 		exiftoolBinary:  "exiftool",
+		geotagMaxGap:    30 * time.Minute,
+		sidecarDir:      ".",
 		filenamePattern: `FILM_${cameraID:02d}${filmID:03d}${frameNo:05d}.dng`,
 		timestampFormat: types.TimestampFormatUS,
-		timezone:        map[uint8]string{0: "UTC"},
+		cameras: map[uint8]types.CameraProfile{
+			0: {Timezone: types.PtrString("UTC")},
+		},
+		sources: map[string]string{},
 	}
 
 	return c
 }
 
-// FillFromYAMLFile ...
+// FillFromYaml fills the config from YAML file. Values set in the file
+// are recorded as coming from path
 func (c *config) FillFromYaml(path string) error {
 	_, err := os.Stat(path)
 	if err != nil {
@@ -64,52 +92,141 @@ func (c *config) FillFromYaml(path string) error {
 	defer fp.Close()
 
 	var ycfg YamlConfig
-	err = yaml.NewDecoder(fp).Decode(&ycfg)
+	dec := yaml.NewDecoder(fp)
+	dec.SetStrict(true)
+	err = dec.Decode(&ycfg)
+	if err == io.EOF {
+		// empty file
+		return nil
+	}
 	if err != nil {
 		return err
 	}
-	return c.fillFromYamlConfig(ycfg)
+
+	for _, key := range ycfg.deprecatedKeys() {
+		c.warnings = append(c.warnings, fmt.Sprintf(
+			"%s: `%s` is deprecated, use `cameras.<camera ID>.%s` instead", path, key, key))
+	}
+
+	return c.fillFromYamlConfig(ycfg, path)
 }
 
-func (c *config) fillFromYamlConfig(ycfg YamlConfig) error {
+func (ycfg YamlConfig) deprecatedKeys() []string {
+	keys := []string{}
+	if ycfg.ClockCorrection != nil {
+		keys = append(keys, "clock-correction")
+	}
+	if ycfg.Make != nil {
+		keys = append(keys, "make")
+	}
+	if ycfg.Model != nil {
+		keys = append(keys, "model")
+	}
+	if ycfg.SerialNumber != nil {
+		keys = append(keys, "serial-number")
+	}
+	if ycfg.Timezone != nil {
+		keys = append(keys, "timezone")
+	}
+	return keys
+}
+
+func (c *config) fillFromYamlConfig(ycfg YamlConfig, source string) error {
+	// deprecated per-camera keys go first to let `cameras` section
+	// of the same source override them
+	for cameraID, v := range ycfg.ClockCorrection {
+		v := v
+		c.mergeCameraProfile(cameraID, types.CameraProfile{ClockCorrection: &v}, source)
+	}
+
+	for cameraID, v := range ycfg.Make {
+		c.mergeCameraProfile(cameraID, types.CameraProfile{Make: types.PtrString(v)}, source)
+	}
+
+	for cameraID, v := range ycfg.Model {
+		c.mergeCameraProfile(cameraID, types.CameraProfile{Model: types.PtrString(v)}, source)
+	}
+
+	for cameraID, v := range ycfg.SerialNumber {
+		c.mergeCameraProfile(cameraID, types.CameraProfile{SerialNumber: types.PtrString(v)}, source)
+	}
+
+	for cameraID, v := range ycfg.Timezone {
+		c.mergeCameraProfile(cameraID, types.CameraProfile{Timezone: types.PtrString(v)}, source)
+	}
+
+	for cameraID, p := range ycfg.Cameras {
+		c.mergeCameraProfile(cameraID, p, source)
+	}
+
 	if ycfg.Copyright != nil {
 		c.copyright = ycfg.Copyright
+		c.setSource("copyright", source)
 	}
 
 	if ycfg.ExiftoolBinary != nil {
 		c.exiftoolBinary = *ycfg.ExiftoolBinary
+		c.setSource("exiftool-binary", source)
 	}
 
 	if ycfg.FilenamePattern != nil {
 		c.filenamePattern = *ycfg.FilenamePattern
+		c.setSource("filename-pattern", source)
 	}
 
 	if ycfg.FileSource != nil {
 		c.fileSource = ycfg.FileSource
+		c.setSource("file-source", source)
 	}
 
-	if ycfg.Make != nil {
-		c.make = ycfg.Make
+	if ycfg.FilmStocks != nil {
+		c.filmStocks = ycfg.FilmStocks
+		c.setSource("film-stocks", source)
 	}
 
-	if ycfg.Model != nil {
-		c.model = ycfg.Model
+	if ycfg.StockAssigns != nil {
+		c.stockAssigns = ycfg.StockAssigns
+		c.setSource("film-stock-assignments", source)
 	}
 
-	if ycfg.SerialNumber != nil {
-		c.serialNumber = ycfg.SerialNumber
+	if ycfg.Geotag != nil {
+		c.geotag = ycfg.Geotag
+		c.setSource("geotag", source)
+	}
+
+	if ycfg.GeotagMaxGap != nil {
+		c.geotagMaxGap = *ycfg.GeotagMaxGap
+		c.setSource("geotag-max-gap", source)
+	}
+
+	if ycfg.GeotagOffset != nil {
+		c.geotagOffset = *ycfg.GeotagOffset
+		c.setSource("geotag-offset", source)
+	}
+
+	if ycfg.Lenses != nil {
+		c.lenses = ycfg.Lenses
+		c.setSource("lenses", source)
+	}
+
+	if ycfg.SidecarDir != nil {
+		c.sidecarDir = *ycfg.SidecarDir
+		c.setSource("sidecar-dir", source)
 	}
 
 	if ycfg.SetDigitized != nil {
 		c.setDigitized = *ycfg.SetDigitized
+		c.setSource("set-digitized", source)
 	}
 
 	if ycfg.TimestampFormat != nil {
 		c.timestampFormat = *ycfg.TimestampFormat
+		c.setSource("timestamp-format", source)
 	}
 
-	if ycfg.Timezone != nil {
-		c.timezone = ycfg.Timezone
+	if ycfg.TzOverrides != nil {
+		c.tzOverrides = ycfg.TzOverrides
+		c.setSource("timezone-overrides", source)
 	}
 
 	return nil
@@ -124,59 +241,80 @@ func (c *config) FillFromFlags(f types.Flags) error {
 		c.displayVersion = f.GetDisplayVersion()
 	}
 
+	for cameraID, offset := range f.GetClockOffset() {
+		v := offset
+		c.mergeCameraProfile(cameraID, types.CameraProfile{
+			ClockCorrection: &types.ClockCorrection{Offset: &v},
+		}, "flag -clock-offset")
+	}
+
 	if f.GetCopyright() != "" {
 		v := f.GetCopyright()
 		c.copyright = &v
+		c.setSource("copyright", "flag -copyright")
 	}
 
 	if f.GetExiftoolBinary() != "" {
 		c.exiftoolBinary = f.GetExiftoolBinary()
+		c.setSource("exiftool-binary", "flag -exiftool-binary")
 	}
 
 	if f.GetFileSource() != "" {
 		v := f.GetFileSource()
 		c.fileSource = &v
+		c.setSource("file-source", "flag -file-source")
 	}
 
 	if f.GetFilenamePattern() != "" {
 		c.filenamePattern = f.GetFilenamePattern()
+		c.setSource("filename-pattern", "flag -filename-pattern")
 	}
 
 	if f.GetGeotag() != "" {
 		v := f.GetGeotag()
 		c.geotag = &v
+		c.setSource("geotag", "flag -geotag")
+	}
+
+	if f.GetGeotagMaxGap() != 0 {
+		c.geotagMaxGap = f.GetGeotagMaxGap()
+		c.setSource("geotag-max-gap", "flag -geotag-max-gap")
+	}
+
+	if f.GetGeotagOffset() != 0 {
+		c.geotagOffset = f.GetGeotagOffset()
+		c.setSource("geotag-offset", "flag -geotag-offset")
+	}
+
+	for cameraID, v := range f.GetMake() {
+		c.mergeCameraProfile(cameraID, types.CameraProfile{Make: types.PtrString(v)}, "flag -make")
 	}
 
-	if f.GetMake() != "" {
-		c.make = map[uint8]string{
-			0: f.GetMake(),
-		}
+	for cameraID, v := range f.GetModel() {
+		c.mergeCameraProfile(cameraID, types.CameraProfile{Model: types.PtrString(v)}, "flag -model")
 	}
 
-	if f.GetModel() != "" {
-		c.model = map[uint8]string{
-			0: f.GetModel(),
-		}
+	for cameraID, v := range f.GetSerialNumber() {
+		c.mergeCameraProfile(cameraID, types.CameraProfile{SerialNumber: types.PtrString(v)}, "flag -serial-number")
 	}
 
-	if f.GetSerialNumber() != "" {
-		c.serialNumber = map[uint8]string{
-			0: f.GetSerialNumber(),
-		}
+	if f.GetSidecarDir() != "" {
+		c.sidecarDir = f.GetSidecarDir()
+		c.setSource("sidecar-dir", "flag -sidecar-dir")
 	}
 
 	if f.GetSetDigitized() {
 		c.setDigitized = f.GetSetDigitized()
+		c.setSource("set-digitized", "flag -set-digitized")
 	}
 
 	if f.GetTimestampFormat() != "" {
 		c.timestampFormat = f.GetTimestampFormat()
+		c.setSource("timestamp-format", "flag -timestamp-format")
 	}
 
-	if f.GetTimezone() != "" {
-		c.timezone = map[uint8]string{
-			0: f.GetTimezone(),
-		}
+	for cameraID, v := range f.GetTimezone() {
+		c.mergeCameraProfile(cameraID, types.CameraProfile{Timezone: types.PtrString(v)}, "flag -timezone")
 	}
 
 	return nil
@@ -189,6 +327,27 @@ func (c *config) GetDisplayVersion() bool {
 	return c.displayVersion
 }
 
+// GetCameraProfile returns profile of the camera merged with the profile
+// of camera ID 0 holding the values common for all of the cameras
+func (c *config) GetCameraProfile(cameraID uint8) types.CameraProfile {
+	p := c.cameras[0]
+	if cameraID != 0 {
+		p = p.Merge(c.cameras[cameraID])
+	}
+	return p
+}
+
+// GetCameraProfileWithDefaults returns profile of the camera with the
+// defaults (e.g. of the input format) set above the profile of camera ID 0
+// so only the profile set for the camera itself overrides them
+func (c *config) GetCameraProfileWithDefaults(cameraID uint8, defaults types.CameraProfile) types.CameraProfile {
+	p := c.cameras[0].Merge(defaults)
+	if cameraID != 0 {
+		p = p.Merge(c.cameras[cameraID])
+	}
+	return p
+}
+
 func (c *config) GetCopyright() *string {
 	return c.copyright
 }
@@ -205,31 +364,32 @@ func (c *config) GetFileSource() *types.FileSource {
 	return c.fileSource
 }
 
+func (c *config) GetFilmStocks() map[string]types.FilmStock {
+	return c.filmStocks
+}
+
+func (c *config) GetFilmStockAssignments() map[string]string {
+	return c.stockAssigns
+}
+
 func (c *config) GetGeotag() *string {
 	return c.geotag
 }
 
-func (c *config) GetMakeByCameraID(cameraID uint8) *string {
-	v := getOrDefault(c.make, cameraID, 0, "")
-	if v == "" {
-		return nil
-	}
-	return &v
+func (c *config) GetGeotagMaxGap() time.Duration {
+	return c.geotagMaxGap
 }
 
-func (c *config) GetModelByCameraID(cameraID uint8) *string {
-	v := getOrDefault(c.model, cameraID, 0, "")
-	if v == "" {
-		return nil
-	}
-	return &v
+func (c *config) GetGeotagOffset() time.Duration {
+	return c.geotagOffset
 }
-func (c *config) GetSerialNumberByCameraID(cameraID uint8) *string {
-	v := getOrDefault(c.serialNumber, cameraID, 0, "")
-	if v == "" {
-		return nil
-	}
-	return &v
+
+func (c *config) GetLenses() []types.Lens {
+	return c.lenses
+}
+
+func (c *config) GetSidecarDir() string {
+	return c.sidecarDir
 }
 
 func (c *config) GetSetDigitized() bool {
@@ -239,18 +399,33 @@ func (c *config) GetSetDigitized() bool {
 func (c *config) GetTimestampFormat() *types.TimestampFormat {
 	return &c.timestampFormat
 }
-func (c *config) GetTimezoneByCameraID(cameraID uint8) types.Timezone {
-	return getOrDefault(c.timezone, cameraID, 0, "UTC")
+func (c *config) GetTimezoneOverrides() []types.TimezoneOverride {
+	return c.tzOverrides
 }
 
-func getOrDefault(d map[uint8]string, key, defaultKey uint8, defaultValue string) string {
-	if k, ok := d[key]; ok {
-		return k
+// GetWarnings returns the list of non-fatal configuration problems like
+// deprecated keys usage
+func (c *config) GetWarnings() []string {
+	return c.warnings
+}
+
+func (c *config) setSource(key, source string) {
+	c.sources[key] = source
+}
+
+// mergeCameraProfile sets the fields set in p over the profile of the camera
+func (c *config) mergeCameraProfile(cameraID uint8, p types.CameraProfile, source string) {
+	if c.cameras == nil {
+		c.cameras = map[uint8]types.CameraProfile{}
 	}
+	c.cameras[cameraID] = c.cameras[cameraID].Merge(p)
 
-	if dk, ok := d[defaultKey]; ok {
-		return dk
+	for _, field := range p.Fields() {
+		c.setSource(cameraKey(cameraID, field), source)
 	}
+}
 
-	return defaultValue
+// cameraKey returns the key camera profile values are tracked with
+func cameraKey(cameraID uint8, field string) string {
+	return fmt.Sprintf("cameras.%d.%s", cameraID, field)
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -18,16 +19,90 @@ func TestConfigFromYAML(t *testing.T) {
 	r.NoError(err)
 
 	r.Equal(&config{
+		cameras: map[uint8]types.CameraProfile{
+			0: {
+				Timezone:        types.PtrString("Europe/Paris"),
+				ClockCorrection: &types.ClockCorrection{Offset: types.PtrDuration(-2 * time.Minute)},
+				Artist:          types.PtrString("John Doe"),
+			},
+			9: {
+				Make:         types.PtrString("Canon"),
+				Model:        types.PtrString("Canon EOS 1V"),
+				SerialNumber: types.PtrString("XXXYYYZZZ"),
+				Timezone:     types.PtrString("Europe/Moscow"),
+				ClockCorrection: &types.ClockCorrection{References: []types.ClockReference{
+					{
+						Camera: time.Date(2019, 10, 1, 12, 0, 0, 0, time.FixedZone("", 3*3600)),
+						Actual: time.Date(2019, 10, 1, 12, 1, 0, 0, time.FixedZone("", 3*3600)),
+					},
+					{
+						Camera: time.Date(2019, 11, 1, 12, 0, 0, 0, time.FixedZone("", 3*3600)),
+						Actual: time.Date(2019, 11, 1, 12, 3, 0, 0, time.FixedZone("", 3*3600)),
+					},
+				}},
+				Owner: types.PtrString("John Doe"),
+				Lens:  types.PtrString("EF50mm f/1.4 USM"),
+			},
+		},
 		copyright:       types.PtrString("Test Copyright Value"),
 		exiftoolBinary:  "/usr/local/bin/exiftool",
 		filenamePattern: "XXX_${cameraID:02d}${filmID:03d}${frameNo:05d}.dng",
 		fileSource:      func() *types.FileSource { t := types.FileSourceFilmScanner; return &t }(),
-		make:            map[uint8]string{9: "Canon"},
-		model:           map[uint8]string{9: "Canon EOS 1V"},
-		serialNumber:    map[uint8]string{9: "XXXYYYZZZ"},
+		filmStocks: map[string]types.FilmStock{
+			"portra400": {
+				Manufacturer: "Kodak",
+				Name:         "Portra 400",
+				BoxSpeed:     400,
+				Process:      types.FilmProcessC41,
+				Format:       "135",
+				TitlePattern: "(?i)portra",
+			},
+		},
+		stockAssigns: map[string]string{"09-139": "portra400"},
+		geotag:       types.PtrString("/home/user/track.gpx"),
+		geotagMaxGap: 10 * time.Minute,
+		geotagOffset: 30 * time.Second,
+		lenses: []types.Lens{
+			{
+				Name:         "EF24-70mm f/2.8L USM",
+				Make:         "Canon",
+				FocalLength:  types.Range{Min: 24, Max: 70},
+				MaxAperture:  types.Range{Min: 2.8, Max: 2.8},
+				SerialNumber: "123456",
+				Owned: []types.DateRange{
+					{From: types.PtrTime(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))},
+				},
+			},
+			{
+				Name:        "EF50mm f/1.4 USM",
+				Make:        "Canon",
+				FocalLength: types.Range{Min: 50, Max: 50},
+				MaxAperture: types.Range{Min: 1.4, Max: 1.4},
+			},
+		},
+		sidecarDir:      "/home/user/scans",
 		setDigitized:    true,
 		timestampFormat: types.TimestampFormatEU,
-		timezone:        map[uint8]string{0: "Europe/Paris", 9: "Europe/Moscow"},
+		tzOverrides: []types.TimezoneOverride{
+			{
+				CameraID: types.PtrUint8(9),
+				FilmID:   types.PtrInt64(139),
+				Timezone: "Asia/Tokyo",
+			},
+			{
+				From:     types.PtrTime(time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)),
+				To:       types.PtrTime(time.Date(2019, 10, 15, 0, 0, 0, 0, time.UTC)),
+				Timezone: "America/New_York",
+			},
+		},
+		sources: sourcesOf("./testdata/config.yaml",
+			"cameras.0.timezone", "cameras.0.clock-correction", "cameras.0.artist",
+			"cameras.9.make", "cameras.9.model", "cameras.9.serial-number", "cameras.9.timezone",
+			"cameras.9.clock-correction", "cameras.9.owner", "cameras.9.lens",
+			"copyright", "exiftool-binary", "filename-pattern", "file-source", "film-stocks",
+			"film-stock-assignments", "geotag", "geotag-max-gap", "geotag-offset", "lenses", "sidecar-dir",
+			"set-digitized", "timestamp-format", "timezone-overrides",
+		),
 	}, cfg)
 }
 
@@ -38,17 +113,21 @@ func TestConfigFromFlags(t *testing.T) {
 
 	m.On("GetDisplayHelp").Return(true).Twice()
 	m.On("GetDisplayVersion").Return(true).Twice()
+	m.On("GetClockOffset").Return(map[uint8]time.Duration{0: time.Hour}).Twice()
 	m.On("GetCopyright").Return("test copyright from flags").Twice()
 	m.On("GetExiftoolBinary").Return("/opt/local/bin/exiftool").Twice()
 	m.On("GetFileSource").Return(types.FileSourceDigitalCamera).Twice()
 	m.On("GetFilenamePattern").Return("blah").Twice()
 	m.On("GetGeotag").Return("blah.gpx").Twice()
-	m.On("GetMake").Return("blah vendor").Twice()
-	m.On("GetModel").Return("blah model").Twice()
-	m.On("GetSerialNumber").Return("ZZZZZZZZZ").Twice()
+	m.On("GetGeotagMaxGap").Return(5 * time.Minute).Twice()
+	m.On("GetGeotagOffset").Return(-90 * time.Second).Twice()
+	m.On("GetMake").Return(map[uint8]string{0: "blah vendor"}).Twice()
+	m.On("GetModel").Return(map[uint8]string{0: "blah model"}).Twice()
+	m.On("GetSerialNumber").Return(map[uint8]string{0: "ZZZZZZZZZ"}).Twice()
+	m.On("GetSidecarDir").Return("/scans").Twice()
 	m.On("GetSetDigitized").Return(true).Twice()
 	m.On("GetTimestampFormat").Return(types.TimestampFormatEU).Twice()
-	m.On("GetTimezone").Return("Europe/Berlin").Twice()
+	m.On("GetTimezone").Return(map[uint8]string{0: "Europe/Berlin"}).Twice()
 
 	cfg := NewDefaultConfig()
 	err := cfg.FillFromYaml("./testdata/config.yaml")
@@ -58,19 +137,116 @@ func TestConfigFromFlags(t *testing.T) {
 	r.NoError(err)
 
 	r.Equal(&config{
-		displayHelp:     true,
-		displayVersion:  true,
+		displayHelp:    true,
+		displayVersion: true,
+		cameras: map[uint8]types.CameraProfile{
+			0: {
+				Make:            types.PtrString("blah vendor"),
+				Model:           types.PtrString("blah model"),
+				SerialNumber:    types.PtrString("ZZZZZZZZZ"),
+				Timezone:        types.PtrString("Europe/Berlin"),
+				ClockCorrection: &types.ClockCorrection{Offset: types.PtrDuration(time.Hour)},
+				Artist:          types.PtrString("John Doe"),
+			},
+			9: {
+				Make:         types.PtrString("Canon"),
+				Model:        types.PtrString("Canon EOS 1V"),
+				SerialNumber: types.PtrString("XXXYYYZZZ"),
+				Timezone:     types.PtrString("Europe/Moscow"),
+				ClockCorrection: &types.ClockCorrection{References: []types.ClockReference{
+					{
+						Camera: time.Date(2019, 10, 1, 12, 0, 0, 0, time.FixedZone("", 3*3600)),
+						Actual: time.Date(2019, 10, 1, 12, 1, 0, 0, time.FixedZone("", 3*3600)),
+					},
+					{
+						Camera: time.Date(2019, 11, 1, 12, 0, 0, 0, time.FixedZone("", 3*3600)),
+						Actual: time.Date(2019, 11, 1, 12, 3, 0, 0, time.FixedZone("", 3*3600)),
+					},
+				}},
+				Owner: types.PtrString("John Doe"),
+				Lens:  types.PtrString("EF50mm f/1.4 USM"),
+			},
+		},
 		copyright:       types.PtrString("test copyright from flags"),
 		exiftoolBinary:  "/opt/local/bin/exiftool",
 		filenamePattern: "blah",
 		fileSource:      types.PtrFileSource(types.FileSourceDigitalCamera),
-		geotag:          types.PtrString("blah.gpx"),
-		make:            map[uint8]string{0: "blah vendor"},
-		model:           map[uint8]string{0: "blah model"},
-		serialNumber:    map[uint8]string{0: "ZZZZZZZZZ"},
+		filmStocks: map[string]types.FilmStock{
+			"portra400": {
+				Manufacturer: "Kodak",
+				Name:         "Portra 400",
+				BoxSpeed:     400,
+				Process:      types.FilmProcessC41,
+				Format:       "135",
+				TitlePattern: "(?i)portra",
+			},
+		},
+		stockAssigns: map[string]string{"09-139": "portra400"},
+		geotag:       types.PtrString("blah.gpx"),
+		geotagMaxGap: 5 * time.Minute,
+		geotagOffset: -90 * time.Second,
+		lenses: []types.Lens{
+			{
+				Name:         "EF24-70mm f/2.8L USM",
+				Make:         "Canon",
+				FocalLength:  types.Range{Min: 24, Max: 70},
+				MaxAperture:  types.Range{Min: 2.8, Max: 2.8},
+				SerialNumber: "123456",
+				Owned: []types.DateRange{
+					{From: types.PtrTime(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))},
+				},
+			},
+			{
+				Name:        "EF50mm f/1.4 USM",
+				Make:        "Canon",
+				FocalLength: types.Range{Min: 50, Max: 50},
+				MaxAperture: types.Range{Min: 1.4, Max: 1.4},
+			},
+		},
+		sidecarDir:      "/scans",
 		setDigitized:    true,
 		timestampFormat: types.TimestampFormatEU,
-		timezone:        map[uint8]string{0: "Europe/Berlin"},
+		tzOverrides: []types.TimezoneOverride{
+			{
+				CameraID: types.PtrUint8(9),
+				FilmID:   types.PtrInt64(139),
+				Timezone: "Asia/Tokyo",
+			},
+			{
+				From:     types.PtrTime(time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)),
+				To:       types.PtrTime(time.Date(2019, 10, 15, 0, 0, 0, 0, time.UTC)),
+				Timezone: "America/New_York",
+			},
+		},
+		sources: map[string]string{
+			"cameras.0.make":             "flag -make",
+			"cameras.0.model":            "flag -model",
+			"cameras.0.serial-number":    "flag -serial-number",
+			"cameras.0.timezone":         "flag -timezone",
+			"cameras.0.clock-correction": "flag -clock-offset",
+			"cameras.0.artist":           "./testdata/config.yaml",
+			"cameras.9.make":             "./testdata/config.yaml",
+			"cameras.9.model":            "./testdata/config.yaml",
+			"cameras.9.serial-number":    "./testdata/config.yaml",
+			"cameras.9.timezone":         "./testdata/config.yaml",
+			"cameras.9.clock-correction": "./testdata/config.yaml",
+			"cameras.9.owner":            "./testdata/config.yaml",
+			"cameras.9.lens":             "./testdata/config.yaml",
+			"copyright":                  "flag -copyright",
+			"exiftool-binary":            "flag -exiftool-binary",
+			"filename-pattern":           "flag -filename-pattern",
+			"file-source":                "flag -file-source",
+			"film-stocks":                "./testdata/config.yaml",
+			"film-stock-assignments":     "./testdata/config.yaml",
+			"geotag":                     "flag -geotag",
+			"geotag-max-gap":             "flag -geotag-max-gap",
+			"geotag-offset":              "flag -geotag-offset",
+			"lenses":                     "./testdata/config.yaml",
+			"sidecar-dir":                "flag -sidecar-dir",
+			"set-digitized":              "flag -set-digitized",
+			"timestamp-format":           "flag -timestamp-format",
+			"timezone-overrides":         "./testdata/config.yaml",
+		},
 	}, cfg)
 }
 
@@ -81,17 +257,21 @@ func TestGetters(t *testing.T) {
 
 	m.On("GetDisplayHelp").Return(true).Twice()
 	m.On("GetDisplayVersion").Return(true).Twice()
+	m.On("GetClockOffset").Return(map[uint8]time.Duration{0: time.Hour}).Twice()
 	m.On("GetCopyright").Return("test copyright").Twice()
 	m.On("GetExiftoolBinary").Return("/opt/local/bin/exiftool").Twice()
 	m.On("GetFileSource").Return(types.FileSourceDigitalCamera).Twice()
 	m.On("GetFilenamePattern").Return("blah").Twice()
 	m.On("GetGeotag").Return("blah.gpx").Twice()
-	m.On("GetMake").Return("Blah Vendor").Twice()
-	m.On("GetModel").Return("Blah Model").Twice()
-	m.On("GetSerialNumber").Return("ZZZZZZZZZ").Twice()
+	m.On("GetGeotagMaxGap").Return(5 * time.Minute).Twice()
+	m.On("GetGeotagOffset").Return(-90 * time.Second).Twice()
+	m.On("GetMake").Return(map[uint8]string{0: "Blah Vendor"}).Twice()
+	m.On("GetModel").Return(map[uint8]string{0: "Blah Model"}).Twice()
+	m.On("GetSerialNumber").Return(map[uint8]string{0: "ZZZZZZZZZ"}).Twice()
+	m.On("GetSidecarDir").Return("/scans").Twice()
 	m.On("GetSetDigitized").Return(true).Twice()
 	m.On("GetTimestampFormat").Return(types.TimestampFormatEU).Twice()
-	m.On("GetTimezone").Return("Europe/Berlin").Twice()
+	m.On("GetTimezone").Return(map[uint8]string{0: "Europe/Berlin"}).Twice()
 
 	cfg := NewDefaultConfig()
 	err := cfg.FillFromYaml("./testdata/config.yaml")
@@ -102,60 +282,187 @@ func TestGetters(t *testing.T) {
 
 	r.Equal(true, cfg.GetDisplayHelp())
 	r.Equal(true, cfg.GetDisplayVersion())
+	r.Equal(&types.ClockCorrection{Offset: types.PtrDuration(time.Hour)}, cfg.GetCameraProfile(1).ClockCorrection)
+	r.Equal(2, len(cfg.GetCameraProfile(9).ClockCorrection.References))
 	r.Equal(types.PtrString("test copyright"), cfg.GetCopyright())
 	r.Equal("/opt/local/bin/exiftool", cfg.GetExiftoolBinary())
 	r.Equal(types.PtrFileSource(types.FileSourceDigitalCamera), cfg.GetFileSource())
 	r.Equal("blah", cfg.GetFilenamePattern())
 	r.Equal(types.PtrString("blah.gpx"), cfg.GetGeotag())
-	r.Equal(types.PtrString("Blah Vendor"), cfg.GetMakeByCameraID(0))
-	r.Equal(types.PtrString("Blah Model"), cfg.GetModelByCameraID(0))
-	r.Equal(types.PtrString("ZZZZZZZZZ"), cfg.GetSerialNumberByCameraID(0))
+	r.Equal(5*time.Minute, cfg.GetGeotagMaxGap())
+	r.Equal(-90*time.Second, cfg.GetGeotagOffset())
+	r.Equal(types.PtrString("Blah Vendor"), cfg.GetCameraProfile(0).Make)
+	r.Equal(types.PtrString("Blah Model"), cfg.GetCameraProfile(0).Model)
+	r.Equal(types.PtrString("ZZZZZZZZZ"), cfg.GetCameraProfile(0).SerialNumber)
+	r.Equal("/scans", cfg.GetSidecarDir())
 	r.Equal(true, cfg.GetSetDigitized())
 	r.Equal(types.PtrTimestampFormat(types.TimestampFormatEU), cfg.GetTimestampFormat())
-	r.Equal("Europe/Berlin", cfg.GetTimezoneByCameraID(0))
+	r.Equal(types.CameraProfile{
+		Make:            types.PtrString("Canon"),
+		Model:           types.PtrString("Canon EOS 1V"),
+		SerialNumber:    types.PtrString("XXXYYYZZZ"),
+		Timezone:        types.PtrString("Europe/Moscow"),
+		ClockCorrection: cfg.GetCameraProfile(9).ClockCorrection,
+		Artist:          types.PtrString("John Doe"),
+		Owner:           types.PtrString("John Doe"),
+		Lens:            types.PtrString("EF50mm f/1.4 USM"),
+	}, cfg.GetCameraProfile(9))
+	r.Equal(types.PtrString("Europe/Berlin"), cfg.GetCameraProfile(0).Timezone)
+	r.Equal(types.PtrString("Europe/Berlin"), cfg.GetCameraProfile(1).Timezone)
+	r.Empty(cfg.GetWarnings())
+}
+
+func sourcesOf(source string, keys ...string) map[string]string {
+	m := map[string]string{}
+	for _, k := range keys {
+		m[k] = source
+	}
+	return m
 }
 
-func TestGetOrDefault(t *testing.T) {
+func TestGetCameraProfileWithDefaults(t *testing.T) {
 	r := require.New(t)
 
+	cfg := NewDefaultConfig()
+	cfg.(*config).cameras = map[uint8]types.CameraProfile{
+		0: {Make: types.PtrString("Canon"), Artist: types.PtrString("John Doe")},
+		9: {Model: types.PtrString("Nikon F100")},
+	}
+	defaults := types.CameraProfile{Make: types.PtrString("Nikon"), Model: types.PtrString("Nikon F6")}
+
+	r.Equal(types.CameraProfile{
+		Make:   types.PtrString("Nikon"),
+		Model:  types.PtrString("Nikon F6"),
+		Artist: types.PtrString("John Doe"),
+	}, cfg.GetCameraProfileWithDefaults(0, defaults))
+	r.Equal(types.CameraProfile{
+		Make:   types.PtrString("Nikon"),
+		Model:  types.PtrString("Nikon F100"),
+		Artist: types.PtrString("John Doe"),
+	}, cfg.GetCameraProfileWithDefaults(9, defaults))
+	r.Equal(types.CameraProfile{
+		Make:   types.PtrString("Canon"),
+		Artist: types.PtrString("John Doe"),
+	}, cfg.GetCameraProfileWithDefaults(1, types.CameraProfile{}))
+}
+
+func TestCameraValuesPrecedence(t *testing.T) {
+	r := require.New(t)
+
+	m := flagM.New()
+	m.On("GetDisplayHelp").Return(false).Once()
+	m.On("GetDisplayVersion").Return(false).Once()
+	m.On("GetClockOffset").Return(map[uint8]time.Duration{3: time.Minute}).Twice()
+	m.On("GetCopyright").Return("").Once()
+	m.On("GetExiftoolBinary").Return("").Once()
+	m.On("GetFileSource").Return(types.FileSource("")).Once()
+	m.On("GetFilenamePattern").Return("").Once()
+	m.On("GetGeotag").Return("").Once()
+	m.On("GetGeotagMaxGap").Return(time.Duration(0)).Once()
+	m.On("GetGeotagOffset").Return(time.Duration(0)).Once()
+	m.On("GetMake").Return(map[uint8]string{0: "Canon Inc."}).Twice()
+	m.On("GetModel").Return(map[uint8]string(nil)).Once()
+	m.On("GetSerialNumber").Return(map[uint8]string{3: "AAA"}).Twice()
+	m.On("GetSidecarDir").Return("").Once()
+	m.On("GetSetDigitized").Return(false).Once()
+	m.On("GetTimestampFormat").Return(types.TimestampFormat("")).Once()
+	m.On("GetTimezone").Return(map[uint8]types.Timezone{3: "Europe/Berlin"}).Twice()
+
+	cfg := NewDefaultConfig()
+	r.NoError(cfg.FillFromYaml("./testdata/config.yaml"))
+	r.NoError(cfg.FillFromEnv([]string{"TAGGER_TIMEZONE=9=Asia/Tokyo"}))
+	r.NoError(cfg.FillFromFlags(m))
+
 	type testCase struct {
-		name         string
-		data         map[uint8]string
-		key          uint8
-		defaultKey   uint8
-		defaultValue string
-		expResult    string
+		name      string
+		value     func() interface{}
+		expResult interface{}
 	}
 
 	tcs := []testCase{
 		{
-			name:         "get by key",
-			data:         map[uint8]string{0: "blah", 1: "blah2", 2: "blah3"},
-			key:          1,
-			defaultKey:   2,
-			defaultValue: "default value",
-			expResult:    "blah2",
+			name:      "timezone for camera ID without own value from YAML default",
+			value:     func() interface{} { return *cfg.GetCameraProfile(5).Timezone },
+			expResult: "Europe/Paris",
 		},
 		{
-			name:         "get by default key",
-			data:         map[uint8]string{0: "blah", 1: "blah2", 2: "blah3"},
-			key:          3, // not existent
-			defaultKey:   2,
-			defaultValue: "default value",
-			expResult:    "blah3",
+			name:      "timezone from environment overrides YAML for camera 9",
+			value:     func() interface{} { return *cfg.GetCameraProfile(9).Timezone },
+			expResult: "Asia/Tokyo",
 		},
 		{
-			name:         "get default value",
-			data:         map[uint8]string{},
-			key:          3, // not existent
-			defaultKey:   2, // not existent
-			defaultValue: "default value",
-			expResult:    "default value",
+			name:      "timezone from flag for camera 3",
+			value:     func() interface{} { return *cfg.GetCameraProfile(3).Timezone },
+			expResult: "Europe/Berlin",
+		},
+		{
+			name:      "make from flag for camera without own value",
+			value:     func() interface{} { return cfg.GetCameraProfile(3).Make },
+			expResult: types.PtrString("Canon Inc."),
+		},
+		{
+			name:      "make from YAML for camera 9 is not wiped by flag for camera 0",
+			value:     func() interface{} { return cfg.GetCameraProfile(9).Make },
+			expResult: types.PtrString("Canon"),
+		},
+		{
+			name:      "serial number from YAML for camera 9",
+			value:     func() interface{} { return cfg.GetCameraProfile(9).SerialNumber },
+			expResult: types.PtrString("XXXYYYZZZ"),
+		},
+		{
+			name:      "serial number from flag for camera 3",
+			value:     func() interface{} { return cfg.GetCameraProfile(3).SerialNumber },
+			expResult: types.PtrString("AAA"),
+		},
+		{
+			name:      "clock offset from flag for camera 3",
+			value:     func() interface{} { return cfg.GetCameraProfile(3).ClockCorrection },
+			expResult: &types.ClockCorrection{Offset: types.PtrDuration(time.Minute)},
+		},
+		{
+			name:      "clock offset from YAML default for camera without own value",
+			value:     func() interface{} { return cfg.GetCameraProfile(5).ClockCorrection },
+			expResult: &types.ClockCorrection{Offset: types.PtrDuration(-2 * time.Minute)},
 		},
 	}
 
 	for _, tc := range tcs {
-		v := getOrDefault(tc.data, tc.key, tc.defaultKey, tc.defaultValue)
-		r.Equalf(tc.expResult, v, tc.name)
+		r.Equalf(tc.expResult, tc.value(), tc.name)
 	}
+
+	sources := cfg.(*config).sources
+	r.Equal("./testdata/config.yaml", sources["cameras.0.timezone"])
+	r.Equal("env TAGGER_TIMEZONE", sources["cameras.9.timezone"])
+	r.Equal("flag -timezone", sources["cameras.3.timezone"])
+}
+
+func TestConfigFromLegacyYAML(t *testing.T) {
+	r := require.New(t)
+
+	cfg := NewDefaultConfig()
+	err := cfg.FillFromYaml("./testdata/legacy.yaml")
+	r.NoError(err)
+
+	r.Equal(types.CameraProfile{
+		Timezone:        types.PtrString("Europe/Paris"),
+		ClockCorrection: &types.ClockCorrection{Offset: types.PtrDuration(-2 * time.Minute)},
+	}, cfg.GetCameraProfile(0))
+
+	r.Equal(types.CameraProfile{
+		Make:            types.PtrString("Canon"),
+		Model:           types.PtrString("Canon EOS-1V HS"),
+		SerialNumber:    types.PtrString("XXXYYYZZZ"),
+		Timezone:        types.PtrString("Europe/Moscow"),
+		ClockCorrection: cfg.GetCameraProfile(9).ClockCorrection,
+	}, cfg.GetCameraProfile(9))
+	r.Len(cfg.GetCameraProfile(9).ClockCorrection.References, 2)
+
+	r.Equal([]string{
+		"./testdata/legacy.yaml: `clock-correction` is deprecated, use `cameras.<camera ID>.clock-correction` instead",
+		"./testdata/legacy.yaml: `make` is deprecated, use `cameras.<camera ID>.make` instead",
+		"./testdata/legacy.yaml: `model` is deprecated, use `cameras.<camera ID>.model` instead",
+		"./testdata/legacy.yaml: `serial-number` is deprecated, use `cameras.<camera ID>.serial-number` instead",
+		"./testdata/legacy.yaml: `timezone` is deprecated, use `cameras.<camera ID>.timezone` instead",
+	}, cfg.GetWarnings())
 }
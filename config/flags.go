@@ -10,29 +10,90 @@ import (
 	"github.com/teran/eos-1v-tagger/types"
 )
 
+// FlagGroup is a set of flags registered for the command
+type FlagGroup uint
+
+const (
+	// FlagsInput are the flags of reading and correcting films
+	FlagsInput FlagGroup = 1 << iota
+
+	// FlagsTagging are the flags of tags written and exiftool
+	FlagsTagging
+
+	// FlagsExport is `-format` flag of export command
+	FlagsExport
+
+	// FlagsLegacy are the flags kept for invocation without command
+	FlagsLegacy
+
+	// FlagsInspect is `-columns` flag of inspect command
+	FlagsInspect
+
+	// FlagsStats is `-json` flag of stats command
+	FlagsStats
+
+	// FlagsContactSheet are the flags of contactsheet command
+	FlagsContactSheet
+)
+
+// Command describes the command flags are parsed for
+type Command struct {
+	Name string
+
+	// Usage is the arguments synopsis printed after the command name
+	Usage string
+
+	Description string
+
+	Flags FlagGroup
+}
+
 type flags struct {
+	fs *flag.FlagSet
+
 	displayHelp     bool
+	configPath      string
+	showConfig      bool
+	export          types.DataFormat
+	format          types.ExportFormat
+	columns         string
+	json            bool
+	output          string
+	thumbnailSize   int
+	inputFormat     types.InputFormat
+	clockOffset     types.CameraDurations
 	copyright       string
 	exiftoolBinary  string
 	filenamePattern string
 	fileSource      types.FileSource
 	geotag          string
-	make            string
-	model           string
-	serialNumber    string
+	geotagMaxGap    time.Duration
+	geotagOffset    time.Duration
+	make            types.CameraValues
+	model           types.CameraValues
+	serialNumber    types.CameraValues
+	sidecarDir      string
 	setDigitized    bool
 	timestampFormat types.TimestampFormat
-	timezone        string
+	timezone        types.CameraValues
 	displayVersion  bool
 
 	usagePrefix string
 	usageSuffix string
 }
 
-// NewFlags ...
-func NewFlags(binary, version, timestamp string) types.Flags {
+// NewFlags parses command line arguments of the command, command name is
+// omitted for invocation without command
+func NewFlags(binary string, cmd Command, version, timestamp string, args []string) types.Flags {
+	name := binary
+	if cmd.Name != "" {
+		name += " " + cmd.Name
+	}
+
 	f := flags{
-		usagePrefix: fmt.Sprintf("Usage: %v [OPTIONS] file.csv\n\nOptions:\n", binary),
+		fs:          flag.NewFlagSet(name, flag.ExitOnError),
+		format:      types.ExportFormatJSON,
+		usagePrefix: fmt.Sprintf("Usage: %s %s\n\n%s\n\nOptions:\n", name, cmd.Usage, cmd.Description),
 		usageSuffix: fmt.Sprintf("Version: %s, build with %s at %s\n", version, runtime.Version(), func() string {
 			tsI, err := strconv.ParseInt(timestamp, 10, 64)
 			if err != nil {
@@ -42,34 +103,70 @@ func NewFlags(binary, version, timestamp string) types.Flags {
 		}()),
 	}
 
-	flag.Usage = func() {
+	fs := f.fs
+	fs.Usage = func() {
 		fmt.Print(f.usagePrefix)
-		flag.PrintDefaults()
+		fs.PrintDefaults()
 		fmt.Print("\n")
 		fmt.Print(f.usageSuffix)
 	}
 
-	flag.BoolVar(&f.displayHelp, "help", false, "display help message")
-	flag.StringVar(&f.copyright, "copyright", "", "copyright notice for images")
-	flag.StringVar(&f.exiftoolBinary, "exiftool-binary", "exiftool", "path to exiftool binary")
-	flag.StringVar(&f.filenamePattern, "filename-pattern", `FILM_${cameraID:02d}${filmID:03d}${frameNo:05d}.dng`, "filename pattern for generate exiftool command. Available variables: frameNo, cameraID, filmID. More details are available in README.")
-	flag.Var(&f.fileSource, "file-source", "adds file source EXIF tag. Available options: 'Film Scanner', 'Reflection Print Scanner', 'Digital Camera'")
-	flag.StringVar(&f.geotag, "geotag", "", "GPS track log file to set location data, supported formats are the ones supported by exiftool. Please refer to exiftool docs for details.")
-	flag.StringVar(&f.make, "make", "", "Make tag value. NOTE: it will overwrite the value set by your film scanner software")
-	flag.StringVar(&f.model, "model", "", "Model tag value. NOTE: it will overwrite the value set by your film scanner software")
-	flag.StringVar(&f.serialNumber, "serial-number", "", "SerialNumber tag value. NOTE: it will overwrite the value set by your film scanner software")
-	flag.BoolVar(&f.setDigitized, "set-digitized", false, "set DateTimeDigitized from CreateDate field")
-	flag.Var(&f.timestampFormat, "timestamp-format", "the timestamp format in the locale your're using on the system with ES-E1 software. Allowed values: 'US', 'EU'")
-	flag.StringVar(&f.timezone, "timezone", "", "location or timezone name used while setting time on EOS 1V, will be used for proper scans timestamping (example: 'Europe/Moscow'; default: 'UTC')")
-	flag.BoolVar(&f.displayVersion, "version", false, "show program version")
-
-	flag.Parse()
+	fs.BoolVar(&f.displayHelp, "help", false, "display help message")
+	fs.StringVar(&f.configPath, "config", "", "configuration file to use instead of the discovered ones (~/.tagger/config.yaml, $XDG_CONFIG_HOME/tagger/config.yaml, .tagger.yaml in the working directory or its parents)")
+	fs.BoolVar(&f.showConfig, "show-config", false, "print effective configuration along with the source of every value and exit")
+	fs.BoolVar(&f.displayVersion, "version", false, "show program version")
+
+	if cmd.Flags&FlagsLegacy != 0 {
+		fs.Var(&f.export, "export", "print parsed films as JSON or YAML document instead of exiftool commands, same as `export` command. Allowed values: 'json', 'yaml'")
+	}
+
+	if cmd.Flags&FlagsExport != 0 {
+		fs.Var(&f.format, "format", "output format. Allowed values: 'json', 'yaml', 'csv'")
+	}
+
+	if cmd.Flags&FlagsInspect != 0 {
+		fs.StringVar(&f.columns, "columns", "", "comma separated list of frame table columns. Available columns: no, timestamp, tv, av, iso, ec, fec, focal, lens, shooting, metering, flash, af, advance, multiple, position, remarks (default: 'no,timestamp,tv,av,iso,ec,focal,shooting,metering,remarks')")
+	}
+
+	if cmd.Flags&FlagsStats != 0 {
+		fs.BoolVar(&f.json, "json", false, "print report as JSON")
+	}
+
+	if cmd.Flags&FlagsContactSheet != 0 {
+		fs.StringVar(&f.output, "output", "contactsheet", "directory to write contact sheets and thumbnails to")
+		fs.IntVar(&f.thumbnailSize, "thumbnail-size", 0, "maximum width and height of thumbnails in pixels. Thumbnails are made of JPEG and TIFF scans, for other formats (e.g. DNG) JPEG or TIFF file of the same name is looked up (default: 320)")
+	}
+
+	if cmd.Flags&FlagsInput != 0 {
+		fs.Var(&f.inputFormat, "input-format", "format of input files: ES-E1 CSV export, Nikon data memory CSV export, JSON/YAML document produced by -export, hand-written film log (.filmlog) or Exif Notes app JSON export. Allowed values: 'auto', 'csv', 'nikon', 'json', 'yaml', 'log', 'exifnotes' (default: 'auto', detected by file content)")
+		fs.Var(&f.clockOffset, "clock-offset", "fixed camera clock correction added to every timestamp recorded by camera, could be prefixed with camera ID and set several times (example: '-1h', '9=2m30s')")
+		fs.StringVar(&f.sidecarDir, "sidecar-dir", "", "directory to look up per-film metadata override files (film-<camera ID>-<film ID>.yaml) in (default: current directory)")
+		fs.Var(&f.timestampFormat, "timestamp-format", "the timestamp format in the locale your're using on the system with ES-E1 software. Allowed values: 'US', 'EU'")
+		fs.Var(&f.timezone, "timezone", "location or timezone name used while setting time on EOS 1V, will be used for proper scans timestamping, could be prefixed with camera ID and set several times (example: 'Europe/Moscow', '9=Europe/Moscow'; default: 'UTC')")
+	}
+
+	if cmd.Flags&FlagsTagging != 0 {
+		fs.StringVar(&f.copyright, "copyright", "", "copyright notice for images")
+		fs.StringVar(&f.exiftoolBinary, "exiftool-binary", "", "path to exiftool binary (default: 'exiftool')")
+		fs.StringVar(&f.filenamePattern, "filename-pattern", "", "filename pattern for generate exiftool command. Available variables: frameNo, cameraID, filmID, stock, process, boxSpeed. More details are available in README. (default: 'FILM_${cameraID:02d}${filmID:03d}${frameNo:05d}.dng')")
+		fs.Var(&f.fileSource, "file-source", "adds file source EXIF tag. Available options: 'Film Scanner', 'Reflection Print Scanner', 'Digital Camera'")
+		fs.StringVar(&f.geotag, "geotag", "", "GPS track log file to set location data, supported formats are GPX, KML and NMEA")
+		fs.DurationVar(&f.geotagMaxGap, "geotag-max-gap", 0, "maximum time between two track log points to interpolate position between (default: 30m)")
+		fs.DurationVar(&f.geotagOffset, "geotag-offset", 0, "time offset added to frame timestamps before track log lookup (example: '-1m30s')")
+		fs.Var(&f.make, "make", "Make tag value, could be prefixed with camera ID and set several times (example: 'Canon', '9=Canon'). NOTE: it will overwrite the value set by your film scanner software")
+		fs.Var(&f.model, "model", "Model tag value, could be prefixed with camera ID and set several times. NOTE: it will overwrite the value set by your film scanner software")
+		fs.Var(&f.serialNumber, "serial-number", "SerialNumber tag value, could be prefixed with camera ID and set several times. NOTE: it will overwrite the value set by your film scanner software")
+		fs.BoolVar(&f.setDigitized, "set-digitized", false, "set DateTimeDigitized from CreateDate field")
+	}
+
+	// ExitOnError is set so Parse never returns an error
+	_ = fs.Parse(args)
 
 	return &f
 }
 
 func (f *flags) PrintUsageString() {
-	flag.Usage()
+	f.fs.Usage()
 }
 
 func (f *flags) PrintVersionString() {
@@ -84,6 +181,46 @@ func (f *flags) GetDisplayVersion() bool {
 	return f.displayVersion
 }
 
+func (f *flags) GetConfigPath() string {
+	return f.configPath
+}
+
+func (f *flags) GetShowConfig() bool {
+	return f.showConfig
+}
+
+func (f *flags) GetExport() types.DataFormat {
+	return f.export
+}
+
+func (f *flags) GetFormat() types.ExportFormat {
+	return f.format
+}
+
+func (f *flags) GetColumns() string {
+	return f.columns
+}
+
+func (f *flags) GetJSON() bool {
+	return f.json
+}
+
+func (f *flags) GetOutput() string {
+	return f.output
+}
+
+func (f *flags) GetThumbnailSize() int {
+	return f.thumbnailSize
+}
+
+func (f *flags) GetInputFormat() types.InputFormat {
+	return f.inputFormat
+}
+
+func (f *flags) GetClockOffset() map[uint8]time.Duration {
+	return f.clockOffset
+}
+
 func (f *flags) GetCopyright() string {
 	return f.copyright
 }
@@ -104,18 +241,30 @@ func (f *flags) GetGeotag() string {
 	return f.geotag
 }
 
-func (f *flags) GetMake() string {
+func (f *flags) GetGeotagMaxGap() time.Duration {
+	return f.geotagMaxGap
+}
+
+func (f *flags) GetGeotagOffset() time.Duration {
+	return f.geotagOffset
+}
+
+func (f *flags) GetMake() map[uint8]string {
 	return f.make
 }
 
-func (f *flags) GetModel() string {
+func (f *flags) GetModel() map[uint8]string {
 	return f.model
 }
 
-func (f *flags) GetSerialNumber() string {
+func (f *flags) GetSerialNumber() map[uint8]string {
 	return f.serialNumber
 }
 
+func (f *flags) GetSidecarDir() string {
+	return f.sidecarDir
+}
+
 func (f *flags) GetSetDigitized() bool {
 	return f.setDigitized
 }
@@ -124,10 +273,10 @@ func (f *flags) GetTimestampFormat() types.TimestampFormat {
 	return f.timestampFormat
 }
 
-func (f *flags) GetTimezone() types.Timezone {
+func (f *flags) GetTimezone() map[uint8]types.Timezone {
 	return f.timezone
 }
 
-func (f *flags) GetCSVPath() string {
-	return flag.Arg(0)
+func (f *flags) GetInputPaths() []string {
+	return f.fs.Args()
 }
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+func TestNewFlags(t *testing.T) {
+	r := require.New(t)
+
+	f := NewFlags("tagger", Command{
+		Name:  "export",
+		Flags: FlagsInput | FlagsExport,
+	}, "1.0", "0", []string{"-format", "csv", "-timezone", "9=Europe/Moscow", "-input-format", "nikon", "a.csv", "b.csv"})
+
+	r.Equal(types.ExportFormatCSV, f.GetFormat())
+	r.Equal(map[uint8]types.Timezone{9: "Europe/Moscow"}, f.GetTimezone())
+	r.Equal(types.InputFormatNikon, f.GetInputFormat())
+	r.Equal([]string{"a.csv", "b.csv"}, f.GetInputPaths())
+	r.Equal(types.DataFormat(""), f.GetExport())
+
+	f = NewFlags("tagger", Command{
+		Name:  "tag",
+		Flags: FlagsInput | FlagsTagging,
+	}, "1.0", "0", []string{"-geotag-offset", "-1m", "-clock-offset", "1h", "film.json"})
+
+	r.Equal(types.ExportFormatJSON, f.GetFormat())
+	r.Equal(-time.Minute, f.GetGeotagOffset())
+	r.Equal(map[uint8]time.Duration{0: time.Hour}, f.GetClockOffset())
+	r.Equal([]string{"film.json"}, f.GetInputPaths())
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/teran/eos-1v-tagger/types"
+)
+
+const (
+	envPrefix = "TAGGER_"
+
+	projectConfigName = ".tagger.yaml"
+)
+
+// DiscoverFiles returns configuration files present on the system in the
+// order they should be applied, i.e. the latter ones take precedence:
+//
+//   - legacy ~/.tagger/config.yaml
+//   - $XDG_CONFIG_HOME/tagger/config.yaml (~/.config/tagger/config.yaml
+//     when XDG_CONFIG_HOME is not set)
+//   - .tagger.yaml in the working directory or the nearest of its parents
+func DiscoverFiles(wd, home, xdgConfigHome string) []string {
+	candidates := []string{}
+	if home != "" {
+		candidates = append(candidates, filepath.Join(home, ".tagger", "config.yaml"))
+		if xdgConfigHome == "" {
+			xdgConfigHome = filepath.Join(home, ".config")
+		}
+	}
+	if xdgConfigHome != "" {
+		candidates = append(candidates, filepath.Join(xdgConfigHome, "tagger", "config.yaml"))
+	}
+
+	files := []string{}
+	for _, c := range candidates {
+		if isFile(c) {
+			files = append(files, c)
+		}
+	}
+
+	if wd != "" {
+		dir := filepath.Clean(wd)
+		for {
+			p := filepath.Join(dir, projectConfigName)
+			if isFile(p) {
+				files = append(files, p)
+				break
+			}
+
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				break
+			}
+			dir = parent
+		}
+	}
+
+	return files
+}
+
+// FillFromEnv fills the config from TAGGER_* variables of environ. Variable
+// name is the upper-cased configuration key with dashes replaced by
+// underscores (e.g. TAGGER_EXIFTOOL_BINARY). Per-camera values are set the
+// same way as with flags (`Canon` or `9=Canon`), structured values are passed
+// as YAML (e.g. TAGGER_TIMEZONE='{0: UTC, 9: Europe/Moscow}'). Variables
+// not matching any configuration key are ignored with a warning.
+func (c *config) FillFromEnv(environ []string) error {
+	for _, kv := range environ {
+		if !strings.HasPrefix(kv, envPrefix) {
+			continue
+		}
+
+		idx := strings.IndexByte(kv, '=')
+		if idx < 0 {
+			continue
+		}
+		name, value := kv[:idx], kv[idx+1:]
+		key := strings.ToLower(strings.Replace(strings.TrimPrefix(name, envPrefix), "_", "-", -1))
+
+		doc, err := envDocument(key, value)
+		if err != nil {
+			return errors.Wrapf(err, "error handling environment variable %s", name)
+		}
+		if doc == nil {
+			c.warnings = append(c.warnings, fmt.Sprintf(
+				"env %s: unknown configuration key `%s`, ignored", name, key))
+			continue
+		}
+
+		var ycfg YamlConfig
+		if err := yaml.UnmarshalStrict(doc, &ycfg); err != nil {
+			return errors.Wrapf(err, "error parsing environment variable %s", name)
+		}
+
+		if err := c.fillFromYamlConfig(ycfg, "env "+name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// envDocument renders value as a YAML document for key according to the
+// type of the corresponding YamlConfig field, nil is returned for unknown
+// keys
+func envDocument(key, value string) ([]byte, error) {
+	t := reflect.TypeOf(YamlConfig{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if strings.Split(f.Tag.Get("yaml"), ",")[0] != key {
+			continue
+		}
+
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		switch {
+		case ft.Kind() == reflect.String:
+			return yaml.Marshal(map[string]string{key: value})
+		case ft.Kind() == reflect.Map && ft.Key().Kind() == reflect.Uint8 && ft.Elem().Kind() == reflect.String:
+			if strings.HasPrefix(strings.TrimSpace(value), "{") {
+				break
+			}
+			cameraID, v, err := types.ParseCameraValue(value)
+			if err != nil {
+				return nil, err
+			}
+			return yaml.Marshal(map[string]map[uint8]string{key: {cameraID: v}})
+		}
+
+		lines := strings.Split(value, "\n")
+		for i := range lines {
+			lines[i] = "  " + lines[i]
+		}
+		return []byte(key + ":\n" + strings.Join(lines, "\n") + "\n"), nil
+	}
+
+	return nil, nil
+}
+
+func isFile(path string) bool {
+	st, err := os.Stat(path)
+	return err == nil && st.Mode().IsRegular()
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	flagM "github.com/teran/eos-1v-tagger/config/mocks/flags"
+	"github.com/teran/eos-1v-tagger/types"
+)
+
+func TestDiscoverFiles(t *testing.T) {
+	r := require.New(t)
+
+	root, err := ioutil.TempDir("", "tagger-config")
+	r.NoError(err)
+	defer os.RemoveAll(root)
+
+	home := filepath.Join(root, "home")
+	project := filepath.Join(root, "scans", "2019")
+	wd := filepath.Join(project, "roll-139")
+	r.NoError(os.MkdirAll(wd, 0755))
+
+	touch := func(path string) string {
+		r.NoError(os.MkdirAll(filepath.Dir(path), 0755))
+		r.NoError(ioutil.WriteFile(path, []byte("---\n"), 0644))
+		return path
+	}
+
+	r.Equal([]string{}, DiscoverFiles(wd, home, ""))
+
+	legacy := touch(filepath.Join(home, ".tagger", "config.yaml"))
+	r.Equal([]string{legacy}, DiscoverFiles(wd, home, ""))
+
+	xdgDefault := touch(filepath.Join(home, ".config", "tagger", "config.yaml"))
+	r.Equal([]string{legacy, xdgDefault}, DiscoverFiles(wd, home, ""))
+
+	xdg := touch(filepath.Join(root, "xdg", "tagger", "config.yaml"))
+	r.Equal([]string{legacy, xdg}, DiscoverFiles(wd, home, filepath.Join(root, "xdg")))
+
+	parent := touch(filepath.Join(root, "scans", projectConfigName))
+	nearest := touch(filepath.Join(project, projectConfigName))
+	r.Equal([]string{legacy, xdgDefault, nearest}, DiscoverFiles(wd, home, ""))
+
+	r.NoError(os.Remove(nearest))
+	r.Equal([]string{legacy, xdgDefault, parent}, DiscoverFiles(wd, home, ""))
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	r := require.New(t)
+
+	cfg := NewDefaultConfig()
+	err := cfg.FillFromEnv([]string{
+		"HOME=/home/user",
+		"TAGGER_COPYRIGHT=Jane Doe: all rights reserved",
+		"TAGGER_EXIFTOOL_BINARY=/opt/bin/exiftool",
+		"TAGGER_FILE_SOURCE=Film Scanner",
+		"TAGGER_GEOTAG_MAX_GAP=5m",
+		"TAGGER_MAKE=Canon",
+		"TAGGER_MODEL=9=EOS-1V HS",
+		"TAGGER_SET_DIGITIZED=true",
+		"TAGGER_TIMEZONE={0: Europe/Moscow, 3: Asia/Tokyo}",
+		"TAGGER_FILM_STOCK_ASSIGNMENTS={09-139: portra400}",
+		"TAGGER_CAMERAS={9: {artist: John Doe}}",
+	})
+	r.NoError(err)
+
+	r.Equal(types.PtrString("Jane Doe: all rights reserved"), cfg.GetCopyright())
+	r.Equal("/opt/bin/exiftool", cfg.GetExiftoolBinary())
+	r.Equal(types.PtrFileSource(types.FileSourceFilmScanner), cfg.GetFileSource())
+	r.Equal(5*time.Minute, cfg.GetGeotagMaxGap())
+	r.Equal(types.PtrString("Canon"), cfg.GetCameraProfile(9).Make)
+	r.Equal(types.PtrString("EOS-1V HS"), cfg.GetCameraProfile(9).Model)
+	r.Nil(cfg.GetCameraProfile(3).Model)
+	r.True(cfg.GetSetDigitized())
+	r.Equal("Europe/Moscow", *cfg.GetCameraProfile(9).Timezone)
+	r.Equal("Asia/Tokyo", *cfg.GetCameraProfile(3).Timezone)
+	r.Equal(map[string]string{"09-139": "portra400"}, cfg.GetFilmStockAssignments())
+	r.Equal(types.PtrString("John Doe"), cfg.GetCameraProfile(9).Artist)
+	r.Nil(cfg.GetCameraProfile(3).Artist)
+	r.Empty(cfg.GetWarnings())
+	r.Equal("env TAGGER_GEOTAG_MAX_GAP", cfg.(*config).sources["geotag-max-gap"])
+
+	err = cfg.FillFromEnv([]string{"TAGGER_NO_SUCH_KEY=1", "TAGGER_COPYRIGHT=Jane Doe"})
+	r.NoError(err)
+	r.Equal([]string{"env TAGGER_NO_SUCH_KEY: unknown configuration key `no-such-key`, ignored"}, cfg.GetWarnings())
+	r.Equal("Jane Doe", *cfg.GetCopyright())
+
+	err = cfg.FillFromEnv([]string{"TAGGER_GEOTAG_OFFSET=blah"})
+	r.Error(err)
+}
+
+func TestPrecedence(t *testing.T) {
+	r := require.New(t)
+
+	m := flagM.New()
+	m.On("GetDisplayHelp").Return(false).Once()
+	m.On("GetDisplayVersion").Return(false).Once()
+	m.On("GetClockOffset").Return(map[uint8]time.Duration(nil)).Once()
+	m.On("GetCopyright").Return("").Once()
+	m.On("GetExiftoolBinary").Return("").Once()
+	m.On("GetFileSource").Return(types.FileSource("")).Once()
+	m.On("GetFilenamePattern").Return("").Once()
+	m.On("GetGeotag").Return("").Once()
+	m.On("GetGeotagMaxGap").Return(time.Duration(0)).Once()
+	m.On("GetGeotagOffset").Return(time.Duration(0)).Once()
+	m.On("GetMake").Return(map[uint8]string(nil)).Once()
+	m.On("GetModel").Return(map[uint8]string(nil)).Once()
+	m.On("GetSerialNumber").Return(map[uint8]string(nil)).Once()
+	m.On("GetSidecarDir").Return("/scans").Twice()
+	m.On("GetSetDigitized").Return(false).Once()
+	m.On("GetTimestampFormat").Return(types.TimestampFormat("")).Once()
+	m.On("GetTimezone").Return(map[uint8]types.Timezone(nil)).Once()
+
+	cfg := NewDefaultConfig()
+	r.NoError(cfg.FillFromYaml("./testdata/config.yaml"))
+	r.NoError(cfg.FillFromEnv([]string{
+		"TAGGER_COPYRIGHT=from env",
+		"TAGGER_SIDECAR_DIR=/env/scans",
+	}))
+	r.NoError(cfg.FillFromFlags(m))
+
+	// flags win over environment
+	r.Equal("/scans", cfg.GetSidecarDir())
+	// environment wins over config file
+	r.Equal(types.PtrString("from env"), cfg.GetCopyright())
+	// config file wins over defaults, unset flags and environment do not
+	// override it
+	r.Equal("/usr/local/bin/exiftool", cfg.GetExiftoolBinary())
+	r.Equal(10*time.Minute, cfg.GetGeotagMaxGap())
+	r.Equal("Europe/Moscow", *cfg.GetCameraProfile(9).Timezone)
+
+	sources := cfg.(*config).sources
+	r.Equal("flag -sidecar-dir", sources["sidecar-dir"])
+	r.Equal("env TAGGER_COPYRIGHT", sources["copyright"])
+	r.Equal("./testdata/config.yaml", sources["exiftool-binary"])
+}
@@ -1,6 +1,8 @@
 package flags
 
 import (
+	"time"
+
 	"github.com/stretchr/testify/mock"
 
 	"github.com/teran/eos-1v-tagger/types"
@@ -40,6 +42,57 @@ func (m *Mock) GetDisplayVersion() bool {
 	return args.Get(0).(bool)
 }
 
+func (m *Mock) GetConfigPath() string {
+	args := m.Called()
+	return args.Get(0).(string)
+}
+
+func (m *Mock) GetShowConfig() bool {
+	args := m.Called()
+	return args.Get(0).(bool)
+}
+
+func (m *Mock) GetExport() types.DataFormat {
+	args := m.Called()
+	return args.Get(0).(types.DataFormat)
+}
+
+func (m *Mock) GetFormat() types.ExportFormat {
+	args := m.Called()
+	return args.Get(0).(types.ExportFormat)
+}
+
+func (m *Mock) GetColumns() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+func (m *Mock) GetJSON() bool {
+	args := m.Called()
+	return args.Bool(0)
+}
+
+func (m *Mock) GetOutput() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+func (m *Mock) GetThumbnailSize() int {
+	args := m.Called()
+	return args.Int(0)
+}
+
+func (m *Mock) GetInputFormat() types.InputFormat {
+	args := m.Called()
+	return args.Get(0).(types.InputFormat)
+}
+
+func (m *Mock) GetClockOffset() map[uint8]time.Duration {
+	args := m.Called()
+	v, _ := args.Get(0).(map[uint8]time.Duration)
+	return v
+}
+
 func (m *Mock) GetCopyright() string {
 	args := m.Called()
 	return args.Get(0).(string)
@@ -65,17 +118,35 @@ func (m *Mock) GetGeotag() string {
 	return args.Get(0).(string)
 }
 
-func (m *Mock) GetMake() string {
+func (m *Mock) GetGeotagMaxGap() time.Duration {
 	args := m.Called()
-	return args.Get(0).(string)
+	return args.Get(0).(time.Duration)
 }
 
-func (m *Mock) GetModel() string {
+func (m *Mock) GetGeotagOffset() time.Duration {
 	args := m.Called()
-	return args.Get(0).(string)
+	return args.Get(0).(time.Duration)
+}
+
+func (m *Mock) GetMake() map[uint8]string {
+	args := m.Called()
+	v, _ := args.Get(0).(map[uint8]string)
+	return v
 }
 
-func (m *Mock) GetSerialNumber() string {
+func (m *Mock) GetModel() map[uint8]string {
+	args := m.Called()
+	v, _ := args.Get(0).(map[uint8]string)
+	return v
+}
+
+func (m *Mock) GetSerialNumber() map[uint8]string {
+	args := m.Called()
+	v, _ := args.Get(0).(map[uint8]string)
+	return v
+}
+
+func (m *Mock) GetSidecarDir() string {
 	args := m.Called()
 	return args.Get(0).(string)
 }
@@ -90,12 +161,14 @@ func (m *Mock) GetTimestampFormat() types.TimestampFormat {
 	return args.Get(0).(types.TimestampFormat)
 }
 
-func (m *Mock) GetTimezone() types.Timezone {
+func (m *Mock) GetTimezone() map[uint8]types.Timezone {
 	args := m.Called()
-	return args.Get(0).(types.Timezone)
+	v, _ := args.Get(0).(map[uint8]types.Timezone)
+	return v
 }
 
-func (m *Mock) GetCSVPath() string {
+func (m *Mock) GetInputPaths() []string {
 	args := m.Called()
-	return args.Get(0).(string)
+	v, _ := args.Get(0).([]string)
+	return v
 }
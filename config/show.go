@@ -0,0 +1,141 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+const defaultSource = "default"
+
+// Show prints effective configuration as YAML document annotating every
+// key with the source its value came from
+func (c *config) Show(w io.Writer) error {
+	if err := c.showCameras(w); err != nil {
+		return err
+	}
+
+	for _, item := range c.effective() {
+		if err := writeAnnotated(w, item, "", c.source(item.Key.(string))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// showCameras prints camera profiles annotating every field separately
+// since every field could be set from different source
+func (c *config) showCameras(w io.Writer) error {
+	if len(c.cameras) == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintln(w, "cameras:"); err != nil {
+		return err
+	}
+
+	for _, id := range cameraIDs(c.cameras) {
+		out, err := yaml.Marshal(c.cameras[id])
+		if err != nil {
+			return err
+		}
+
+		var fields yaml.MapSlice
+		if err := yaml.Unmarshal(out, &fields); err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintf(w, "  %d:\n", id); err != nil {
+			return err
+		}
+		for _, f := range fields {
+			if err := writeAnnotated(w, f, "    ", c.source(cameraKey(id, f.Key.(string)))); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func writeAnnotated(w io.Writer, item yaml.MapItem, indent, source string) error {
+	out, err := yaml.Marshal(yaml.MapSlice{item})
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(strings.TrimRight(string(out), "\n"), "\n")
+	lines[0] += "  # " + source
+	for _, l := range lines {
+		if _, err := fmt.Fprintln(w, indent+l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *config) source(key string) string {
+	if s, ok := c.sources[key]; ok {
+		return s
+	}
+	return defaultSource
+}
+
+// effective returns the configuration values but camera profiles in the
+// order of YamlConfig fields, unset values are omitted
+func (c *config) effective() yaml.MapSlice {
+	ms := yaml.MapSlice{}
+	add := func(key string, value interface{}) {
+		ms = append(ms, yaml.MapItem{Key: key, Value: value})
+	}
+
+	if c.copyright != nil {
+		add("copyright", *c.copyright)
+	}
+	add("exiftool-binary", c.exiftoolBinary)
+	add("filename-pattern", c.filenamePattern)
+	if c.fileSource != nil {
+		add("file-source", string(*c.fileSource))
+	}
+	if len(c.filmStocks) > 0 {
+		add("film-stocks", c.filmStocks)
+	}
+	if len(c.stockAssigns) > 0 {
+		add("film-stock-assignments", c.stockAssigns)
+	}
+	if c.geotag != nil {
+		add("geotag", *c.geotag)
+	}
+	add("geotag-max-gap", c.geotagMaxGap.String())
+	add("geotag-offset", c.geotagOffset.String())
+	if len(c.lenses) > 0 {
+		add("lenses", c.lenses)
+	}
+	add("sidecar-dir", c.sidecarDir)
+	add("set-digitized", c.setDigitized)
+	add("timestamp-format", string(c.timestampFormat))
+	if len(c.tzOverrides) > 0 {
+		add("timezone-overrides", c.tzOverrides)
+	}
+
+	return ms
+}
+
+func cameraIDs(m map[uint8]types.CameraProfile) []uint8 {
+	ids := make([]uint8, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sortCameraIDs(ids)
+	return ids
+}
+
+func sortCameraIDs(ids []uint8) {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShow(t *testing.T) {
+	r := require.New(t)
+
+	cfg := NewDefaultConfig()
+	r.NoError(cfg.FillFromEnv([]string{
+		"TAGGER_COPYRIGHT=Jane Doe",
+		"TAGGER_MAKE=Canon",
+	}))
+
+	buf := &bytes.Buffer{}
+	err := cfg.Show(buf)
+	r.NoError(err)
+	r.Equal(`cameras:
+  0:
+    make: Canon  # env TAGGER_MAKE
+    timezone: UTC  # default
+copyright: Jane Doe  # env TAGGER_COPYRIGHT
+exiftool-binary: exiftool  # default
+filename-pattern: FILM_${cameraID:02d}${filmID:03d}${frameNo:05d}.dng  # default
+geotag-max-gap: 30m0s  # default
+geotag-offset: 0s  # default
+sidecar-dir: .  # default
+set-digitized: false  # default
+timestamp-format: US  # default
+`, buf.String())
+}
+
+func TestShowFromYAML(t *testing.T) {
+	r := require.New(t)
+
+	cfg := NewDefaultConfig()
+	r.NoError(cfg.FillFromYaml("./testdata/config.yaml"))
+
+	buf := &bytes.Buffer{}
+	r.NoError(cfg.Show(buf))
+
+	// output is a valid configuration file itself
+	fp, err := ioutil.TempFile("", "tagger-config")
+	r.NoError(err)
+	defer os.Remove(fp.Name())
+
+	_, err = fp.Write(buf.Bytes())
+	r.NoError(err)
+	r.NoError(fp.Close())
+
+	cfg2 := NewDefaultConfig()
+	r.NoError(cfg2.FillFromYaml(fp.Name()))
+
+	cfg2.(*config).sources = cfg.(*config).sources
+	r.Equal(cfg, cfg2)
+}
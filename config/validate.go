@@ -0,0 +1,144 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	correction "github.com/teran/eos-1v-tagger/correction"
+	format "github.com/teran/eos-1v-tagger/format"
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+// Validate checks the effective configuration and returns an error listing
+// every problem found along with the source of the offending value.
+// exiftool binary is checked by ValidateExiftool since only the commands
+// running exiftool need it.
+func (c *config) Validate() error {
+	problems := []string{}
+	report := func(key, sourceKey string, err error) {
+		problems = append(problems, fmt.Sprintf("%s: %s (set by %s)", key, err, c.source(sourceKey)))
+	}
+
+	lensNames := map[string]struct{}{}
+	for _, l := range c.lenses {
+		lensNames[l.Name] = struct{}{}
+	}
+
+	for _, id := range cameraIDs(c.cameras) {
+		p := c.cameras[id]
+
+		if p.ClockCorrection != nil {
+			if _, err := correction.NewClock(*p.ClockCorrection); err != nil {
+				key := cameraKey(id, "clock-correction")
+				report(key, key, err)
+			}
+		}
+
+		if p.Timezone != nil {
+			if _, err := time.LoadLocation(*p.Timezone); err != nil {
+				key := cameraKey(id, "timezone")
+				report(key, key, err)
+			}
+		}
+
+		if p.Lens != nil {
+			if _, ok := lensNames[*p.Lens]; !ok {
+				key := cameraKey(id, "lens")
+				report(key, key, errors.Errorf("lens `%s` is not present in the lens catalog", *p.Lens))
+			}
+		}
+	}
+
+	if _, err := format.Compile(c.filenamePattern, format.FrameVariables); err != nil {
+		report("filename-pattern", "filename-pattern", err)
+	}
+
+	if c.fileSource != nil {
+		var fs types.FileSource
+		if err := fs.Set(string(*c.fileSource)); err != nil {
+			report("file-source", "file-source", err)
+		}
+	}
+
+	stockIDs := make([]string, 0, len(c.filmStocks))
+	for id := range c.filmStocks {
+		stockIDs = append(stockIDs, id)
+	}
+	sort.Strings(stockIDs)
+	for _, id := range stockIDs {
+		if _, err := regexp.Compile(c.filmStocks[id].TitlePattern); err != nil {
+			report(fmt.Sprintf("film-stocks.%s.title-pattern", id), "film-stocks", err)
+		}
+	}
+
+	filmIDs := make([]string, 0, len(c.stockAssigns))
+	for id := range c.stockAssigns {
+		filmIDs = append(filmIDs, id)
+	}
+	sort.Strings(filmIDs)
+	for _, id := range filmIDs {
+		if _, ok := c.filmStocks[c.stockAssigns[id]]; !ok {
+			report(fmt.Sprintf("film-stock-assignments.%s", id), "film-stock-assignments",
+				errors.Errorf("unknown film stock `%s`", c.stockAssigns[id]))
+		}
+	}
+
+	if c.geotag != nil {
+		if st, err := os.Stat(*c.geotag); err != nil {
+			report("geotag", "geotag", err)
+		} else if st.IsDir() {
+			report("geotag", "geotag", errors.Errorf("%s is a directory", *c.geotag))
+		}
+	}
+
+	if c.geotagMaxGap <= 0 {
+		report("geotag-max-gap", "geotag-max-gap", errors.Errorf("positive duration expected, got %s", c.geotagMaxGap))
+	}
+
+	for i, l := range c.lenses {
+		key := fmt.Sprintf("lenses[%d]", i)
+		if l.Name == "" {
+			report(key, "lenses", errors.New("lens name is required"))
+		}
+		if l.FocalLength.Min > l.FocalLength.Max {
+			report(key, "lenses", errors.Errorf("focal length minimum %v is greater than maximum %v", l.FocalLength.Min, l.FocalLength.Max))
+		}
+		if l.MaxAperture.Min > l.MaxAperture.Max {
+			report(key, "lenses", errors.Errorf("max aperture minimum %v is greater than maximum %v", l.MaxAperture.Min, l.MaxAperture.Max))
+		}
+	}
+
+	if _, err := types.NewTimestampFormat(string(c.timestampFormat)); err != nil {
+		report("timestamp-format", "timestamp-format", errors.Errorf("unknown value `%s`, allowed values: 'US', 'EU'", c.timestampFormat))
+	}
+
+	for i, o := range c.tzOverrides {
+		key := fmt.Sprintf("timezone-overrides[%d]", i)
+		if _, err := time.LoadLocation(o.Timezone); err != nil {
+			report(key, "timezone-overrides", err)
+		}
+		if o.From != nil && o.To != nil && o.To.Before(*o.From) {
+			report(key, "timezone-overrides", errors.New("`to` date is before `from` date"))
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.Errorf("invalid configuration:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// ValidateExiftool checks the exiftool binary could be run
+func (c *config) ValidateExiftool() error {
+	if _, err := exec.LookPath(c.exiftoolBinary); err != nil {
+		return errors.Errorf("exiftool-binary: %s (set by %s)", err, c.source("exiftool-binary"))
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	r := require.New(t)
+
+	cfg := NewDefaultConfig()
+	r.NoError(cfg.FillFromYaml("./testdata/config.yaml"))
+
+	// test binary itself is the executable known to exist
+	cfg.(*config).exiftoolBinary = os.Args[0]
+	cfg.(*config).geotag = nil
+
+	r.NoError(cfg.Validate())
+}
+
+func TestValidateInvalid(t *testing.T) {
+	r := require.New(t)
+
+	cfg := NewDefaultConfig()
+	r.NoError(cfg.FillFromYaml("./testdata/invalid.yaml"))
+	r.NoError(cfg.FillFromEnv([]string{"TAGGER_GEOTAG_MAX_GAP=-5m"}))
+
+	err := cfg.Validate()
+	r.Error(err)
+	r.Equal("invalid configuration:\n"+
+		"  cameras.9.clock-correction: clock correction could be set either by offset or by references, not both (set by ./testdata/invalid.yaml)\n"+
+		"  cameras.9.timezone: unknown time zone Europe/Mscow (set by ./testdata/invalid.yaml)\n"+
+		"  cameras.9.lens: lens `EF85mm f/1.8 USM` is not present in the lens catalog (set by ./testdata/invalid.yaml)\n"+
+		"  filename-pattern: error parsing variable at position 5 in pattern `FILM_${cameraID:02s}.dng`: variable `cameraID`: verb `s` is not applicable to integer values (set by ./testdata/invalid.yaml)\n"+
+		"  file-source: Unknown value `Polaroid` for time format (set by ./testdata/invalid.yaml)\n"+
+		"  film-stocks.portra400.title-pattern: error parsing regexp: missing closing ): `(?i)portra(` (set by ./testdata/invalid.yaml)\n"+
+		"  film-stock-assignments.09-139: unknown film stock `ektar100` (set by ./testdata/invalid.yaml)\n"+
+		"  geotag: stat ./testdata/nonexistent.gpx: no such file or directory (set by ./testdata/invalid.yaml)\n"+
+		"  geotag-max-gap: positive duration expected, got -5m0s (set by env TAGGER_GEOTAG_MAX_GAP)\n"+
+		"  lenses[0]: focal length minimum 70 is greater than maximum 24 (set by ./testdata/invalid.yaml)\n"+
+		"  timestamp-format: unknown value `ISO`, allowed values: 'US', 'EU' (set by ./testdata/invalid.yaml)\n"+
+		"  timezone-overrides[0]: `to` date is before `from` date (set by ./testdata/invalid.yaml)",
+		err.Error())
+}
+
+func TestValidateExiftool(t *testing.T) {
+	r := require.New(t)
+
+	cfg := NewDefaultConfig()
+	cfg.(*config).exiftoolBinary = os.Args[0]
+	r.NoError(cfg.ValidateExiftool())
+
+	r.NoError(cfg.FillFromYaml("./testdata/invalid.yaml"))
+	err := cfg.ValidateExiftool()
+	r.Error(err)
+	r.Equal("exiftool-binary: exec: \"/nonexistent/exiftool\": stat /nonexistent/exiftool: no such file or directory (set by ./testdata/invalid.yaml)", err.Error())
+}
+
+func TestStrictYAML(t *testing.T) {
+	r := require.New(t)
+
+	cfg := NewDefaultConfig()
+	err := cfg.FillFromYaml("./testdata/unknown-key.yaml")
+	r.Error(err)
+	r.Contains(err.Error(), "field timezones not found")
+}
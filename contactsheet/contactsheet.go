@@ -0,0 +1,290 @@
+package contactsheet
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	inspect "github.com/teran/eos-1v-tagger/inspect"
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+// DefaultThumbnailSize is the maximum width and height of thumbnails in
+// pixels unless set
+const DefaultThumbnailSize = 320
+
+const (
+	indexFile     = "index.html"
+	thumbnailsDir = "thumbnails"
+)
+
+// frameColumns are the columns of inspect view printed on every frame
+var frameColumns = []string{"timestamp", "tv", "av", "iso", "ec", "fec", "focal", "lens", "shooting", "metering", "flash", "af", "advance", "multiple", "position"}
+
+// Options of the contact sheet
+type Options struct {
+	// Scan returns the filename of the frame scan, thumbnails are not made
+	// if it's nil
+	Scan func(*types.Film, *types.Frame) string
+
+	// Camera returns camera name printed along with camera ID, optional
+	Camera func(*types.Film) string
+
+	// ThumbnailSize is the maximum width and height of thumbnails in pixels,
+	// DefaultThumbnailSize is used if zero
+	ThumbnailSize int
+}
+
+// Report summarizes generated contact sheets
+type Report struct {
+	Films      int
+	Frames     int
+	Thumbnails int
+
+	// Missing are the frames no JPEG or TIFF scan is found for
+	Missing int
+
+	// Errors are the scans failed to be read, the frames are shown without
+	// thumbnails
+	Errors []error
+}
+
+// Generate writes contact sheet of every film to the directory: HTML page
+// with the film data and a grid of frames each with thumbnail of its scan
+// and shooting data, thumbnails are written to the same directory. Index
+// page links the films so the directory is self-contained.
+func Generate(dir string, films []*types.Film, opts Options) (*Report, error) {
+	if opts.ThumbnailSize == 0 {
+		opts.ThumbnailSize = DefaultThumbnailSize
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, thumbnailsDir), 0755); err != nil {
+		return nil, err
+	}
+
+	columns, err := inspect.ParseColumns(strings.Join(frameColumns, ","))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Errors: []error{}}
+	index := indexPage{}
+	for _, film := range films {
+		page := newFilmPage(film, opts.Camera)
+		for i, fr := range film.Frames {
+			card := newFrameCard(film, fr, columns)
+			if opts.Scan != nil {
+				makeThumbnail(dir, film, i, opts.Scan(film, fr), opts.ThumbnailSize, &card, report)
+			}
+			page.Frames = append(page.Frames, card)
+			page.Marked = page.Marked || card.Marks != ""
+		}
+
+		fn := "film-" + film.FullID() + ".html"
+		if err := writePage(filepath.Join(dir, fn), filmTemplate, page); err != nil {
+			return nil, err
+		}
+
+		index.Films = append(index.Films, indexEntry{
+			Link:   fn,
+			ID:     film.FullID(),
+			Title:  strValue(film.Title),
+			Stock:  page.Stock,
+			Loaded: dateValue(film.FilmLoadedTimestamp),
+			Frames: len(film.Frames),
+		})
+		report.Films++
+		report.Frames += len(film.Frames)
+	}
+
+	if err := writePage(filepath.Join(dir, indexFile), indexTemplate, index); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func makeThumbnail(dir string, film *types.Film, i int, scan string, size int, card *frameCard, report *Report) {
+	if scan == "" {
+		return
+	}
+
+	src := findScan(scan)
+	if src == "" {
+		card.Problem = "scan not found"
+		card.Scan = filepath.Base(scan)
+		report.Missing++
+		return
+	}
+	card.Scan = filepath.Base(src)
+
+	fn := filepath.Join(thumbnailsDir, fmt.Sprintf("%s-%03d.jpg", film.FullID(), i+1))
+	if err := writeThumbnail(src, filepath.Join(dir, fn), size); err != nil {
+		card.Problem = "scan is not readable"
+		report.Errors = append(report.Errors, errors.Wrapf(err, "film %s frame %s: %s", film.FullID(), card.Number, src))
+		return
+	}
+
+	card.Thumbnail = filepath.ToSlash(fn)
+	report.Thumbnails++
+}
+
+type field struct {
+	Name  string
+	Value string
+}
+
+type indexPage struct {
+	Films []indexEntry
+}
+
+type indexEntry struct {
+	Link   string
+	ID     string
+	Title  string
+	Stock  string
+	Loaded string
+	Frames int
+}
+
+type filmPage struct {
+	ID     string
+	Title  string
+	Stock  string
+	Fields []field
+	Frames []frameCard
+
+	// Marked is set if any frame is marked so the legend is printed
+	Marked bool
+}
+
+type frameCard struct {
+	Number    string
+	Marks     string
+	Flagged   bool
+	Empty     bool
+	Thumbnail string
+	Scan      string
+	Problem   string
+	Fields    []field
+	Remarks   string
+}
+
+func newFilmPage(f *types.Film, camera func(*types.Film) string) filmPage {
+	p := filmPage{
+		ID:    f.FullID(),
+		Title: strValue(f.Title),
+	}
+
+	cameraID := ""
+	if f.CameraID != nil {
+		cameraID = fmt.Sprintf("%02d", *f.CameraID)
+	}
+	if camera != nil {
+		if name := camera(f); name != "" {
+			cameraID += " (" + name + ")"
+		}
+	}
+
+	if f.Stock != nil {
+		p.Stock = f.Stock.String()
+	}
+
+	frames := fmt.Sprintf("%d present", len(f.Frames))
+	if f.FrameCount != nil {
+		frames = fmt.Sprintf("%d present of %d counted", len(f.Frames), *f.FrameCount)
+	}
+
+	pushPull := ""
+	if f.PushPull != nil && *f.PushPull != 0 {
+		pushPull = strconv.FormatFloat(*f.PushPull, 'f', -1, 64)
+		if *f.PushPull > 0 {
+			pushPull = "+" + pushPull
+		}
+	}
+
+	iso := ""
+	if f.ISO != nil {
+		iso = strconv.FormatInt(*f.ISO, 10)
+	}
+
+	for _, v := range []field{
+		{"Camera", cameraID},
+		{"Loaded", timeValue(f.FilmLoadedTimestamp)},
+		{"ISO (DX)", iso},
+		{"Frames", frames},
+		{"Stock", p.Stock},
+		{"Push/pull", pushPull},
+		{"Developer", strValue(f.Developer)},
+		{"Lab", strValue(f.Lab)},
+		{"Location", strValue(f.Location)},
+		{"Remarks", strValue(f.Remarks)},
+	} {
+		if v.Value != "" {
+			p.Fields = append(p.Fields, v)
+		}
+	}
+
+	return p
+}
+
+func newFrameCard(film *types.Film, f *types.Frame, columns []inspect.Column) frameCard {
+	c := frameCard{
+		Marks:   inspect.Marks(film, f),
+		Flagged: f.Flag != nil && *f.Flag,
+		Empty:   inspect.IsEmpty(film, f),
+		Remarks: strValue(f.Remarks),
+	}
+
+	if f.Number != nil {
+		c.Number = strconv.FormatInt(*f.Number, 10)
+	}
+
+	for _, col := range columns {
+		if v := col.Value(f); v != "" {
+			c.Fields = append(c.Fields, field{col.Header, v})
+		}
+	}
+
+	return c
+}
+
+func writePage(fn string, tmpl *template.Template, data interface{}) error {
+	fp, err := os.Create(fn)
+	if err != nil {
+		return err
+	}
+
+	if err := tmpl.Execute(fp, data); err != nil {
+		fp.Close()
+		return errors.Wrapf(err, "error writing %s", fn)
+	}
+	return fp.Close()
+}
+
+func strValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return strings.TrimSpace(*s)
+}
+
+func timeValue(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func dateValue(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
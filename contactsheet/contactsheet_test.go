@@ -0,0 +1,173 @@
+package contactsheet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+func TestGenerate(t *testing.T) {
+	r := require.New(t)
+
+	dir, err := ioutil.TempDir("", "contactsheet")
+	r.NoError(err)
+	defer os.RemoveAll(dir)
+
+	scans := filepath.Join(dir, "scans")
+	r.NoError(os.Mkdir(scans, 0755))
+
+	fp, err := os.Create(filepath.Join(scans, "FILM_01139_00001.jpg"))
+	r.NoError(err)
+	r.NoError(jpeg.Encode(fp, image.NewGray(image.Rect(0, 0, 64, 48)), nil))
+	r.NoError(fp.Close())
+
+	// DNG scan is replaced with TIFF version of it
+	r.NoError(ioutil.WriteFile(filepath.Join(scans, "FILM_01139_00002.dng"), []byte("raw"), 0644))
+	data, _ := testTIFF{
+		order: binary.LittleEndian, width: 30, height: 60, samples: 3, bits: 16,
+		photometric: tiffPhotometricRGB, compression: tiffCompressionLZW, predictor: tiffPredictorHorizontal,
+	}.encode()
+	r.NoError(ioutil.WriteFile(filepath.Join(scans, "FILM_01139_00002.TIF"), data, 0644))
+
+	r.NoError(ioutil.WriteFile(filepath.Join(scans, "FILM_01139_00004.jpg"), []byte("broken"), 0644))
+
+	films := []*types.Film{
+		{
+			ID:                  types.PtrInt64(139),
+			CameraID:            types.PtrUint8(1),
+			Title:               types.PtrString("Trip <north>"),
+			FilmLoadedTimestamp: types.PtrTime(time.Date(2019, 9, 28, 10, 21, 32, 0, time.UTC)),
+			ISO:                 types.PtrInt64(400),
+			FrameCount:          types.PtrInt64(36),
+			Lab:                 types.PtrString("Local lab"),
+			Frames: []*types.Frame{
+				{
+					Number:               types.PtrInt64(1),
+					Tv:                   types.PtrString("1/250"),
+					Av:                   types.PtrAperture(8),
+					ISO:                  types.PtrInt64(400),
+					ExposureCompensation: types.PtrFloat64(-0.5),
+					FocalLength:          types.PtrInt64(35),
+					ShootingMode:         types.PtrShootingMode(types.ShootingModeAperturePriorityAE),
+					Timestamp:            types.PtrTime(time.Date(2019, 10, 7, 20, 2, 18, 0, time.UTC)),
+					Remarks:              types.PtrString("<b>keeper</b>"),
+				},
+				{
+					Flag:      types.PtrBool(true),
+					Number:    types.PtrInt64(2),
+					Tv:        types.PtrString("1/60"),
+					Timestamp: types.PtrTime(time.Date(2019, 10, 7, 20, 5, 0, 0, time.UTC)),
+				},
+				{
+					Number: types.PtrInt64(3),
+				},
+				{
+					Number: types.PtrInt64(4),
+					Tv:     types.PtrString("1/30"),
+				},
+			},
+		},
+		{
+			ID:       types.PtrInt64(12),
+			CameraID: types.PtrUint8(20),
+		},
+	}
+
+	out := filepath.Join(dir, "out")
+	report, err := Generate(out, films, Options{
+		Scan: func(film *types.Film, frame *types.Frame) string {
+			ext := ".jpg"
+			if *frame.Number == 2 {
+				ext = ".dng"
+			}
+			return filepath.Join(scans, fmt.Sprintf("FILM_%02d%03d_%05d%s", *film.CameraID, *film.ID, *frame.Number, ext))
+		},
+		Camera: func(*types.Film) string {
+			return "Canon EOS-1V"
+		},
+		ThumbnailSize: 16,
+	})
+	r.NoError(err)
+	r.Equal(2, report.Films)
+	r.Equal(4, report.Frames)
+	r.Equal(2, report.Thumbnails)
+	r.Equal(1, report.Missing)
+	r.Len(report.Errors, 1)
+	r.Contains(report.Errors[0].Error(), "film 01-139 frame 4: "+filepath.Join(scans, "FILM_01139_00004.jpg"))
+
+	for fn, size := range map[string]image.Point{
+		"01-139-001.jpg": {16, 12},
+		"01-139-002.jpg": {8, 16},
+	} {
+		fp, err := os.Open(filepath.Join(out, thumbnailsDir, fn))
+		r.NoError(err)
+		cfg, err := jpeg.DecodeConfig(fp)
+		fp.Close()
+		r.NoError(err)
+		r.Equalf(size, image.Point{cfg.Width, cfg.Height}, fn)
+	}
+
+	index, err := ioutil.ReadFile(filepath.Join(out, "index.html"))
+	r.NoError(err)
+	r.Contains(string(index), `<tr><td><a href="film-01-139.html">01-139</a></td><td>Trip &lt;north&gt;</td><td></td><td>2019-09-28</td><td>4</td></tr>`)
+	r.Contains(string(index), `<a href="film-20-012.html">20-012</a>`)
+
+	page, err := ioutil.ReadFile(filepath.Join(out, "film-01-139.html"))
+	r.NoError(err)
+	for _, s := range []string{
+		`<h1>Film 01-139: Trip &lt;north&gt;</h1>`,
+		`<dt>Camera</dt><dd>01 (Canon EOS-1V)</dd>`,
+		`<dt>Frames</dt><dd>4 present of 36 counted</dd>`,
+		`<dt>Lab</dt><dd>Local lab</dd>`,
+		`<img src="thumbnails/01-139-001.jpg" alt="Frame 1">`,
+		`<span class="scan">FILM_01139_00002.TIF</span>`,
+		`<div class="frame flagged">`,
+		`<span class="number">2</span><span class="marks">*</span>`,
+		`<dt>TV</dt><dd>1/250</dd>`,
+		`<dt>AV</dt><dd>8.0</dd>`,
+		`<dt>EC</dt><dd>-0.5</dd>`,
+		`<dt>SHOOTING</dt><dd>Aperture-priority AE</dd>`,
+		`<dt>TIMESTAMP</dt><dd>2019-10-07T20:02:18Z</dd>`,
+		`<p class="remarks">&lt;b&gt;keeper&lt;/b&gt;</p>`,
+		`<div class="frame empty">`,
+		`<span class="problem">scan not found</span>`,
+		`<span class="problem">scan is not readable</span>`,
+		`<p class="legend">Marks: * flagged, ! no timestamp, - empty frame</p>`,
+	} {
+		r.Contains(string(page), s)
+	}
+
+	page, err = ioutil.ReadFile(filepath.Join(out, "film-20-012.html"))
+	r.NoError(err)
+	r.NotContains(string(page), `class="legend"`)
+}
+
+func TestThumbnail(t *testing.T) {
+	r := require.New(t)
+
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		img.Set(x, 0, color.RGBA{R: 200, A: 0xff})
+		img.Set(x, 1, color.RGBA{R: 100, B: 50, A: 0xff})
+	}
+
+	th := thumbnail(img, 2)
+	r.Equal(image.Rect(0, 0, 2, 1), th.Bounds())
+	r.Equal(color.RGBA{R: 150, B: 25, A: 0xff}, th.At(0, 0))
+	r.Equal(color.RGBA{R: 150, B: 25, A: 0xff}, th.At(1, 0))
+
+	th = thumbnail(img, 10)
+	r.Equal(img.Bounds(), th.Bounds())
+	r.Equal(color.RGBA{R: 100, B: 50, A: 0xff}, th.At(3, 1))
+}
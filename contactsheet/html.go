@@ -0,0 +1,102 @@
+package contactsheet
+
+import (
+	"html/template"
+
+	inspect "github.com/teran/eos-1v-tagger/inspect"
+)
+
+// style is inlined into every page so the pages need nothing but
+// thumbnails
+const style = `{{define "style"}}<style>
+body { font-family: -apple-system, "Helvetica Neue", Arial, sans-serif; margin: 2em; color: #222; background: #fafafa; }
+h1 { font-weight: normal; margin-bottom: 0.2em; }
+a { color: #2a5db0; }
+table.films { border-collapse: collapse; }
+table.films th, table.films td { text-align: left; padding: 0.3em 1em 0.3em 0; border-bottom: 1px solid #ddd; }
+dl.film { display: grid; grid-template-columns: max-content auto; gap: 0.2em 1em; margin: 1em 0 2em; }
+dl.film dt { color: #777; }
+dl.film dd { margin: 0; }
+.frames { display: grid; grid-template-columns: repeat(auto-fill, minmax(16em, 1fr)); gap: 1em; }
+.frame { background: #fff; border: 1px solid #ddd; padding: 0.6em; font-size: 0.85em; }
+.frame.flagged { border-color: #d9822b; box-shadow: 0 0 0 1px #d9822b; }
+.frame.empty { opacity: 0.6; }
+.frame .image { display: flex; align-items: center; justify-content: center; min-height: 10em; background: #eee; margin-bottom: 0.5em; }
+.frame .image img { max-width: 100%; height: auto; display: block; }
+.frame .problem { color: #999; }
+.frame .number { font-size: 1.3em; font-weight: bold; }
+.frame .marks { color: #d9822b; margin-left: 0.3em; }
+.frame .scan { color: #999; float: right; }
+.frame dl { display: grid; grid-template-columns: max-content auto; gap: 0.1em 0.6em; margin: 0.4em 0; }
+.frame dt { color: #999; font-size: 0.85em; }
+.frame dd { margin: 0; }
+.frame .remarks { font-style: italic; margin: 0.4em 0 0; }
+.legend { color: #777; margin-top: 2em; }
+</style>{{end}}`
+
+var indexTemplate = template.Must(template.New("index").Parse(style + `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Contact sheets</title>
+{{template "style"}}
+</head>
+<body>
+<h1>Contact sheets</h1>
+<table class="films">
+<tr><th>Film</th><th>Title</th><th>Stock</th><th>Loaded</th><th>Frames</th></tr>
+{{- range .Films}}
+<tr><td><a href="{{.Link}}">{{.ID}}</a></td><td>{{.Title}}</td><td>{{.Stock}}</td><td>{{.Loaded}}</td><td>{{.Frames}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+var filmTemplate = template.Must(template.New("film").Parse(style + `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Film {{.ID}}{{with .Title}}: {{.}}{{end}}</title>
+{{template "style"}}
+</head>
+<body>
+<p><a href="` + indexFile + `">All films</a></p>
+<h1>Film {{.ID}}{{with .Title}}: {{.}}{{end}}</h1>
+<dl class="film">
+{{- range .Fields}}
+<dt>{{.Name}}</dt><dd>{{.Value}}</dd>
+{{- end}}
+</dl>
+<div class="frames">
+{{- range .Frames}}
+<div class="frame{{if .Flagged}} flagged{{end}}{{if .Empty}} empty{{end}}">
+<div class="image">
+{{- if .Thumbnail}}<img src="{{.Thumbnail}}" alt="Frame {{.Number}}">
+{{- else if .Problem}}<span class="problem">{{.Problem}}</span>
+{{- else}}<span class="problem">no scan</span>
+{{- end -}}
+</div>
+{{- with .Scan}}
+<span class="scan">{{.}}</span>
+{{- end}}
+<span class="number">{{.Number}}</span>{{with .Marks}}<span class="marks">{{.}}</span>{{end}}
+{{- with .Fields}}
+<dl>
+{{- range .}}
+<dt>{{.Name}}</dt><dd>{{.Value}}</dd>
+{{- end}}
+</dl>
+{{- end}}
+{{- with .Remarks}}
+<p class="remarks">{{.}}</p>
+{{- end}}
+</div>
+{{- end}}
+</div>
+{{- if .Marked}}
+<p class="legend">Marks: ` + inspect.MarkFlagged + ` flagged, ` + inspect.MarkNoTimestamp + ` no timestamp, ` + inspect.MarkEmpty + ` empty frame</p>
+{{- end}}
+</body>
+</html>
+`))
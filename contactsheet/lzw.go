@@ -0,0 +1,88 @@
+package contactsheet
+
+import (
+	"github.com/pkg/errors"
+)
+
+const (
+	lzwClear    = 256
+	lzwEOI      = 257
+	lzwFirst    = 258
+	lzwMinWidth = 9
+	lzwMaxWidth = 12
+)
+
+// lzwDecode decompresses LZW compressed TIFF strip. TIFF flavor of LZW
+// differs from the GIF one compress/lzw implements: codes are packed most
+// significant bit first and the code width grows one code earlier.
+//
+// Every table entry is a string already written to the output so entries
+// are kept as positions in the output instead of prefix chains.
+func lzwDecode(src []byte, size int) ([]byte, error) {
+	type entry struct {
+		start, length int
+	}
+
+	var (
+		table [1 << lzwMaxWidth]entry
+		dst   = make([]byte, 0, size)
+
+		acc   uint32
+		nbits uint
+		width uint = lzwMinWidth
+		next       = lzwFirst
+
+		last = entry{start: -1}
+	)
+
+	for len(dst) < size {
+		for nbits < width {
+			if len(src) == 0 {
+				return dst, nil
+			}
+			acc = acc<<8 | uint32(src[0])
+			src = src[1:]
+			nbits += 8
+		}
+		code := int(acc>>(nbits-width)) & (1<<width - 1)
+		nbits -= width
+
+		switch {
+		case code == lzwClear:
+			width, next, last = lzwMinWidth, lzwFirst, entry{start: -1}
+			continue
+		case code == lzwEOI:
+			return dst, nil
+		}
+
+		cur := entry{start: len(dst)}
+		switch {
+		case code < lzwClear:
+			dst = append(dst, byte(code))
+		case code < next && last.start >= 0:
+			e := table[code]
+			dst = append(dst, dst[e.start:e.start+e.length]...)
+		case code == next && last.start >= 0:
+			// the code is being defined: the last string followed by its
+			// first byte
+			dst = append(dst, dst[last.start:last.start+last.length]...)
+			dst = append(dst, dst[last.start])
+		default:
+			return nil, errors.Errorf("invalid LZW code %d", code)
+		}
+		cur.length = len(dst) - cur.start
+
+		if last.start >= 0 && next < len(table) {
+			// the last string followed by the first byte of the current one
+			// is written right before the current string ends
+			table[next] = entry{start: last.start, length: last.length + 1}
+			next++
+			if next+1 >= 1<<width && width < lzwMaxWidth {
+				width++
+			}
+		}
+		last = cur
+	}
+
+	return dst, nil
+}
@@ -0,0 +1,172 @@
+package contactsheet
+
+import (
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// scanExtensions are the extensions of the scans thumbnails are made of,
+// for scans of other formats (e.g. DNG) the files of the same name with
+// these extensions are looked up
+var scanExtensions = []string{".jpg", ".jpeg", ".tif", ".tiff"}
+
+// findScan returns the file decodable version of the scan is stored in or
+// empty string if there's none
+func findScan(fn string) string {
+	ext := filepath.Ext(fn)
+	base := strings.TrimSuffix(fn, ext)
+
+	candidates := []string{}
+	if isScanExtension(ext) {
+		candidates = append(candidates, fn)
+	}
+	for _, e := range scanExtensions {
+		candidates = append(candidates, base+e, base+strings.ToUpper(e))
+	}
+
+	for _, c := range candidates {
+		if st, err := os.Stat(c); err == nil && st.Mode().IsRegular() {
+			return c
+		}
+	}
+	return ""
+}
+
+func isScanExtension(ext string) bool {
+	for _, e := range scanExtensions {
+		if strings.EqualFold(ext, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// readImage decodes JPEG or TIFF file detected by the file signature
+func readImage(fn string) (image.Image, error) {
+	fp, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(fp, magic); err != nil {
+		return nil, errors.Wrap(err, "error reading file signature")
+	}
+
+	switch string(magic) {
+	case "II*\x00", "MM\x00*":
+		return decodeTIFF(fp)
+	}
+
+	if magic[0] == 0xff && magic[1] == 0xd8 {
+		if _, err := fp.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return jpeg.Decode(fp)
+	}
+
+	return nil, errors.New("unknown image format, JPEG or TIFF is expected")
+}
+
+// thumbnail scales the image down to fit into size x size square, every
+// thumbnail pixel is the average of the source pixels it covers. Images
+// smaller than the square are kept as is.
+func thumbnail(img image.Image, size int) *image.RGBA {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	tw, th := w, h
+	switch {
+	case w >= h && w > size:
+		tw, th = size, h*size/w
+	case h > w && h > size:
+		tw, th = w*size/h, size
+	}
+	if tw < 1 {
+		tw = 1
+	}
+	if th < 1 {
+		th = 1
+	}
+
+	at := rgbAt(img)
+	sums := make([][4]uint64, tw*th)
+	for y := 0; y < h; y++ {
+		row := sums[y*th/h*tw:]
+		for x := 0; x < w; x++ {
+			r, g, bl := at(b.Min.X+x, b.Min.Y+y)
+			s := &row[x*tw/w]
+			s[0] += uint64(r)
+			s[1] += uint64(g)
+			s[2] += uint64(bl)
+			s[3]++
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for i, s := range sums {
+		if s[3] == 0 {
+			continue
+		}
+		dst.Pix[4*i] = uint8(s[0] / s[3])
+		dst.Pix[4*i+1] = uint8(s[1] / s[3])
+		dst.Pix[4*i+2] = uint8(s[2] / s[3])
+		dst.Pix[4*i+3] = 0xff
+	}
+	return dst
+}
+
+// rgbAt returns function reading 8 bits color values of the image pixel,
+// images produced by the decoders are read directly, others through
+// color model conversion
+func rgbAt(img image.Image) func(x, y int) (uint8, uint8, uint8) {
+	switch m := img.(type) {
+	case *image.RGBA:
+		return func(x, y int) (uint8, uint8, uint8) {
+			i := m.PixOffset(x, y)
+			return m.Pix[i], m.Pix[i+1], m.Pix[i+2]
+		}
+	case *image.Gray:
+		return func(x, y int) (uint8, uint8, uint8) {
+			v := m.Pix[m.PixOffset(x, y)]
+			return v, v, v
+		}
+	case *image.YCbCr:
+		return func(x, y int) (uint8, uint8, uint8) {
+			ci := m.COffset(x, y)
+			return color.YCbCrToRGB(m.Y[m.YOffset(x, y)], m.Cb[ci], m.Cr[ci])
+		}
+	}
+
+	return func(x, y int) (uint8, uint8, uint8) {
+		r, g, b, _ := img.At(x, y).RGBA()
+		return uint8(r >> 8), uint8(g >> 8), uint8(b >> 8)
+	}
+}
+
+// writeThumbnail makes JPEG thumbnail of the scan
+func writeThumbnail(scan, fn string, size int) error {
+	img, err := readImage(scan)
+	if err != nil {
+		return err
+	}
+
+	fp, err := os.Create(fn)
+	if err != nil {
+		return err
+	}
+
+	if err := jpeg.Encode(fp, thumbnail(img, size), &jpeg.Options{Quality: 85}); err != nil {
+		fp.Close()
+		return err
+	}
+	return fp.Close()
+}
@@ -0,0 +1,350 @@
+package contactsheet
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"image"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// TIFF tags used by the decoder
+const (
+	tiffImageWidth                = 256
+	tiffImageLength               = 257
+	tiffBitsPerSample             = 258
+	tiffCompression               = 259
+	tiffPhotometricInterpretation = 262
+	tiffStripOffsets              = 273
+	tiffSamplesPerPixel           = 277
+	tiffRowsPerStrip              = 278
+	tiffStripByteCounts           = 279
+	tiffPlanarConfiguration       = 284
+	tiffPredictor                 = 317
+	tiffTileWidth                 = 322
+)
+
+// TIFF compression schemes supported
+const (
+	tiffCompressionNone       = 1
+	tiffCompressionLZW        = 5
+	tiffCompressionDeflate    = 8
+	tiffCompressionPackBits   = 32773
+	tiffCompressionDeflateOld = 32946
+)
+
+// TIFF photometric interpretations supported
+const (
+	tiffPhotometricWhiteIsZero = 0
+	tiffPhotometricBlackIsZero = 1
+	tiffPhotometricRGB         = 2
+)
+
+// tiffPredictorHorizontal stores samples as the difference with the
+// previous pixel
+const tiffPredictorHorizontal = 2
+
+// tiffTypeSizes are the sizes of TIFF field types in bytes: BYTE, ASCII,
+// SHORT, LONG
+var tiffTypeSizes = map[uint16]uint32{1: 1, 2: 1, 3: 2, 4: 4}
+
+type tiffDecoder struct {
+	r     io.ReaderAt
+	order binary.ByteOrder
+	tags  map[uint16][]uint32
+
+	width, height, samples, bits int
+}
+
+// decodeTIFF decodes the first image of baseline TIFF file as written by
+// film scanner software: grayscale or RGB strips of 8 or 16 bits per
+// sample, uncompressed or compressed with LZW, Deflate or PackBits. 16 bits
+// samples are reduced to 8 bits, extra samples (alpha) are skipped.
+func decodeTIFF(r io.ReaderAt) (image.Image, error) {
+	d := &tiffDecoder{
+		r:    r,
+		tags: map[uint16][]uint32{},
+	}
+
+	header := make([]byte, 8)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, errors.Wrap(err, "error reading TIFF header")
+	}
+
+	switch string(header[:4]) {
+	case "II*\x00":
+		d.order = binary.LittleEndian
+	case "MM\x00*":
+		d.order = binary.BigEndian
+	default:
+		return nil, errors.New("not a TIFF file")
+	}
+
+	if err := d.readIFD(int64(d.order.Uint32(header[4:]))); err != nil {
+		return nil, err
+	}
+
+	return d.decode()
+}
+
+func (d *tiffDecoder) readIFD(offset int64) error {
+	buf := make([]byte, 2)
+	if _, err := d.r.ReadAt(buf, offset); err != nil {
+		return errors.Wrap(err, "error reading TIFF directory")
+	}
+
+	entries := make([]byte, 12*int(d.order.Uint16(buf)))
+	if _, err := d.r.ReadAt(entries, offset+2); err != nil {
+		return errors.Wrap(err, "error reading TIFF directory")
+	}
+
+	for i := 0; i < len(entries); i += 12 {
+		e := entries[i : i+12]
+		tag, typ, count := d.order.Uint16(e), d.order.Uint16(e[2:]), d.order.Uint32(e[4:])
+
+		size, ok := tiffTypeSizes[typ]
+		if !ok || typ == 2 {
+			// rational, floating point and text fields aren't used
+			continue
+		}
+
+		data := e[8:12]
+		if size*count > 4 {
+			data = make([]byte, size*count)
+			if _, err := d.r.ReadAt(data, int64(d.order.Uint32(e[8:]))); err != nil {
+				return errors.Wrapf(err, "error reading TIFF tag %d", tag)
+			}
+		}
+
+		values := make([]uint32, count)
+		for j := range values {
+			switch size {
+			case 1:
+				values[j] = uint32(data[j])
+			case 2:
+				values[j] = uint32(d.order.Uint16(data[2*j:]))
+			case 4:
+				values[j] = d.order.Uint32(data[4*j:])
+			}
+		}
+		d.tags[tag] = values
+	}
+
+	return nil
+}
+
+// value returns the first value of the tag or the default value if the tag
+// is missing
+func (d *tiffDecoder) value(tag uint16, def uint32) uint32 {
+	v, ok := d.tags[tag]
+	if !ok || len(v) == 0 {
+		return def
+	}
+	return v[0]
+}
+
+func (d *tiffDecoder) decode() (image.Image, error) {
+	d.width = int(d.value(tiffImageWidth, 0))
+	d.height = int(d.value(tiffImageLength, 0))
+	if d.width == 0 || d.height == 0 {
+		return nil, errors.New("TIFF image dimensions are missing")
+	}
+
+	if _, ok := d.tags[tiffTileWidth]; ok {
+		return nil, errors.New("tiled TIFF images are not supported")
+	}
+
+	if v := d.value(tiffPlanarConfiguration, 1); v != 1 {
+		return nil, errors.Errorf("TIFF planar configuration %d is not supported", v)
+	}
+
+	d.samples = int(d.value(tiffSamplesPerPixel, 1))
+	d.bits = int(d.value(tiffBitsPerSample, 1))
+	for _, b := range d.tags[tiffBitsPerSample] {
+		if int(b) != d.bits {
+			return nil, errors.New("TIFF images with different bits per sample are not supported")
+		}
+	}
+	if d.bits != 8 && d.bits != 16 {
+		return nil, errors.Errorf("TIFF images of %d bits per sample are not supported", d.bits)
+	}
+
+	photometric := d.value(tiffPhotometricInterpretation, 0)
+	var img image.Image
+	var set func(x, y int, px []byte)
+	switch {
+	case photometric == tiffPhotometricRGB && d.samples >= 3:
+		m := image.NewRGBA(image.Rect(0, 0, d.width, d.height))
+		img, set = m, func(x, y int, px []byte) {
+			i := m.PixOffset(x, y)
+			m.Pix[i], m.Pix[i+1], m.Pix[i+2], m.Pix[i+3] = d.sample(px, 0), d.sample(px, 1), d.sample(px, 2), 0xff
+		}
+	case photometric == tiffPhotometricBlackIsZero:
+		m := image.NewGray(image.Rect(0, 0, d.width, d.height))
+		img, set = m, func(x, y int, px []byte) {
+			m.Pix[m.PixOffset(x, y)] = d.sample(px, 0)
+		}
+	case photometric == tiffPhotometricWhiteIsZero:
+		m := image.NewGray(image.Rect(0, 0, d.width, d.height))
+		img, set = m, func(x, y int, px []byte) {
+			m.Pix[m.PixOffset(x, y)] = 0xff - d.sample(px, 0)
+		}
+	default:
+		return nil, errors.Errorf("TIFF photometric interpretation %d with %d samples per pixel is not supported", photometric, d.samples)
+	}
+
+	offsets, counts := d.tags[tiffStripOffsets], d.tags[tiffStripByteCounts]
+	if len(offsets) == 0 || len(offsets) != len(counts) {
+		return nil, errors.New("TIFF strip offsets or byte counts are missing")
+	}
+
+	rowsPerStrip := int(d.value(tiffRowsPerStrip, uint32(d.height)))
+	if rowsPerStrip == 0 || rowsPerStrip > d.height {
+		rowsPerStrip = d.height
+	}
+
+	pixelSize := d.samples * d.bits / 8
+	rowSize := d.width * pixelSize
+	for i := range offsets {
+		y0 := i * rowsPerStrip
+		if y0 >= d.height {
+			break
+		}
+		rows := rowsPerStrip
+		if y0+rows > d.height {
+			rows = d.height - y0
+		}
+
+		strip, err := d.readStrip(offsets[i], counts[i], rows*rowSize)
+		if err != nil {
+			return nil, errors.Wrapf(err, "TIFF strip %d", i)
+		}
+
+		for y := 0; y < rows; y++ {
+			row := strip[y*rowSize : (y+1)*rowSize]
+			for x := 0; x < d.width; x++ {
+				set(x, y0+y, row[x*pixelSize:(x+1)*pixelSize])
+			}
+		}
+	}
+
+	return img, nil
+}
+
+// readStrip reads and decompresses the strip, predictor is reverted
+func (d *tiffDecoder) readStrip(offset, count uint32, size int) ([]byte, error) {
+	src := make([]byte, count)
+	if _, err := d.r.ReadAt(src, int64(offset)); err != nil {
+		return nil, errors.Wrap(err, "error reading data")
+	}
+
+	var (
+		strip []byte
+		err   error
+	)
+	switch c := d.value(tiffCompression, tiffCompressionNone); c {
+	case tiffCompressionNone:
+		strip = src
+	case tiffCompressionLZW:
+		strip, err = lzwDecode(src, size)
+	case tiffCompressionDeflate, tiffCompressionDeflateOld:
+		strip, err = inflate(src, size)
+	case tiffCompressionPackBits:
+		strip, err = unpackBits(src, size)
+	default:
+		return nil, errors.Errorf("compression %d is not supported", c)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(strip) < size {
+		return nil, errors.New("unexpected end of data")
+	}
+	strip = strip[:size]
+
+	switch p := d.value(tiffPredictor, 1); p {
+	case 1:
+	case tiffPredictorHorizontal:
+		d.revertPredictor(strip)
+	default:
+		return nil, errors.Errorf("predictor %d is not supported", p)
+	}
+
+	return strip, nil
+}
+
+// revertPredictor restores samples stored as the difference with the same
+// sample of the previous pixel in the row
+func (d *tiffDecoder) revertPredictor(strip []byte) {
+	rowSize := d.width * d.samples * d.bits / 8
+	for off := 0; off+rowSize <= len(strip); off += rowSize {
+		row := strip[off : off+rowSize]
+		if d.bits == 8 {
+			for i := d.samples; i < len(row); i++ {
+				row[i] += row[i-d.samples]
+			}
+			continue
+		}
+
+		step := 2 * d.samples
+		for i := step; i+1 < len(row); i += 2 {
+			v := d.order.Uint16(row[i:]) + d.order.Uint16(row[i-step:])
+			d.order.PutUint16(row[i:], v)
+		}
+	}
+}
+
+// sample returns n-th sample of the pixel reduced to 8 bits
+func (d *tiffDecoder) sample(px []byte, n int) uint8 {
+	if d.bits == 16 {
+		return uint8(d.order.Uint16(px[2*n:]) >> 8)
+	}
+	return px[n]
+}
+
+func inflate(src []byte, size int) ([]byte, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(src))
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading deflate stream")
+	}
+	defer zr.Close()
+
+	dst := make([]byte, size)
+	n, err := io.ReadFull(zr, dst)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, errors.Wrap(err, "error reading deflate stream")
+	}
+	return dst[:n], nil
+}
+
+// unpackBits decompresses PackBits run-length encoding: every run starts
+// with a count byte followed by either count+1 literal bytes or the byte
+// repeated 1-count times
+func unpackBits(src []byte, size int) ([]byte, error) {
+	dst := make([]byte, 0, size)
+	for len(src) > 0 && len(dst) < size {
+		n := int(int8(src[0]))
+		src = src[1:]
+
+		switch {
+		case n >= 0:
+			if len(src) < n+1 {
+				return nil, errors.New("unexpected end of PackBits data")
+			}
+			dst = append(dst, src[:n+1]...)
+			src = src[n+1:]
+		case n != -128:
+			if len(src) == 0 {
+				return nil, errors.New("unexpected end of PackBits data")
+			}
+			for i := 0; i < 1-n; i++ {
+				dst = append(dst, src[0])
+			}
+			src = src[1:]
+		}
+	}
+	return dst, nil
+}
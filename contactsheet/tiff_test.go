@@ -0,0 +1,401 @@
+package contactsheet
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"image"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeTIFF(t *testing.T) {
+	r := require.New(t)
+
+	type testCase struct {
+		name  string
+		tiff  testTIFF
+		error string
+	}
+
+	tcs := []testCase{
+		{
+			name: "uncompressed RGB little endian",
+			tiff: testTIFF{order: binary.LittleEndian, samples: 3, bits: 8, photometric: tiffPhotometricRGB, compression: tiffCompressionNone},
+		},
+		{
+			name: "uncompressed RGB big endian several strips",
+			tiff: testTIFF{order: binary.BigEndian, samples: 3, bits: 8, photometric: tiffPhotometricRGB, compression: tiffCompressionNone, rowsPerStrip: 7},
+		},
+		{
+			name: "16 bits RGB with alpha",
+			tiff: testTIFF{order: binary.LittleEndian, samples: 4, bits: 16, photometric: tiffPhotometricRGB, compression: tiffCompressionNone},
+		},
+		{
+			name: "grayscale",
+			tiff: testTIFF{order: binary.LittleEndian, samples: 1, bits: 8, photometric: tiffPhotometricBlackIsZero, compression: tiffCompressionNone},
+		},
+		{
+			name: "16 bits grayscale white is zero",
+			tiff: testTIFF{order: binary.BigEndian, samples: 1, bits: 16, photometric: tiffPhotometricWhiteIsZero, compression: tiffCompressionNone},
+		},
+		{
+			name: "LZW",
+			tiff: testTIFF{order: binary.LittleEndian, samples: 3, bits: 8, photometric: tiffPhotometricRGB, compression: tiffCompressionLZW, rowsPerStrip: 16},
+		},
+		{
+			name: "LZW with predictor",
+			tiff: testTIFF{order: binary.LittleEndian, samples: 3, bits: 8, photometric: tiffPhotometricRGB, compression: tiffCompressionLZW, predictor: tiffPredictorHorizontal},
+		},
+		{
+			name: "16 bits LZW with predictor",
+			tiff: testTIFF{order: binary.BigEndian, samples: 3, bits: 16, photometric: tiffPhotometricRGB, compression: tiffCompressionLZW, predictor: tiffPredictorHorizontal},
+		},
+		{
+			name: "Deflate with predictor",
+			tiff: testTIFF{order: binary.LittleEndian, samples: 3, bits: 16, photometric: tiffPhotometricRGB, compression: tiffCompressionDeflate, predictor: tiffPredictorHorizontal},
+		},
+		{
+			name: "PackBits",
+			tiff: testTIFF{order: binary.BigEndian, samples: 1, bits: 8, photometric: tiffPhotometricBlackIsZero, compression: tiffCompressionPackBits, rowsPerStrip: 5},
+		},
+		{
+			name:  "unsupported compression",
+			tiff:  testTIFF{order: binary.LittleEndian, samples: 3, bits: 8, photometric: tiffPhotometricRGB, compression: 7},
+			error: "TIFF strip 0: compression 7 is not supported",
+		},
+		{
+			name:  "unsupported photometric interpretation",
+			tiff:  testTIFF{order: binary.LittleEndian, samples: 4, bits: 8, photometric: 5, compression: tiffCompressionNone},
+			error: "TIFF photometric interpretation 5 with 4 samples per pixel is not supported",
+		},
+		{
+			name:  "unsupported bits per sample",
+			tiff:  testTIFF{order: binary.LittleEndian, samples: 1, bits: 4, photometric: tiffPhotometricBlackIsZero, compression: tiffCompressionNone},
+			error: "TIFF images of 4 bits per sample are not supported",
+		},
+	}
+
+	for _, tc := range tcs {
+		tc.tiff.width, tc.tiff.height = 33, 21
+		data, expected := tc.tiff.encode()
+
+		img, err := decodeTIFF(bytes.NewReader(data))
+		if tc.error != "" {
+			r.Errorf(err, tc.name)
+			r.Equalf(tc.error, err.Error(), tc.name)
+			continue
+		}
+		r.NoErrorf(err, tc.name)
+		r.Equalf(expected, img, tc.name)
+	}
+}
+
+func TestDecodeTIFFNotTIFF(t *testing.T) {
+	r := require.New(t)
+
+	_, err := decodeTIFF(bytes.NewReader([]byte("\xff\xd8\xff\xe0 not a TIFF")))
+	r.Error(err)
+	r.Equal("not a TIFF file", err.Error())
+}
+
+func TestLZWDecode(t *testing.T) {
+	r := require.New(t)
+
+	rnd := rand.New(rand.NewSource(1))
+
+	// random data grows code width up to 12 bits and resets the table,
+	// repeated bytes are encoded with the codes being defined
+	data := make([]byte, 6000)
+	rnd.Read(data)
+	for i := 3000; i < 3500; i++ {
+		data[i] = 'a'
+	}
+
+	out, err := lzwDecode(lzwEncode(data), len(data))
+	r.NoError(err)
+	r.Equal(data, out)
+
+	_, err = lzwDecode([]byte{0x80, 0x7f, 0xff}, 10)
+	r.Error(err)
+	r.Equal("invalid LZW code 511", err.Error())
+}
+
+func TestUnpackBits(t *testing.T) {
+	r := require.New(t)
+
+	// example from TIFF 6.0 specification
+	src := []byte{0xfe, 0xaa, 0x02, 0x80, 0x00, 0x2a, 0xfd, 0xaa, 0x03, 0x80, 0x00, 0x2a, 0x22, 0xf7, 0xaa}
+	out, err := unpackBits(src, 24)
+	r.NoError(err)
+	r.Equal([]byte{
+		0xaa, 0xaa, 0xaa, 0x80, 0x00, 0x2a, 0xaa, 0xaa, 0xaa, 0xaa, 0x80, 0x00,
+		0x2a, 0x22, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa,
+	}, out)
+
+	_, err = unpackBits([]byte{0x05, 0x01}, 6)
+	r.Error(err)
+}
+
+// testTIFF writes TIFF file of random pixels
+type testTIFF struct {
+	order                               binary.ByteOrder
+	width, height, samples, bits        int
+	photometric, compression, predictor uint16
+	rowsPerStrip                        int
+}
+
+// encode returns the file and the image expected to be decoded from it
+func (tt testTIFF) encode() ([]byte, image.Image) {
+	rnd := rand.New(rand.NewSource(int64(tt.width * tt.samples * tt.bits)))
+
+	bytesPerSample := tt.bits / 8
+	if bytesPerSample == 0 {
+		bytesPerSample = 1
+	}
+	rowSize := tt.width * tt.samples * bytesPerSample
+	raw := make([]byte, rowSize*tt.height)
+	rnd.Read(raw)
+
+	var expected image.Image
+	high := func(i int) uint8 {
+		if bytesPerSample == 2 {
+			return uint8(tt.order.Uint16(raw[i:]) >> 8)
+		}
+		return raw[i]
+	}
+	px := tt.samples * bytesPerSample
+	switch tt.photometric {
+	case tiffPhotometricRGB:
+		m := image.NewRGBA(image.Rect(0, 0, tt.width, tt.height))
+		for i := 0; i < tt.width*tt.height; i++ {
+			m.Pix[4*i] = high(i * px)
+			m.Pix[4*i+1] = high(i*px + bytesPerSample)
+			m.Pix[4*i+2] = high(i*px + 2*bytesPerSample)
+			m.Pix[4*i+3] = 0xff
+		}
+		expected = m
+	case tiffPhotometricBlackIsZero, tiffPhotometricWhiteIsZero:
+		m := image.NewGray(image.Rect(0, 0, tt.width, tt.height))
+		for i := range m.Pix {
+			m.Pix[i] = high(i * px)
+			if tt.photometric == tiffPhotometricWhiteIsZero {
+				m.Pix[i] = 0xff - m.Pix[i]
+			}
+		}
+		expected = m
+	}
+
+	rowsPerStrip := tt.rowsPerStrip
+	if rowsPerStrip == 0 {
+		rowsPerStrip = tt.height
+	}
+
+	buf := &bytes.Buffer{}
+	buf.Write(make([]byte, 8))
+
+	var offsets, counts []uint32
+	for y := 0; y < tt.height; y += rowsPerStrip {
+		end := y + rowsPerStrip
+		if end > tt.height {
+			end = tt.height
+		}
+		strip := append([]byte{}, raw[y*rowSize:end*rowSize]...)
+
+		if tt.predictor == tiffPredictorHorizontal {
+			for off := 0; off < len(strip); off += rowSize {
+				row := strip[off : off+rowSize]
+				step := tt.samples * bytesPerSample
+				for i := len(row) - bytesPerSample; i >= step; i -= bytesPerSample {
+					if bytesPerSample == 2 {
+						tt.order.PutUint16(row[i:], tt.order.Uint16(row[i:])-tt.order.Uint16(row[i-step:]))
+					} else {
+						row[i] -= row[i-step]
+					}
+				}
+			}
+		}
+
+		switch tt.compression {
+		case tiffCompressionLZW:
+			strip = lzwEncode(strip)
+		case tiffCompressionDeflate:
+			zbuf := &bytes.Buffer{}
+			zw := zlib.NewWriter(zbuf)
+			zw.Write(strip)
+			zw.Close()
+			strip = zbuf.Bytes()
+		case tiffCompressionPackBits:
+			strip = packBits(strip)
+		}
+
+		offsets = append(offsets, uint32(buf.Len()))
+		counts = append(counts, uint32(len(strip)))
+		buf.Write(strip)
+	}
+
+	bits := make([]uint32, tt.samples)
+	for i := range bits {
+		bits[i] = uint32(tt.bits)
+	}
+
+	fields := map[uint16][]uint32{
+		tiffImageWidth:                {uint32(tt.width)},
+		tiffImageLength:               {uint32(tt.height)},
+		tiffBitsPerSample:             bits,
+		tiffCompression:               {uint32(tt.compression)},
+		tiffPhotometricInterpretation: {uint32(tt.photometric)},
+		tiffStripOffsets:              offsets,
+		tiffSamplesPerPixel:           {uint32(tt.samples)},
+		tiffRowsPerStrip:              {uint32(rowsPerStrip)},
+		tiffStripByteCounts:           counts,
+	}
+	if tt.predictor != 0 {
+		fields[tiffPredictor] = []uint32{uint32(tt.predictor)}
+	}
+
+	tags := []int{}
+	for tag := range fields {
+		tags = append(tags, int(tag))
+	}
+	sort.Ints(tags)
+
+	if buf.Len()%2 == 1 {
+		buf.WriteByte(0)
+	}
+	ifd := uint32(buf.Len())
+	extra := ifd + 2 + 12*uint32(len(tags)) + 4
+
+	entries := &bytes.Buffer{}
+	values := &bytes.Buffer{}
+	put16 := func(w *bytes.Buffer, v uint16) {
+		b := make([]byte, 2)
+		tt.order.PutUint16(b, v)
+		w.Write(b)
+	}
+	put32 := func(w *bytes.Buffer, v uint32) {
+		b := make([]byte, 4)
+		tt.order.PutUint32(b, v)
+		w.Write(b)
+	}
+
+	put16(entries, uint16(len(tags)))
+	for _, tag := range tags {
+		vs := fields[uint16(tag)]
+		put16(entries, uint16(tag))
+		put16(entries, 4)
+		put32(entries, uint32(len(vs)))
+		if len(vs) == 1 {
+			put32(entries, vs[0])
+			continue
+		}
+		put32(entries, extra+uint32(values.Len()))
+		for _, v := range vs {
+			put32(values, v)
+		}
+	}
+	put32(entries, 0)
+
+	buf.Write(entries.Bytes())
+	buf.Write(values.Bytes())
+
+	data := buf.Bytes()
+	if tt.order == binary.LittleEndian {
+		copy(data, "II*\x00")
+	} else {
+		copy(data, "MM\x00*")
+	}
+	tt.order.PutUint32(data[4:], ifd)
+
+	return data, expected
+}
+
+// lzwEncode compresses the data with TIFF flavor of LZW
+func lzwEncode(src []byte) []byte {
+	var (
+		dst   []byte
+		acc   uint32
+		nbits uint
+		width uint = lzwMinWidth
+		next       = lzwFirst
+		table      = map[string]int{}
+	)
+
+	put := func(code int) {
+		acc = acc<<width | uint32(code)
+		nbits += width
+		for nbits >= 8 {
+			dst = append(dst, byte(acc>>(nbits-8)))
+			nbits -= 8
+		}
+	}
+	code := func(s string) (int, bool) {
+		if len(s) == 1 {
+			return int(s[0]), true
+		}
+		c, ok := table[s]
+		return c, ok
+	}
+
+	put(lzwClear)
+	w := ""
+	for _, c := range src {
+		wc := w + string([]byte{c})
+		if _, ok := code(wc); ok || w == "" {
+			w = wc
+			continue
+		}
+
+		cw, _ := code(w)
+		put(cw)
+		table[wc] = next
+		next++
+		if next >= 1<<width && width < lzwMaxWidth {
+			width++
+		}
+		if next == 1<<lzwMaxWidth-2 {
+			put(lzwClear)
+			width, next, table = lzwMinWidth, lzwFirst, map[string]int{}
+		}
+		w = string([]byte{c})
+	}
+	if w != "" {
+		cw, _ := code(w)
+		put(cw)
+	}
+	put(lzwEOI)
+	if nbits > 0 {
+		dst = append(dst, byte(acc<<(8-nbits)))
+	}
+
+	return dst
+}
+
+// packBits compresses runs of 3 or more equal bytes, other bytes are
+// written as literals
+func packBits(src []byte) []byte {
+	dst := []byte{}
+	for len(src) > 0 {
+		n := 1
+		for n < len(src) && n < 128 && src[n] == src[0] {
+			n++
+		}
+		if n >= 3 {
+			dst = append(dst, byte(int8(1-n)), src[0])
+			src = src[n:]
+			continue
+		}
+
+		n = 1
+		for n < len(src) && n < 128 && !(n+2 < len(src) && src[n] == src[n+1] && src[n] == src[n+2]) {
+			n++
+		}
+		dst = append(dst, byte(n-1))
+		dst = append(dst, src[:n]...)
+		src = src[n:]
+	}
+	return dst
+}
@@ -0,0 +1,167 @@
+package correction
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+// Clock corrects timestamps recorded by the camera clock
+type Clock struct {
+	offset time.Duration
+
+	// linear drift references, nil for fixed offset
+	from, to *types.ClockReference
+}
+
+// NewClock creates new Clock object from correction settings
+func NewClock(cc types.ClockCorrection) (*Clock, error) {
+	if cc.Offset != nil && len(cc.References) > 0 {
+		return nil, errors.New("clock correction could be set either by offset or by references, not both")
+	}
+
+	switch len(cc.References) {
+	case 0:
+		c := &Clock{}
+		if cc.Offset != nil {
+			c.offset = *cc.Offset
+		}
+		return c, nil
+	case 1:
+		return &Clock{
+			offset: cc.References[0].Actual.Sub(cc.References[0].Camera),
+		}, nil
+	case 2:
+		from, to := cc.References[0], cc.References[1]
+		if to.Camera.Before(from.Camera) {
+			from, to = to, from
+		}
+		if from.Camera.Equal(to.Camera) {
+			return nil, errors.New("clock correction references must have different camera time")
+		}
+		return &Clock{
+			from: &from,
+			to:   &to,
+		}, nil
+	}
+
+	return nil, errors.Errorf("clock correction accepts up to 2 references, %d given", len(cc.References))
+}
+
+// Offset returns the correction applied to the timestamp
+func (c *Clock) Offset(t time.Time) time.Duration {
+	if c.from == nil {
+		return c.offset
+	}
+
+	d1 := c.from.Actual.Sub(c.from.Camera)
+	d2 := c.to.Actual.Sub(c.to.Camera)
+	k := float64(t.Sub(c.from.Camera)) / float64(c.to.Camera.Sub(c.from.Camera))
+
+	return (d1 + time.Duration(float64(d2-d1)*k)).Round(time.Second)
+}
+
+// Correct returns corrected timestamp
+func (c *Clock) Correct(t time.Time) time.Time {
+	return t.Add(c.Offset(t))
+}
+
+func (c *Clock) String() string {
+	if c.from == nil {
+		return fmt.Sprintf("fixed offset %s", formatDuration(c.offset))
+	}
+
+	return fmt.Sprintf("linear drift from %s at %s to %s at %s",
+		formatDuration(c.from.Actual.Sub(c.from.Camera)), c.from.Camera.Format(time.RFC3339),
+		formatDuration(c.to.Actual.Sub(c.to.Camera)), c.to.Camera.Format(time.RFC3339),
+	)
+}
+
+// ClockReport describes correction applied to the film
+type ClockReport struct {
+	CameraID  uint8
+	FilmID    int64
+	Method    string
+	Frames    int
+	MinOffset time.Duration
+	MaxOffset time.Duration
+}
+
+func (r ClockReport) String() string {
+	offset := formatDuration(r.MinOffset)
+	if r.MinOffset != r.MaxOffset {
+		offset += ".." + formatDuration(r.MaxOffset)
+	}
+
+	return fmt.Sprintf("film %02d-%03d: %s, %d frames shifted by %s", r.CameraID, r.FilmID, r.Method, r.Frames, offset)
+}
+
+// ApplyClock corrects every camera clock timestamp of the films in place
+// using the correction returned by fn for the film camera ID. Films which
+// camera has no correction are left untouched.
+func ApplyClock(films []*types.Film, fn func(cameraID uint8) *types.ClockCorrection) ([]ClockReport, error) {
+	reports := []ClockReport{}
+	for _, film := range films {
+		if film.CameraID == nil {
+			continue
+		}
+
+		cc := fn(*film.CameraID)
+		if cc == nil {
+			continue
+		}
+
+		c, err := NewClock(*cc)
+		if err != nil {
+			return nil, errors.Wrapf(err, "camera %d", *film.CameraID)
+		}
+
+		report := ClockReport{
+			CameraID: *film.CameraID,
+			Method:   c.String(),
+		}
+		if film.ID != nil {
+			report.FilmID = *film.ID
+		}
+
+		correct := func(t *time.Time) *time.Time {
+			if t == nil || t.IsZero() {
+				return t
+			}
+			return types.PtrTime(c.Correct(*t))
+		}
+
+		film.FilmLoadedTimestamp = correct(film.FilmLoadedTimestamp)
+		for _, f := range film.Frames {
+			f.BatteryLoadedDate = correct(f.BatteryLoadedDate)
+			if f.Timestamp == nil || f.Timestamp.IsZero() {
+				continue
+			}
+
+			offset := c.Offset(*f.Timestamp)
+			if report.Frames == 0 || offset < report.MinOffset {
+				report.MinOffset = offset
+			}
+			if report.Frames == 0 || offset > report.MaxOffset {
+				report.MaxOffset = offset
+			}
+			report.Frames++
+
+			f.Timestamp = types.PtrTime(f.Timestamp.Add(offset))
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+func formatDuration(d time.Duration) string {
+	if d < 0 {
+		return d.String()
+	}
+	return "+" + d.String()
+}
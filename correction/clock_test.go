@@ -0,0 +1,170 @@
+package correction
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+func TestClock(t *testing.T) {
+	r := require.New(t)
+
+	type testCase struct {
+		name      string
+		cc        types.ClockCorrection
+		input     time.Time
+		expOutput time.Time
+		expString string
+		expError  string
+	}
+
+	tcs := []testCase{
+		{
+			name:      "no correction",
+			cc:        types.ClockCorrection{},
+			input:     time.Date(2019, 10, 7, 20, 2, 18, 0, time.UTC),
+			expOutput: time.Date(2019, 10, 7, 20, 2, 18, 0, time.UTC),
+			expString: "fixed offset +0s",
+		},
+		{
+			name:      "fixed offset",
+			cc:        types.ClockCorrection{Offset: types.PtrDuration(-time.Hour)},
+			input:     time.Date(2019, 10, 7, 20, 2, 18, 0, time.UTC),
+			expOutput: time.Date(2019, 10, 7, 19, 2, 18, 0, time.UTC),
+			expString: "fixed offset -1h0m0s",
+		},
+		{
+			name: "single reference",
+			cc: types.ClockCorrection{References: []types.ClockReference{
+				{
+					Camera: time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC),
+					Actual: time.Date(2019, 10, 1, 12, 1, 30, 0, time.UTC),
+				},
+			}},
+			input:     time.Date(2019, 10, 7, 20, 2, 18, 0, time.UTC),
+			expOutput: time.Date(2019, 10, 7, 20, 3, 48, 0, time.UTC),
+			expString: "fixed offset +1m30s",
+		},
+		{
+			name: "linear drift",
+			cc: types.ClockCorrection{References: []types.ClockReference{
+				{
+					Camera: time.Date(2019, 10, 11, 12, 0, 0, 0, time.UTC),
+					Actual: time.Date(2019, 10, 11, 12, 3, 0, 0, time.UTC),
+				},
+				{
+					Camera: time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC),
+					Actual: time.Date(2019, 10, 1, 12, 1, 0, 0, time.UTC),
+				},
+			}},
+			input:     time.Date(2019, 10, 6, 12, 0, 0, 0, time.UTC),
+			expOutput: time.Date(2019, 10, 6, 12, 2, 0, 0, time.UTC),
+			expString: "linear drift from +1m0s at 2019-10-01T12:00:00Z to +3m0s at 2019-10-11T12:00:00Z",
+		},
+		{
+			name: "linear drift extrapolated",
+			cc: types.ClockCorrection{References: []types.ClockReference{
+				{
+					Camera: time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC),
+					Actual: time.Date(2019, 10, 1, 12, 1, 0, 0, time.UTC),
+				},
+				{
+					Camera: time.Date(2019, 10, 11, 12, 0, 0, 0, time.UTC),
+					Actual: time.Date(2019, 10, 11, 12, 3, 0, 0, time.UTC),
+				},
+			}},
+			input:     time.Date(2019, 10, 21, 12, 0, 0, 0, time.UTC),
+			expOutput: time.Date(2019, 10, 21, 12, 5, 0, 0, time.UTC),
+			expString: "linear drift from +1m0s at 2019-10-01T12:00:00Z to +3m0s at 2019-10-11T12:00:00Z",
+		},
+		{
+			name: "both offset and references",
+			cc: types.ClockCorrection{
+				Offset:     types.PtrDuration(time.Hour),
+				References: []types.ClockReference{{}},
+			},
+			expError: "clock correction could be set either by offset or by references, not both",
+		},
+		{
+			name:     "too many references",
+			cc:       types.ClockCorrection{References: []types.ClockReference{{}, {}, {}}},
+			expError: "clock correction accepts up to 2 references, 3 given",
+		},
+		{
+			name:     "references with the same camera time",
+			cc:       types.ClockCorrection{References: []types.ClockReference{{}, {}}},
+			expError: "clock correction references must have different camera time",
+		},
+	}
+
+	for _, tc := range tcs {
+		c, err := NewClock(tc.cc)
+		if tc.expError != "" {
+			r.Errorf(err, tc.name)
+			r.Equalf(tc.expError, err.Error(), tc.name)
+			continue
+		}
+
+		r.NoErrorf(err, tc.name)
+		r.Truef(tc.expOutput.Equal(c.Correct(tc.input)), "%s: %s != %s", tc.name, tc.expOutput, c.Correct(tc.input))
+		r.Equalf(tc.expString, c.String(), tc.name)
+	}
+}
+
+func TestApplyClock(t *testing.T) {
+	r := require.New(t)
+
+	films := []*types.Film{
+		{
+			ID:                  types.PtrInt64(139),
+			CameraID:            types.PtrUint8(1),
+			FilmLoadedTimestamp: types.PtrTime(time.Date(2019, 9, 28, 10, 21, 32, 0, time.UTC)),
+			Frames: []*types.Frame{
+				{
+					Number:    types.PtrInt64(1),
+					Timestamp: types.PtrTime(time.Date(2019, 10, 7, 20, 2, 18, 0, time.UTC)),
+				},
+				{
+					Number: types.PtrInt64(2),
+				},
+			},
+		},
+		{
+			ID:       types.PtrInt64(12),
+			CameraID: types.PtrUint8(2),
+			Frames: []*types.Frame{
+				{
+					Number:    types.PtrInt64(1),
+					Timestamp: types.PtrTime(time.Date(2019, 10, 7, 20, 2, 18, 0, time.UTC)),
+				},
+			},
+		},
+	}
+
+	reports, err := ApplyClock(films, func(cameraID uint8) *types.ClockCorrection {
+		if cameraID == 1 {
+			return &types.ClockCorrection{Offset: types.PtrDuration(-time.Hour)}
+		}
+		return nil
+	})
+	r.NoError(err)
+	r.Equal([]ClockReport{
+		{
+			CameraID:  1,
+			FilmID:    139,
+			Method:    "fixed offset -1h0m0s",
+			Frames:    1,
+			MinOffset: -time.Hour,
+			MaxOffset: -time.Hour,
+		},
+	}, reports)
+	r.Equal("film 01-139: fixed offset -1h0m0s, 1 frames shifted by -1h0m0s", reports[0].String())
+
+	r.Equal(time.Date(2019, 9, 28, 9, 21, 32, 0, time.UTC), *films[0].FilmLoadedTimestamp)
+	r.Equal(time.Date(2019, 10, 7, 19, 2, 18, 0, time.UTC), *films[0].Frames[0].Timestamp)
+	r.Nil(films[0].Frames[1].Timestamp)
+	r.Equal(time.Date(2019, 10, 7, 20, 2, 18, 0, time.UTC), *films[1].Frames[0].Timestamp)
+}
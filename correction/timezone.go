@@ -0,0 +1,107 @@
+package correction
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+// TimezoneReport describes timezone overrides applied to the film
+type TimezoneReport struct {
+	CameraID uint8
+	FilmID   int64
+	Timezone types.Timezone
+	Frames   int
+}
+
+func (r TimezoneReport) String() string {
+	return fmt.Sprintf("film %02d-%03d: %d frames moved to %s", r.CameraID, r.FilmID, r.Frames, r.Timezone)
+}
+
+// ApplyTimezones reinterprets wall clock time of film timestamps in the
+// timezone of the first matching override. Timestamps no override matches
+// are left in the camera timezone.
+func ApplyTimezones(films []*types.Film, overrides []types.TimezoneOverride) ([]TimezoneReport, error) {
+	locations := make([]*time.Location, len(overrides))
+	for i, o := range overrides {
+		loc, err := time.LoadLocation(o.Timezone)
+		if err != nil {
+			return nil, errors.Wrapf(err, "timezone override #%d", i+1)
+		}
+		locations[i] = loc
+	}
+
+	relocate := func(film *types.Film, t *time.Time) (*time.Time, int) {
+		if t == nil || t.IsZero() {
+			return t, -1
+		}
+		for i, o := range overrides {
+			if o.Matches(film, *t) {
+				return types.PtrTime(Relocate(*t, locations[i])), i
+			}
+		}
+		return t, -1
+	}
+
+	reports := []TimezoneReport{}
+	for _, film := range films {
+		film.FilmLoadedTimestamp, _ = relocate(film, film.FilmLoadedTimestamp)
+
+		counts := make([]int, len(overrides))
+		for _, f := range film.Frames {
+			f.BatteryLoadedDate, _ = relocate(film, f.BatteryLoadedDate)
+
+			var idx int
+			f.Timestamp, idx = relocate(film, f.Timestamp)
+			if idx >= 0 {
+				counts[idx]++
+			}
+		}
+
+		for i, n := range counts {
+			if n == 0 {
+				continue
+			}
+
+			report := TimezoneReport{
+				Timezone: overrides[i].Timezone,
+				Frames:   n,
+			}
+			if film.CameraID != nil {
+				report.CameraID = *film.CameraID
+			}
+			if film.ID != nil {
+				report.FilmID = *film.ID
+			}
+			reports = append(reports, report)
+		}
+	}
+
+	return reports, nil
+}
+
+// Relocate returns the time with the same wall clock in the location
+func Relocate(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
+
+// RelocateFilm moves every film timestamp to the location keeping the
+// wall clock time
+func RelocateFilm(film *types.Film, loc *time.Location) {
+	relocate := func(t *time.Time) *time.Time {
+		if t == nil || t.IsZero() {
+			return t
+		}
+		return types.PtrTime(Relocate(*t, loc))
+	}
+
+	film.FilmLoadedTimestamp = relocate(film.FilmLoadedTimestamp)
+	for _, f := range film.Frames {
+		f.Timestamp = relocate(f.Timestamp)
+		f.Date = relocate(f.Date)
+		f.BatteryLoadedDate = relocate(f.BatteryLoadedDate)
+	}
+}
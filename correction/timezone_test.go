@@ -0,0 +1,89 @@
+package correction
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+func TestApplyTimezones(t *testing.T) {
+	r := require.New(t)
+
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	r.NoError(err)
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	r.NoError(err)
+
+	ny, err := time.LoadLocation("America/New_York")
+	r.NoError(err)
+
+	films := []*types.Film{
+		{
+			ID:                  types.PtrInt64(139),
+			CameraID:            types.PtrUint8(1),
+			FilmLoadedTimestamp: types.PtrTime(time.Date(2019, 9, 28, 10, 21, 32, 0, moscow)),
+			Frames: []*types.Frame{
+				{
+					Number:    types.PtrInt64(1),
+					Timestamp: types.PtrTime(time.Date(2019, 10, 7, 20, 2, 18, 0, moscow)),
+				},
+				{
+					Number:    types.PtrInt64(2),
+					Timestamp: types.PtrTime(time.Date(2019, 10, 20, 11, 0, 0, 0, moscow)),
+				},
+				{
+					Number: types.PtrInt64(3),
+				},
+			},
+		},
+		{
+			ID:       types.PtrInt64(140),
+			CameraID: types.PtrUint8(1),
+			Frames: []*types.Frame{
+				{
+					Number:    types.PtrInt64(1),
+					Timestamp: types.PtrTime(time.Date(2019, 10, 21, 9, 0, 0, 0, moscow)),
+				},
+			},
+		},
+	}
+
+	reports, err := ApplyTimezones(films, []types.TimezoneOverride{
+		{
+			CameraID: types.PtrUint8(1),
+			FilmID:   types.PtrInt64(139),
+			From:     types.PtrTime(time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)),
+			To:       types.PtrTime(time.Date(2019, 10, 7, 0, 0, 0, 0, time.UTC)),
+			Timezone: "Asia/Tokyo",
+		},
+		{
+			From:     types.PtrTime(time.Date(2019, 10, 15, 0, 0, 0, 0, time.UTC)),
+			Timezone: "America/New_York",
+		},
+	})
+	r.NoError(err)
+	r.Equal([]TimezoneReport{
+		{CameraID: 1, FilmID: 139, Timezone: "Asia/Tokyo", Frames: 1},
+		{CameraID: 1, FilmID: 139, Timezone: "America/New_York", Frames: 1},
+		{CameraID: 1, FilmID: 140, Timezone: "America/New_York", Frames: 1},
+	}, reports)
+	r.Equal("film 01-139: 1 frames moved to Asia/Tokyo", reports[0].String())
+
+	r.Equal(time.Date(2019, 9, 28, 10, 21, 32, 0, moscow), *films[0].FilmLoadedTimestamp)
+	r.Equal(time.Date(2019, 10, 7, 20, 2, 18, 0, tokyo), *films[0].Frames[0].Timestamp)
+	r.Equal(time.Date(2019, 10, 20, 11, 0, 0, 0, ny), *films[0].Frames[1].Timestamp)
+	r.Nil(films[0].Frames[2].Timestamp)
+	r.Equal(time.Date(2019, 10, 21, 9, 0, 0, 0, ny), *films[1].Frames[0].Timestamp)
+}
+
+func TestApplyTimezonesInvalidTimezone(t *testing.T) {
+	r := require.New(t)
+
+	_, err := ApplyTimezones(nil, []types.TimezoneOverride{{Timezone: "Mars/Olympus_Mons"}})
+	r.Error(err)
+	r.Equal("timezone override #1: unknown time zone Mars/Olympus_Mons", err.Error())
+}
@@ -2,6 +2,8 @@ package tagger
 
 import (
 	"fmt"
+	"math"
+	"os/exec"
 	"strconv"
 	"strings"
 	"time"
@@ -92,6 +94,20 @@ func NewFromFrame(binary, filename string, f *types.Frame) *ExifTool {
 	return et
 }
 
+// RemoveCanonTags removes Canon maker notes tags set from the frame, these
+// are only valid for the frames recorded by Canon cameras
+func (e *ExifTool) RemoveCanonTags() *ExifTool {
+	options := []ExifToolOption{}
+	for _, o := range e.options {
+		if !strings.HasPrefix(o.key, "Canon:") && !strings.HasPrefix(o.key, "CanonCustom:") {
+			options = append(options, o)
+		}
+	}
+	e.options = options
+
+	return e
+}
+
 // Copyright sets EXIF copyright values
 func (e *ExifTool) Copyright(cr string) *ExifTool {
 	e.add("IFD0:Artist", cr)
@@ -100,6 +116,14 @@ func (e *ExifTool) Copyright(cr string) *ExifTool {
 	return e
 }
 
+// Artist sets EXIF artist value overriding the one set by Copyright
+func (e *ExifTool) Artist(a string) *ExifTool {
+	e.remove("IFD0:Artist")
+	e.add("IFD0:Artist", a)
+
+	return e
+}
+
 // Aperture sets Aperture parameters to exiftool command
 func (e *ExifTool) Aperture(v float64) *ExifTool {
 	vs := strconv.FormatFloat(v, 'f', -1, 64)
@@ -110,6 +134,13 @@ func (e *ExifTool) Aperture(v float64) *ExifTool {
 	return e
 }
 
+// Description sets image description to exiftool command
+func (e *ExifTool) Description(d string) *ExifTool {
+	e.add("XMP-dc:Description", d)
+
+	return e
+}
+
 // Exposure sets Exposure value to exiftool command
 func (e *ExifTool) Exposure(t string) *ExifTool {
 	e.add("ExposureTime", t)
@@ -141,9 +172,29 @@ func (e *ExifTool) FocalLength(fl int64) *ExifTool {
 	return e
 }
 
-// GeoTag adds `-geotag` to exiftool command
-func (e *ExifTool) GeoTag(filename string) *ExifTool {
-	e.add("GeoTag", filename)
+// GPSPosition sets GPS location tags to exiftool command
+func (e *ExifTool) GPSPosition(p types.Position) *ExifTool {
+	latRef, lonRef := "N", "E"
+	if p.Latitude < 0 {
+		latRef = "S"
+	}
+	if p.Longitude < 0 {
+		lonRef = "W"
+	}
+
+	e.add("GPSLatitude", strconv.FormatFloat(math.Abs(p.Latitude), 'f', 6, 64))
+	e.add("GPSLatitudeRef", latRef)
+	e.add("GPSLongitude", strconv.FormatFloat(math.Abs(p.Longitude), 'f', 6, 64))
+	e.add("GPSLongitudeRef", lonRef)
+
+	if p.Altitude != nil {
+		altRef := "Above Sea Level"
+		if *p.Altitude < 0 {
+			altRef = "Below Sea Level"
+		}
+		e.add("GPSAltitude", strconv.FormatFloat(math.Abs(*p.Altitude), 'f', 1, 64))
+		e.add("GPSAltitudeRef", altRef)
+	}
 
 	return e
 }
@@ -157,6 +208,56 @@ func (e *ExifTool) ISO(v int64) *ExifTool {
 	return e
 }
 
+// Keywords appends keywords to IPTC Keywords and XMP Subject lists. Every
+// keyword is removed before it's added so the lists don't get duplicates
+// when the command is run again.
+func (e *ExifTool) Keywords(kws ...string) *ExifTool {
+	for _, kw := range kws {
+		e.delete("Keywords", kw)
+		e.append("Keywords", kw)
+		e.delete("XMP-dc:Subject", kw)
+		e.append("XMP-dc:Subject", kw)
+	}
+
+	return e
+}
+
+// Lens sets lens identification tags to exiftool command
+func (e *ExifTool) Lens(l types.Lens) *ExifTool {
+	e.add("LensModel", l.Name)
+
+	if l.Make != "" {
+		e.add("LensMake", l.Make)
+	}
+
+	e.add("LensInfo", strings.Join([]string{
+		strconv.FormatFloat(l.FocalLength.Min, 'f', -1, 64),
+		strconv.FormatFloat(l.FocalLength.Max, 'f', -1, 64),
+		strconv.FormatFloat(l.MaxAperture.Min, 'f', -1, 64),
+		strconv.FormatFloat(l.MaxAperture.Max, 'f', -1, 64),
+	}, " "))
+
+	if l.SerialNumber != "" {
+		e.add("LensSerialNumber", l.SerialNumber)
+	}
+
+	return e
+}
+
+// LensModel sets lens name only for lenses missing in the lens catalog
+func (e *ExifTool) LensModel(name string) *ExifTool {
+	e.add("LensModel", name)
+
+	return e
+}
+
+// Location sets location name to exiftool command
+func (e *ExifTool) Location(l string) *ExifTool {
+	e.add("XMP-iptcCore:Location", l)
+
+	return e
+}
+
 // Make sets Make parameters to exiftool command
 func (e *ExifTool) Make(m string) *ExifTool {
 	e.add("Make", m)
@@ -171,6 +272,13 @@ func (e *ExifTool) Model(m string) *ExifTool {
 	return e
 }
 
+// OwnerName sets camera owner name to exiftool command
+func (e *ExifTool) OwnerName(o string) *ExifTool {
+	e.add("OwnerName", o)
+
+	return e
+}
+
 // SerialNumber sets SerialNumber parameters to exiftool command
 func (e *ExifTool) SerialNumber(sn string) *ExifTool {
 	e.add("SerialNumber", sn)
@@ -185,31 +293,60 @@ func (e *ExifTool) SetDateTimeDigitizedFromCreateDate() *ExifTool {
 	return e
 }
 
-// Timestamp sets the timestamp shot made on
+// Title sets image title to exiftool command
+func (e *ExifTool) Title(t string) *ExifTool {
+	e.add("XMP-dc:Title", t)
+
+	return e
+}
+
+// Timestamp sets the timestamp shot made on along with EXIF 2.31 timezone
+// offset tags
 func (e *ExifTool) Timestamp(t time.Time) *ExifTool {
 	ts := t.Format(time.RFC3339)
+	offset := t.Format("-07:00")
 	e.add("DateTimeOriginal", ts)
+	e.add("OffsetTimeOriginal", offset)
 	e.add("ModifyDate", ts)
+	e.add("OffsetTime", offset)
 
 	return e
 }
 
-// GeoTime sets Geotime tag to align against GPS tracking files
-func (e *ExifTool) GeoTime(t time.Time) *ExifTool {
-	ts := t.Format(types.GeoTimeFormat)
-	e.add("GeoTime", ts)
+func (e *ExifTool) add(k, v string) {
+	e.options = append(e.options, ExifToolOption{
+		key:      k,
+		value:    v,
+		operator: "=",
+	})
+}
 
-	return e
+func (e *ExifTool) append(k, v string) {
+	e.options = append(e.options, ExifToolOption{
+		key:      k,
+		value:    v,
+		operator: "+=",
+	})
 }
 
-func (e *ExifTool) add(k, v string) {
+func (e *ExifTool) delete(k, v string) {
 	e.options = append(e.options, ExifToolOption{
 		key:      k,
 		value:    v,
-		operator: "=",
+		operator: "-=",
 	})
 }
 
+func (e *ExifTool) remove(k string) {
+	options := e.options[:0]
+	for _, o := range e.options {
+		if o.key != k {
+			options = append(options, o)
+		}
+	}
+	e.options = options
+}
+
 func (e *ExifTool) copy(from, to string) {
 	e.options = append(e.options, ExifToolOption{
 		key:      to,
@@ -237,3 +374,23 @@ func (e *ExifTool) Cmd() string {
 func (e *ExifTool) Options() []ExifToolOption {
 	return e.options
 }
+
+// Filename returns the name of the file the command tags
+func (e *ExifTool) Filename() string {
+	return e.filename
+}
+
+// Args returns exiftool command arguments, unlike Cmd the values are not
+// quoted
+func (e *ExifTool) Args() []string {
+	args := append([]string{}, exifToolDefaultOpts...)
+	for _, o := range e.Options() {
+		args = append(args, "-"+o.key+o.operator+o.value)
+	}
+	return append(args, e.filename)
+}
+
+// Run executes exiftool command and returns its output
+func (e *ExifTool) Run() ([]byte, error) {
+	return exec.Command(e.binary, e.Args()...).CombinedOutput()
+}
@@ -67,6 +67,88 @@ func TestExiftoolOptions(t *testing.T) {
 			},
 			expCommand: `"-ExposureCompensation=0.5" "test-file-with-exposure-compensation"`,
 		},
+		{
+			name:  "GPS position specified",
+			fname: "test-file-with-gps",
+			f: func(e *ExifTool) {
+				e.GPSPosition(types.Position{
+					Latitude:  55.755833,
+					Longitude: -37.617222,
+					Altitude:  types.PtrFloat64(-12.34),
+				})
+			},
+			expCommand: `"-GPSLatitude=55.755833" "-GPSLatitudeRef=N" "-GPSLongitude=37.617222" "-GPSLongitudeRef=W" "-GPSAltitude=12.3" "-GPSAltitudeRef=Below Sea Level" "test-file-with-gps"`,
+		},
+		{
+			name:  "GPS position without altitude specified",
+			fname: "test-file-with-gps",
+			f: func(e *ExifTool) {
+				e.GPSPosition(types.Position{
+					Latitude:  -33.856784,
+					Longitude: 151.215297,
+				})
+			},
+			expCommand: `"-GPSLatitude=33.856784" "-GPSLatitudeRef=S" "-GPSLongitude=151.215297" "-GPSLongitudeRef=E" "test-file-with-gps"`,
+		},
+		{
+			name:  "keywords specified",
+			fname: "test-file-with-keywords",
+			f: func(e *ExifTool) {
+				e.Keywords("Kodak Portra 400", "C-41")
+			},
+			expCommand: `"-Keywords-=Kodak Portra 400" "-Keywords+=Kodak Portra 400" "-XMP-dc:Subject-=Kodak Portra 400" "-XMP-dc:Subject+=Kodak Portra 400" "-Keywords-=C-41" "-Keywords+=C-41" "-XMP-dc:Subject-=C-41" "-XMP-dc:Subject+=C-41" "test-file-with-keywords"`,
+		},
+		{
+			name:  "location specified",
+			fname: "test-file-with-location",
+			f: func(e *ExifTool) {
+				e.Location("Kyoto, Japan")
+			},
+			expCommand: `"-XMP-iptcCore:Location=Kyoto, Japan" "test-file-with-location"`,
+		},
+		{
+			name:  "title and description specified",
+			fname: "test-file-with-title",
+			f: func(e *ExifTool) {
+				e.Title("Trip to Kyoto")
+				e.Description("Pushed one stop")
+			},
+			expCommand: `"-XMP-dc:Title=Trip to Kyoto" "-XMP-dc:Description=Pushed one stop" "test-file-with-title"`,
+		},
+		{
+			name:  "lens specified",
+			fname: "test-file-with-lens",
+			f: func(e *ExifTool) {
+				e.Lens(types.Lens{
+					Name:         "EF24-105mm f/3.5-5.6 IS STM",
+					Make:         "Canon",
+					FocalLength:  types.Range{Min: 24, Max: 105},
+					MaxAperture:  types.Range{Min: 3.5, Max: 5.6},
+					SerialNumber: "1234567",
+				})
+			},
+			expCommand: `"-LensModel=EF24-105mm f/3.5-5.6 IS STM" "-LensMake=Canon" "-LensInfo=24 105 3.5 5.6" "-LensSerialNumber=1234567" "test-file-with-lens"`,
+		},
+		{
+			name:  "lens without make and serial number specified",
+			fname: "test-file-with-lens",
+			f: func(e *ExifTool) {
+				e.Lens(types.Lens{
+					Name:        "EF50mm f/1.4 USM",
+					FocalLength: types.Range{Min: 50, Max: 50},
+					MaxAperture: types.Range{Min: 1.4, Max: 1.4},
+				})
+			},
+			expCommand: `"-LensModel=EF50mm f/1.4 USM" "-LensInfo=50 50 1.4 1.4" "test-file-with-lens"`,
+		},
+		{
+			name:  "lens model specified",
+			fname: "test-file-with-lens-model",
+			f: func(e *ExifTool) {
+				e.LensModel("Nikkor 50mm f/1.4")
+			},
+			expCommand: `"-LensModel=Nikkor 50mm f/1.4" "test-file-with-lens-model"`,
+		},
 		{
 			name:  "timestamp specified",
 			fname: "test-file-with-timestamp",
@@ -76,27 +158,18 @@ func TestExiftoolOptions(t *testing.T) {
 
 				e.Timestamp(ts)
 			},
-			expCommand: `"-DateTimeOriginal=2019-08-21T14:06:13Z" "-ModifyDate=2019-08-21T14:06:13Z" "test-file-with-timestamp"`,
+			expCommand: `"-DateTimeOriginal=2019-08-21T14:06:13Z" "-OffsetTimeOriginal=+00:00" "-ModifyDate=2019-08-21T14:06:13Z" "-OffsetTime=+00:00" "test-file-with-timestamp"`,
 		},
 		{
-			name:  "geotag specified",
-			fname: "test-file-with-geotag",
-			f: func(e *ExifTool) {
-				e.GeoTag("blah.log")
-			},
-			expCommand: `"-GeoTag=blah.log" "test-file-with-geotag"`,
-		},
-		{
-			name:  "geotime specified",
-			fname: "test-file-with-geotime",
+			name:  "timestamp with timezone specified",
+			fname: "test-file-with-timestamp",
 			f: func(e *ExifTool) {
-				tz, err := time.LoadLocation("CET")
+				ts, err := time.Parse(time.RFC3339, "2019-08-21T14:06:13+09:30")
 				r.NoError(err)
 
-				ts := time.Date(2001, 3, 14, 15, 32, 53, 0, tz)
-				e.GeoTime(ts)
+				e.Timestamp(ts)
 			},
-			expCommand: `"-GeoTime=2001:03:14 15:32:53+0100" "test-file-with-geotime"`,
+			expCommand: `"-DateTimeOriginal=2019-08-21T14:06:13+09:30" "-OffsetTimeOriginal=+09:30" "-ModifyDate=2019-08-21T14:06:13+09:30" "-OffsetTime=+09:30" "test-file-with-timestamp"`,
 		},
 		{
 			name:  "DateTimeDigitized copied from CreateDate",
@@ -146,6 +219,23 @@ func TestExiftoolOptions(t *testing.T) {
 			},
 			expCommand: `"-IFD0:Artist=Test Copyright © 2020" "-IFD0:Copyright=Test Copyright © 2020" "test-file-with-copyright"`,
 		},
+		{
+			name:  "artist overrides the one set by copyright",
+			fname: "test-file-with-artist",
+			f: func(e *ExifTool) {
+				e.Copyright("Test Copyright © 2020")
+				e.Artist("John Doe")
+			},
+			expCommand: `"-IFD0:Copyright=Test Copyright © 2020" "-IFD0:Artist=John Doe" "test-file-with-artist"`,
+		},
+		{
+			name:  "owner name",
+			fname: "test-file-with-owner",
+			f: func(e *ExifTool) {
+				e.OwnerName("John Doe")
+			},
+			expCommand: `"-OwnerName=John Doe" "test-file-with-owner"`,
+		},
 	}
 
 	for _, tc := range tcs {
@@ -293,11 +383,21 @@ func TestFromFrame(t *testing.T) {
 					value:    "2009-02-12T15:34:23Z",
 					operator: "=",
 				},
+				{
+					key:      "OffsetTimeOriginal",
+					value:    "+00:00",
+					operator: "=",
+				},
 				{
 					key:      "ModifyDate",
 					value:    "2009-02-12T15:34:23Z",
 					operator: "=",
 				},
+				{
+					key:      "OffsetTime",
+					value:    "+00:00",
+					operator: "=",
+				},
 				{
 					key:      "ExposureTime",
 					value:    "1/300",
@@ -322,3 +422,37 @@ func TestFromFrame(t *testing.T) {
 		r.ElementsMatchf(tc.expOptions, e.Options(), tc.name)
 	}
 }
+
+func TestArgs(t *testing.T) {
+	r := require.New(t)
+
+	et := New("exiftool", "scan 01.dng").Model(`EOS-1V "HS"`).Keywords("Kodak")
+	r.Equal([]string{
+		"-overwrite_original",
+		`-Model=EOS-1V "HS"`,
+		"-Keywords-=Kodak",
+		"-Keywords+=Kodak",
+		"-XMP-dc:Subject-=Kodak",
+		"-XMP-dc:Subject+=Kodak",
+		"scan 01.dng",
+	}, et.Args())
+	r.Equal("scan 01.dng", et.Filename())
+}
+
+func TestRemoveCanonTags(t *testing.T) {
+	r := require.New(t)
+
+	et := New("exiftool", "test.jpg")
+	et.options = []ExifToolOption{
+		{key: "Canon:FocusMode", value: "One-Shot AF", operator: "="},
+		{key: "FNumber", value: "8.0", operator: "="},
+		{key: "CanonCustom:PF0CustomFuncRegistration", value: "Off", operator: "="},
+		{key: "MeteringMode", value: "Evaluative", operator: "="},
+	}
+
+	et.RemoveCanonTags()
+	r.Equal([]ExifToolOption{
+		{key: "FNumber", value: "8.0", operator: "="},
+		{key: "MeteringMode", value: "Evaluative", operator: "="},
+	}, et.options)
+}
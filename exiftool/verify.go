@@ -0,0 +1,151 @@
+package tagger
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// VerifyTags are the tags read back from the file and compared by Verify
+var VerifyTags = []string{
+	"DateTimeOriginal",
+	"ExposureTime",
+	"FNumber",
+	"FocalLength",
+	"ISO",
+	"LensModel",
+	"Make",
+	"Model",
+}
+
+// exifDateTimeFormat is the format of EXIF date and time values
+const exifDateTimeFormat = "2006:01:02 15:04:05"
+
+// Mismatch is the tag which value in the file differs from the one set by
+// the command
+type Mismatch struct {
+	Tag      string
+	Expected string
+	Actual   string
+}
+
+func (m Mismatch) String() string {
+	if m.Actual == "" {
+		return fmt.Sprintf("%s: expected `%s`, not set", m.Tag, m.Expected)
+	}
+	return fmt.Sprintf("%s: expected `%s`, found `%s`", m.Tag, m.Expected, m.Actual)
+}
+
+// ReadTags reads VerifyTags values of the file using exiftool, numeric
+// values are read without print conversion
+func (e *ExifTool) ReadTags() (map[string]string, error) {
+	args := []string{"-json", "-n"}
+	for _, t := range VerifyTags {
+		args = append(args, "-"+t)
+	}
+	args = append(args, e.filename)
+
+	out, err := exec.Command(e.binary, args...).Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading tags of %s", e.filename)
+	}
+
+	files := []map[string]interface{}{}
+	if err := json.Unmarshal(out, &files); err != nil {
+		return nil, errors.Wrapf(err, "error decoding exiftool output for %s", e.filename)
+	}
+	if len(files) != 1 {
+		return nil, errors.Errorf("exiftool returned %d results for %s", len(files), e.filename)
+	}
+
+	tags := map[string]string{}
+	for k, v := range files[0] {
+		tags[k] = fmt.Sprint(v)
+	}
+	return tags, nil
+}
+
+// Verify compares the tags read by ReadTags against the values set by the
+// command. Only VerifyTags set by the command are compared.
+func (e *ExifTool) Verify(actual map[string]string) []Mismatch {
+	expected := map[string]string{}
+	for _, o := range e.options {
+		if o.operator != "=" {
+			continue
+		}
+		tag := o.key
+		if i := strings.LastIndex(tag, ":"); i >= 0 {
+			tag = tag[i+1:]
+		}
+		expected[tag] = o.value
+	}
+
+	mismatches := []Mismatch{}
+	for _, tag := range VerifyTags {
+		v, ok := expected[tag]
+		if !ok {
+			continue
+		}
+		if !tagValuesEqual(tag, v, actual[tag]) {
+			mismatches = append(mismatches, Mismatch{
+				Tag:      tag,
+				Expected: v,
+				Actual:   actual[tag],
+			})
+		}
+	}
+	return mismatches
+}
+
+func tagValuesEqual(tag, expected, actual string) bool {
+	expected, actual = strings.TrimSpace(expected), strings.TrimSpace(actual)
+
+	if tag == "DateTimeOriginal" {
+		if t, err := time.Parse(time.RFC3339, expected); err == nil {
+			expected = t.Format(exifDateTimeFormat)
+		}
+		return len(actual) >= len(expected) && actual[:len(expected)] == expected
+	}
+
+	ev, err := parseNumber(expected)
+	if err != nil {
+		return expected == actual
+	}
+	av, err := parseNumber(actual)
+	if err != nil {
+		return false
+	}
+
+	// values are stored as rationals so 1% is allowed
+	return math.Abs(ev-av) <= 0.01*math.Max(math.Abs(ev), math.Abs(av))
+}
+
+// parseNumber parses numeric tag values as they're set by the command:
+// fractions (`1/250`), seconds (`2"`, `0"5`) and focal lengths (`50mm`)
+func parseNumber(s string) (float64, error) {
+	s = strings.TrimSuffix(s, "mm")
+
+	if i := strings.Index(s, "/"); i > 0 {
+		n, err := strconv.ParseFloat(s[:i], 64)
+		if err != nil {
+			return 0, err
+		}
+		d, err := strconv.ParseFloat(s[i+1:], 64)
+		if err != nil || d == 0 {
+			return 0, errors.Errorf("invalid fraction `%s`", s)
+		}
+		return n / d, nil
+	}
+
+	if strings.Contains(s, `"`) {
+		s = strings.TrimSuffix(strings.Replace(s, `"`, ".", 1), ".")
+	}
+
+	return strconv.ParseFloat(s, 64)
+}
@@ -0,0 +1,126 @@
+package tagger
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+func TestVerify(t *testing.T) {
+	r := require.New(t)
+
+	et := NewFromFrame("exiftool", "FILM_0100100001.dng", &types.Frame{
+		Av:          types.PtrAperture(1.4),
+		Tv:          types.PtrString("1/250"),
+		FocalLength: types.PtrInt64(50),
+		ISO:         types.PtrInt64(400),
+		Timestamp:   types.PtrTime(time.Date(2020, 5, 1, 10, 15, 30, 0, time.FixedZone("", 3*3600))),
+	}).Make("Canon").Model("Canon EOS-1V")
+
+	type testCase struct {
+		name     string
+		actual   map[string]string
+		expected []Mismatch
+	}
+
+	tcs := []testCase{
+		{
+			name: "tags match",
+			actual: map[string]string{
+				"DateTimeOriginal": "2020:05:01 10:15:30",
+				"ExposureTime":     "0.004",
+				"FNumber":          "1.4",
+				"FocalLength":      "50",
+				"ISO":              "400",
+				"Make":             "Canon",
+				"Model":            "Canon EOS-1V",
+			},
+			expected: []Mismatch{},
+		},
+		{
+			name: "tags differ or missing",
+			actual: map[string]string{
+				"DateTimeOriginal": "2020:05:01 07:15:30",
+				"ExposureTime":     "0.004",
+				"FNumber":          "2.8",
+				"FocalLength":      "50",
+				"Make":             "Canon",
+				"Model":            "Canon EOS-1V",
+				"LensModel":        "EF50mm f/1.4 USM",
+			},
+			expected: []Mismatch{
+				{Tag: "DateTimeOriginal", Expected: "2020-05-01T10:15:30+03:00", Actual: "2020:05:01 07:15:30"},
+				{Tag: "FNumber", Expected: "1.4", Actual: "2.8"},
+				{Tag: "ISO", Expected: "400"},
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		r.Equalf(tc.expected, et.Verify(tc.actual), tc.name)
+	}
+
+	r.Equal("ISO: expected `400`, not set", Mismatch{Tag: "ISO", Expected: "400"}.String())
+	r.Equal("FNumber: expected `1.4`, found `2.8`", Mismatch{Tag: "FNumber", Expected: "1.4", Actual: "2.8"}.String())
+}
+
+func TestParseNumber(t *testing.T) {
+	r := require.New(t)
+
+	type testCase struct {
+		name     string
+		input    string
+		expected float64
+		expError bool
+	}
+
+	tcs := []testCase{
+		{name: "fraction", input: "1/8", expected: 0.125},
+		{name: "seconds", input: `2"`, expected: 2},
+		{name: "fractional seconds", input: `0"5`, expected: 0.5},
+		{name: "focal length", input: "85mm", expected: 85},
+		{name: "plain number", input: "5.6", expected: 5.6},
+		{name: "zero denominator", input: "1/0", expError: true},
+		{name: "text", input: "Canon", expError: true},
+	}
+
+	for _, tc := range tcs {
+		v, err := parseNumber(tc.input)
+		if tc.expError {
+			r.Errorf(err, tc.name)
+			continue
+		}
+		r.NoErrorf(err, tc.name)
+		r.InDeltaf(tc.expected, v, 1e-9, tc.name)
+	}
+}
+
+func TestReadTags(t *testing.T) {
+	r := require.New(t)
+
+	dir, err := ioutil.TempDir("", "exiftool")
+	r.NoError(err)
+	defer os.RemoveAll(dir)
+
+	binary := filepath.Join(dir, "exiftool")
+	err = ioutil.WriteFile(binary, []byte("#!/bin/sh\necho '[{\"SourceFile\": \"scan.dng\", \"FNumber\": 8, \"ExposureTime\": 0.004, \"Make\": \"Canon\"}]'\n"), 0755)
+	r.NoError(err)
+
+	tags, err := New(binary, "scan.dng").ReadTags()
+	r.NoError(err)
+	r.Equal(map[string]string{
+		"SourceFile":   "scan.dng",
+		"FNumber":      "8",
+		"ExposureTime": "0.004",
+		"Make":         "Canon",
+	}, tags)
+
+	_, err = New(filepath.Join(dir, "missing"), "scan.dng").ReadTags()
+	r.Error(err)
+}
@@ -0,0 +1,159 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+// Version is the version of export document format, it's bumped on every
+// incompatible change of the document structure. The structure is
+// described by JSON Schema in schema.json.
+const Version = 1
+
+// Document is the top-level object of exported data
+type Document struct {
+	Version int           `json:"version" yaml:"version"`
+	Films   []*types.Film `json:"films" yaml:"films"`
+}
+
+// New creates export document of the films
+func New(films []*types.Film) *Document {
+	if films == nil {
+		films = []*types.Film{}
+	}
+
+	return &Document{
+		Version: Version,
+		Films:   films,
+	}
+}
+
+// Encode writes the document to w in the format specified
+func Encode(w io.Writer, format types.DataFormat, doc *Document) error {
+	switch format {
+	case types.DataFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(doc)
+	case types.DataFormatYAML:
+		out, err := yaml.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(out)
+		return err
+	}
+	return errors.Errorf("unsupported format `%s`", format)
+}
+
+// Decode reads the document from r in the format specified
+func Decode(r io.Reader, format types.DataFormat) (*Document, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc Document
+	switch format {
+	case types.DataFormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&doc)
+	case types.DataFormatYAML:
+		err = yaml.UnmarshalStrict(data, &doc)
+	default:
+		return nil, errors.Errorf("unsupported format `%s`", format)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "error decoding %s document", format)
+	}
+
+	if doc.Version != Version {
+		return nil, errors.Errorf("unsupported document version %d, expected %d", doc.Version, Version)
+	}
+
+	if doc.Films == nil {
+		doc.Films = []*types.Film{}
+	}
+
+	for i, f := range doc.Films {
+		if err := validateFilm(f); err != nil {
+			return nil, errors.Wrapf(err, "film #%d", i+1)
+		}
+	}
+
+	return &doc, nil
+}
+
+// sources are the formats films are read from, documents keep the format
+// of the film
+var sources = map[types.InputFormat]struct{}{
+	types.InputFormatCSV:       {},
+	types.InputFormatNikon:     {},
+	types.InputFormatFilmLog:   {},
+	types.InputFormatExifNotes: {},
+}
+
+// validateFilm checks enum values decoded as is against the ones listed in
+// schema.json
+func validateFilm(f *types.Film) error {
+	if f.Source != nil {
+		if _, ok := sources[*f.Source]; !ok {
+			return errors.Errorf("source: unknown value `%s`", *f.Source)
+		}
+	}
+
+	for i, fr := range f.Frames {
+		if err := validateFrame(fr); err != nil {
+			return errors.Wrapf(err, "frame #%d", i+1)
+		}
+	}
+	return nil
+}
+
+func validateFrame(fr *types.Frame) error {
+	if fr.FlashMode != nil {
+		if _, err := types.FlashModeFromString(string(*fr.FlashMode)); err != nil {
+			return errors.Wrap(err, "flash_mode")
+		}
+	}
+
+	if fr.MeteringMode != nil {
+		if _, err := types.MeteringModeFromString(string(*fr.MeteringMode)); err != nil {
+			return errors.Wrap(err, "metering_mode")
+		}
+	}
+
+	if fr.ShootingMode != nil {
+		if _, err := types.ShootingModeFromString(string(*fr.ShootingMode)); err != nil {
+			return errors.Wrap(err, "shooting_mode")
+		}
+	}
+
+	if fr.FilmAdvanceMode != nil {
+		if _, err := types.FilmAdvanceModeFromString(string(*fr.FilmAdvanceMode)); err != nil {
+			return errors.Wrap(err, "film_advance_mode")
+		}
+	}
+
+	if fr.AFMode != nil {
+		if _, err := types.AFModeFromString(string(*fr.AFMode)); err != nil {
+			return errors.Wrap(err, "af_mode")
+		}
+	}
+
+	if fr.MultipleExposure != nil {
+		if _, err := types.MultipleExposureFromString(string(*fr.MultipleExposure)); err != nil {
+			return errors.Wrap(err, "multiple_exposure")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,264 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+func sampleFilms() []*types.Film {
+	msk := time.FixedZone("", 3*3600)
+
+	return []*types.Film{
+		{
+			ID:                  types.PtrInt64(139),
+			CameraID:            types.PtrUint8(1),
+			Title:               types.PtrString("SampleTest film #139"),
+			Source:              types.PtrInputFormat(types.InputFormatCSV),
+			FilmLoadedTimestamp: types.PtrTime(time.Date(2019, 9, 28, 10, 21, 32, 0, msk)),
+			FrameCount:          types.PtrInt64(2),
+			ISO:                 types.PtrInt64(400),
+			Stock: &types.FilmStock{
+				ID:           "portra400",
+				Manufacturer: "Kodak",
+				Name:         "Portra 400",
+				BoxSpeed:     400,
+				Process:      types.FilmProcessC41,
+			},
+			PushPull: types.PtrFloat64(0),
+			Frames: []*types.Frame{
+				{
+					Flag:         types.PtrBool(false),
+					Number:       types.PtrInt64(1),
+					FocalLength:  types.PtrInt64(50),
+					Tv:           types.PtrString("1/125"),
+					Av:           types.PtrAperture(5.6),
+					ISO:          types.PtrInt64(400),
+					AFMode:       types.PtrAFMode(types.AFModeOneShotAF),
+					FlashMode:    types.PtrFlashMode(types.FlashModeOff),
+					MeteringMode: types.PtrMeteringMode(types.MeteringModeEvaluative),
+					Timestamp:    types.PtrTime(time.Date(2019, 10, 7, 20, 2, 18, 0, msk)),
+				},
+				{
+					Number:    types.PtrInt64(2),
+					Timestamp: types.PtrTime(time.Date(2019, 10, 7, 17, 5, 0, 0, time.UTC)),
+					Remarks:   types.PtrString("bracketing"),
+				},
+			},
+		},
+	}
+}
+
+func TestEncodeJSON(t *testing.T) {
+	r := require.New(t)
+
+	buf := &bytes.Buffer{}
+	err := Encode(buf, types.DataFormatJSON, New(sampleFilms()[:1]))
+	r.NoError(err)
+
+	var doc map[string]interface{}
+	r.NoError(json.Unmarshal(buf.Bytes(), &doc))
+	r.Equal(float64(1), doc["version"])
+
+	film := doc["films"].([]interface{})[0].(map[string]interface{})
+	r.Equal("2019-09-28T10:21:32+03:00", film["film_loaded_timestamp"])
+	r.Equal("C-41", film["stock"].(map[string]interface{})["process"])
+	r.Equal("csv", film["source"])
+
+	frame := film["frames"].([]interface{})[0].(map[string]interface{})
+	r.Equal("One-Shot AF", frame["af_mode"])
+	r.Equal("OFF", frame["flash_mode"])
+	r.Equal("Evaluative", frame["metering_mode"])
+	r.Equal(5.6, frame["av"])
+	r.Equal("2019-10-07T20:02:18+03:00", frame["timestamp"])
+}
+
+func TestEncodeYAML(t *testing.T) {
+	r := require.New(t)
+
+	buf := &bytes.Buffer{}
+	err := Encode(buf, types.DataFormatYAML, New(sampleFilms()))
+	r.NoError(err)
+
+	out := buf.String()
+	r.True(strings.HasPrefix(out, "version: 1\nfilms:\n- id: 139\n  camera_id: 1\n"), out)
+	r.Contains(out, `  film_loaded_timestamp: "2019-09-28T10:21:32+03:00"`)
+	r.Contains(out, "    af_mode: One-Shot AF\n")
+	r.Contains(out, "    box_speed: 400\n")
+}
+
+func TestRoundTrip(t *testing.T) {
+	r := require.New(t)
+
+	for _, format := range []types.DataFormat{types.DataFormatJSON, types.DataFormatYAML} {
+		buf := &bytes.Buffer{}
+		err := Encode(buf, format, New(sampleFilms()))
+		r.NoErrorf(err, "%s", format)
+
+		doc, err := Decode(buf, format)
+		r.NoErrorf(err, "%s", format)
+		r.Equalf(New(sampleFilms()), doc, "%s", format)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	r := require.New(t)
+
+	type testCase struct {
+		name     string
+		format   types.DataFormat
+		input    string
+		expError string
+	}
+
+	tcs := []testCase{
+		{
+			name:     "unsupported version",
+			format:   types.DataFormatJSON,
+			input:    `{"version": 2, "films": []}`,
+			expError: "unsupported document version 2, expected 1",
+		},
+		{
+			name:     "unknown film field in JSON",
+			format:   types.DataFormatJSON,
+			input:    `{"version": 1, "films": [{"id": 1, "film_id": 2}]}`,
+			expError: "error decoding json document: json: unknown field \"film_id\"",
+		},
+		{
+			name:     "unknown frame field in YAML",
+			format:   types.DataFormatYAML,
+			input:    "version: 1\nfilms:\n- id: 1\n  frames:\n  - frame_no: 1\n",
+			expError: "error decoding yaml document: json: unknown field \"frame_no\"",
+		},
+		{
+			name:     "unknown shooting mode in JSON",
+			format:   types.DataFormatJSON,
+			input:    `{"version": 1, "films": [{"id": 1, "frames": [{"number": 1}, {"number": 2, "shooting_mode": "Sport"}]}]}`,
+			expError: "film #1: frame #2: shooting_mode: error parsing ShootingMode: unknown value `Sport`",
+		},
+		{
+			name:     "unknown multiple exposure in YAML",
+			format:   types.DataFormatYAML,
+			input:    "version: 1\nfilms:\n- id: 1\n  frames:\n  - multiple_exposure: \"2\"\n",
+			expError: "film #1: frame #1: multiple_exposure: error parsing MultipleExposure: unknown value `2`",
+		},
+		{
+			name:     "unknown source",
+			format:   types.DataFormatJSON,
+			input:    `{"version": 1, "films": [{"id": 1}, {"id": 2, "source": "json"}]}`,
+			expError: "film #2: source: unknown value `json`",
+		},
+		{
+			name:     "unknown format",
+			format:   types.DataFormat("xml"),
+			input:    "",
+			expError: "unsupported format `xml`",
+		},
+	}
+
+	for _, tc := range tcs {
+		_, err := Decode(strings.NewReader(tc.input), tc.format)
+		r.Errorf(err, tc.name)
+		r.Equalf(tc.expError, err.Error(), tc.name)
+	}
+}
+
+// TestSchema ensures schema.json describes every exported field
+func TestSchema(t *testing.T) {
+	r := require.New(t)
+
+	data, err := ioutil.ReadFile("schema.json")
+	r.NoError(err)
+
+	var schema struct {
+		Properties  map[string]interface{} `json:"properties"`
+		Definitions map[string]struct {
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"definitions"`
+	}
+	r.NoError(json.Unmarshal(data, &schema))
+
+	r.Equal(jsonFields(reflect.TypeOf(Document{})), keys(schema.Properties))
+
+	for def, v := range map[string]interface{}{
+		"film":       types.Film{},
+		"frame":      types.Frame{},
+		"film_stock": types.FilmStock{},
+		"lens":       types.Lens{},
+		"range":      types.Range{},
+		"position":   types.Position{},
+	} {
+		r.Equalf(jsonFields(reflect.TypeOf(v)), keys(schema.Definitions[def].Properties), def)
+	}
+
+	r.Equal(
+		jsonFields(reflect.TypeOf(types.DateRange{})),
+		[]string{"from", "to"},
+	)
+}
+
+// TestSchemaEnums ensures every value listed in schema.json is accepted by
+// Decode
+func TestSchemaEnums(t *testing.T) {
+	r := require.New(t)
+
+	data, err := ioutil.ReadFile("schema.json")
+	r.NoError(err)
+
+	var schema struct {
+		Definitions map[string]struct {
+			Properties map[string]struct {
+				Enum []string `json:"enum"`
+			} `json:"properties"`
+		} `json:"definitions"`
+	}
+	r.NoError(json.Unmarshal(data, &schema))
+
+	for _, def := range []string{"film", "frame"} {
+		for field, p := range schema.Definitions[def].Properties {
+			for _, v := range p.Enum {
+				value, err := json.Marshal(v)
+				r.NoError(err)
+
+				obj := fmt.Sprintf(`{%q: %s}`, field, value)
+				if def == "frame" {
+					obj = fmt.Sprintf(`{"frames": [%s]}`, obj)
+				}
+
+				_, err = Decode(strings.NewReader(`{"version": 1, "films": [`+obj+`]}`), types.DataFormatJSON)
+				r.NoErrorf(err, "%s.%s: %s", def, field, v)
+			}
+		}
+	}
+}
+
+func jsonFields(t reflect.Type) []string {
+	fields := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func keys(m map[string]interface{}) []string {
+	ks := []string{}
+	for k := range m {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+	return ks
+}
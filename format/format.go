@@ -2,42 +2,247 @@ package format
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// VarType describes the kind of value a pattern variable holds
+type VarType int
+
+const (
+	// Int is an integer variable
+	Int VarType = iota
+
+	// Float is a floating point variable
+	Float
+
+	// String is a string variable
+	String
 )
 
-// Format ...
-func Format(s string, subst map[string]interface{}) string {
-	tokens := findTokens(s)
+var allowedVerbs = map[VarType]string{
+	Int:    "bdoxXv",
+	Float:  "eEfFgGv",
+	String: "sqv",
+}
+
+const allowedFlags = "+-# 0"
+
+// Variables maps variable names known to the pattern to their types
+type Variables map[string]VarType
+
+// Pattern is a compiled filename pattern
+type Pattern struct {
+	source string
+	tokens []token
+}
+
+type token struct {
+	literal  string
+	variable string
+	vtype    VarType
+	verb     string
+}
+
+// Compile parses pattern and validates every variable against vars.
+//
+// Variables are written as `${name:verb}` where verb is a fmt verb with
+// optional flags, width and precision (e.g. `05d`, `.2f`, `s`). `${name}`
+// is the same as `${name:v}`. `$$` renders a literal `$`.
+func Compile(pattern string, vars Variables) (*Pattern, error) {
+	p := &Pattern{source: pattern}
+
+	var lit strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '$' {
+			lit.WriteByte(pattern[i])
+			continue
+		}
+
+		if i+1 < len(pattern) && pattern[i+1] == '$' {
+			lit.WriteByte('$')
+			i++
+			continue
+		}
+
+		if i+1 >= len(pattern) || pattern[i+1] != '{' {
+			lit.WriteByte('$')
+			continue
+		}
+
+		end := strings.IndexByte(pattern[i:], '}')
+		if end < 0 {
+			return nil, errors.Errorf("unterminated variable at position %d in pattern `%s`", i, pattern)
+		}
+
+		t, err := parseVariable(pattern[i+2:i+end], vars)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error parsing variable at position %d in pattern `%s`", i, pattern)
+		}
+
+		if lit.Len() > 0 {
+			p.tokens = append(p.tokens, token{literal: lit.String()})
+			lit.Reset()
+		}
+		p.tokens = append(p.tokens, *t)
+
+		i += end
+	}
+
+	if lit.Len() > 0 {
+		p.tokens = append(p.tokens, token{literal: lit.String()})
+	}
+
+	return p, nil
+}
+
+func parseVariable(s string, vars Variables) (*token, error) {
+	name, verb := s, "v"
+	if idx := strings.IndexByte(s, ':'); idx >= 0 {
+		name, verb = s[:idx], s[idx+1:]
+	}
+
+	if name == "" {
+		return nil, errors.New("empty variable name")
+	}
+
+	vt, ok := vars[name]
+	if !ok {
+		return nil, errors.Errorf("unknown variable `%s`, available variables: %s", name, strings.Join(vars.names(), ", "))
+	}
 
-	for vv, ff := range tokens {
-		ss := strings.ReplaceAll(s, `${`+vv+`:`+ff+`}`, `%`+ff)
-		s = fmt.Sprintf(ss, subst[vv])
+	if err := validateVerb(verb, vt); err != nil {
+		return nil, errors.Wrapf(err, "variable `%s`", name)
 	}
 
-	return s
+	return &token{
+		variable: name,
+		vtype:    vt,
+		verb:     "%" + verb,
+	}, nil
 }
 
-func findTokens(s string) map[string]string {
-	m := make(map[string]string)
+func validateVerb(verb string, vt VarType) error {
+	if verb == "" {
+		return errors.New("empty verb")
+	}
+
+	body, v := verb[:len(verb)-1], verb[len(verb)-1]
+	if !strings.ContainsRune(allowedVerbs[vt], rune(v)) {
+		return errors.Errorf("verb `%c` is not applicable to %s values", v, vt)
+	}
 
-	openers := strings.Split(s, "${")
-	if len(openers) < 2 {
-		return m
+	body = strings.TrimLeft(body, allowedFlags)
+	if idx := strings.IndexByte(body, '.'); idx >= 0 {
+		if !isDigits(body[idx+1:]) {
+			return errors.Errorf("invalid precision in verb `%s`", verb)
+		}
+		body = body[:idx]
 	}
 
-	for _, o := range openers[1:] {
-		closer := strings.Split(o, "}")
-		if len(closer) < 2 {
+	if !isDigits(body) {
+		return errors.Errorf("invalid width in verb `%s`", verb)
+	}
+
+	return nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Render substitutes values into the pattern
+func (p *Pattern) Render(subst map[string]interface{}) (string, error) {
+	var sb strings.Builder
+	for _, t := range p.tokens {
+		if t.variable == "" {
+			sb.WriteString(t.literal)
 			continue
 		}
 
-		pair := strings.Split(closer[0], ":")
-		if len(pair) != 2 {
+		v, ok := subst[t.variable]
+		if !ok || v == nil {
+			return "", errors.Errorf("no value provided for variable `%s`", t.variable)
+		}
+		if typeOf(v) != t.vtype {
+			return "", errors.Errorf("%s value expected for variable `%s`, got %T", t.vtype, t.variable, v)
+		}
+		sb.WriteString(fmt.Sprintf(t.verb, v))
+	}
+
+	return sb.String(), nil
+}
+
+// Variables returns the list of variables referenced by the pattern
+func (p *Pattern) Variables() []string {
+	seen := map[string]struct{}{}
+	vars := []string{}
+	for _, t := range p.tokens {
+		if t.variable == "" {
 			continue
 		}
+		if _, ok := seen[t.variable]; ok {
+			continue
+		}
+		seen[t.variable] = struct{}{}
+		vars = append(vars, t.variable)
+	}
+	return vars
+}
+
+func (p *Pattern) String() string {
+	return p.source
+}
+
+// Format compiles s against the variables present in subst and renders it
+func Format(s string, subst map[string]interface{}) (string, error) {
+	vars := Variables{}
+	for k, v := range subst {
+		vars[k] = typeOf(v)
+	}
 
-		m[pair[0]] = pair[1]
+	p, err := Compile(s, vars)
+	if err != nil {
+		return "", err
 	}
 
-	return m
+	return p.Render(subst)
+}
+
+func typeOf(v interface{}) VarType {
+	switch v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return Int
+	case float32, float64:
+		return Float
+	}
+	return String
+}
+
+func (vs Variables) names() []string {
+	names := make([]string, 0, len(vs))
+	for k := range vs {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (vt VarType) String() string {
+	switch vt {
+	case Int:
+		return "integer"
+	case Float:
+		return "float"
+	case String:
+		return "string"
+	}
+	return "unknown"
 }
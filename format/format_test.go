@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	types "github.com/teran/eos-1v-tagger/types"
 )
 
 func TestFormatter(t *testing.T) {
@@ -14,6 +16,7 @@ func TestFormatter(t *testing.T) {
 		sample    string
 		subst     map[string]interface{}
 		expResult string
+		expError  string
 	}
 
 	tcs := []testCase{
@@ -48,10 +51,34 @@ func TestFormatter(t *testing.T) {
 			expResult: `TEST_test1_3975_test`,
 		},
 		{
-			name:      "wrong variable name (opener only)",
-			sample:    "test_${blah_test",
-			subst:     map[string]interface{}{"blah": 2},
-			expResult: "test_${blah_test",
+			name:      "variable without verb",
+			sample:    `TEST_${testvar}_blah`,
+			subst:     map[string]interface{}{"testvar": 42},
+			expResult: `TEST_42_blah`,
+		},
+		{
+			name:      "literal percent sign",
+			sample:    `100%_${testvar:03d}_%d`,
+			subst:     map[string]interface{}{"testvar": 7},
+			expResult: `100%_007_%d`,
+		},
+		{
+			name:      "percent sign in substituted value",
+			sample:    `${var1:s}_${var2:d}`,
+			subst:     map[string]interface{}{"var1": "50%", "var2": 1},
+			expResult: `50%_1`,
+		},
+		{
+			name:      "escaped dollar sign",
+			sample:    `$${testvar:d}_$_${testvar:d}`,
+			subst:     map[string]interface{}{"testvar": 1},
+			expResult: `${testvar:d}_$_1`,
+		},
+		{
+			name:     "wrong variable name (opener only)",
+			sample:   "test_${blah_test",
+			subst:    map[string]interface{}{"blah": 2},
+			expError: "unterminated variable at position 5 in pattern `test_${blah_test`",
 		},
 		{
 			name:      "wrong variable name (closer only)",
@@ -60,21 +87,117 @@ func TestFormatter(t *testing.T) {
 			expResult: "test_blah}_test",
 		},
 		{
-			name:      "wrong variable name (missed closer with type)",
-			sample:    "blah_${blah:05d_test",
-			subst:     map[string]interface{}{"blah": 2},
-			expResult: "blah_${blah:05d_test",
+			name:     "wrong variable name (missed closer with type)",
+			sample:   "blah_${blah:05d_test",
+			subst:    map[string]interface{}{"blah": 2},
+			expError: "unterminated variable at position 5 in pattern `blah_${blah:05d_test`",
 		},
 		{
-			name:      "wrong variable name with additional tokens",
-			sample:    "blah_${blah:05d:test}_test",
-			subst:     map[string]interface{}{"blah": 2},
-			expResult: "blah_${blah:05d:test}_test",
+			name:     "wrong variable name with additional tokens",
+			sample:   "blah_${blah:05d:test}_test",
+			subst:    map[string]interface{}{"blah": 2},
+			expError: "error parsing variable at position 5 in pattern `blah_${blah:05d:test}_test`: variable `blah`: verb `t` is not applicable to integer values",
+		},
+		{
+			name:     "unknown variable",
+			sample:   "blah_${unknown:d}",
+			subst:    map[string]interface{}{"blah": 2},
+			expError: "error parsing variable at position 5 in pattern `blah_${unknown:d}`: unknown variable `unknown`, available variables: blah",
+		},
+		{
+			name:     "invalid width",
+			sample:   "${blah:0x5d}",
+			subst:    map[string]interface{}{"blah": 2},
+			expError: "error parsing variable at position 0 in pattern `${blah:0x5d}`: variable `blah`: invalid width in verb `0x5d`",
+		},
+		{
+			name:     "empty verb",
+			sample:   "${blah:}",
+			subst:    map[string]interface{}{"blah": 2},
+			expError: "error parsing variable at position 0 in pattern `${blah:}`: variable `blah`: empty verb",
 		},
 	}
 
 	for _, tc := range tcs {
-		res := Format(tc.sample, tc.subst)
+		res, err := Format(tc.sample, tc.subst)
+		if tc.expError != "" {
+			r.Errorf(err, tc.name)
+			r.Equalf(tc.expError, err.Error(), tc.name)
+			continue
+		}
+		r.NoErrorf(err, tc.name)
 		r.Equalf(tc.expResult, res, tc.name)
 	}
 }
+
+func TestPattern(t *testing.T) {
+	r := require.New(t)
+
+	p, err := Compile(`FILM_${cameraID:02d}${filmID:03d}${frameNo:05d}_${cameraID:d}.dng`, FrameVariables)
+	r.NoError(err)
+	r.Equal([]string{"cameraID", "filmID", "frameNo"}, p.Variables())
+	r.Equal(`FILM_${cameraID:02d}${filmID:03d}${frameNo:05d}_${cameraID:d}.dng`, p.String())
+
+	res, err := p.Render(map[string]interface{}{
+		"cameraID": uint8(1),
+		"filmID":   int64(139),
+		"frameNo":  int64(7),
+	})
+	r.NoError(err)
+	r.Equal("FILM_0113900007_1.dng", res)
+
+	_, err = p.Render(map[string]interface{}{
+		"cameraID": uint8(1),
+		"filmID":   int64(139),
+	})
+	r.Error(err)
+	r.Equal("no value provided for variable `frameNo`", err.Error())
+
+	_, err = p.Render(map[string]interface{}{
+		"cameraID": "1",
+		"filmID":   int64(139),
+		"frameNo":  int64(7),
+	})
+	r.Error(err)
+	r.Equal("integer value expected for variable `cameraID`, got string", err.Error())
+}
+
+func TestFrameSubstitutions(t *testing.T) {
+	r := require.New(t)
+
+	p, err := Compile(`${stock:s}_${process:s}_${boxSpeed:d}_${frameNo:02d}`, FrameVariables)
+	r.NoError(err)
+
+	film := &types.Film{
+		ID:       types.PtrInt64(139),
+		CameraID: types.PtrUint8(1),
+	}
+	frame := &types.Frame{Number: types.PtrInt64(3)}
+
+	_, err = FrameSubstitutions(p, film, frame)
+	r.Error(err)
+	r.Equal("film 01-139 has no film stock set, required by variable `stock`", err.Error())
+
+	noStock, err := Compile(`${frameNo:02d}`, FrameVariables)
+	r.NoError(err)
+
+	subst, err := FrameSubstitutions(noStock, film, frame)
+	r.NoError(err)
+
+	res, err := noStock.Render(subst)
+	r.NoError(err)
+	r.Equal("03", res)
+
+	film.Stock = &types.FilmStock{
+		ID:       "portra400",
+		BoxSpeed: 400,
+		Process:  types.FilmProcessC41,
+	}
+
+	subst, err = FrameSubstitutions(p, film, frame)
+	r.NoError(err)
+
+	res, err = p.Render(subst)
+	r.NoError(err)
+	r.Equal("portra400_C-41_400_03", res)
+}
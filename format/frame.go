@@ -0,0 +1,56 @@
+package format
+
+import (
+	"github.com/pkg/errors"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+// FrameVariables is the set of variables available in filename patterns
+var FrameVariables = Variables{
+	"cameraID": Int,
+	"filmID":   Int,
+	"frameNo":  Int,
+	"stock":    String,
+	"process":  String,
+	"boxSpeed": Int,
+}
+
+// stockVariables are FrameVariables taken from the film stock
+var stockVariables = []string{"stock", "process", "boxSpeed"}
+
+// FrameSubstitutions returns values for FrameVariables for the given frame.
+// It fails if the pattern uses stock variables and the film has no stock.
+func FrameSubstitutions(p *Pattern, film *types.Film, frame *types.Frame) (map[string]interface{}, error) {
+	if film.Stock == nil {
+		for _, v := range p.Variables() {
+			for _, sv := range stockVariables {
+				if v == sv {
+					return nil, errors.Errorf("film %s has no film stock set, required by variable `%s`", film.FullID(), v)
+				}
+			}
+		}
+	}
+
+	subst := map[string]interface{}{}
+
+	if film.CameraID != nil {
+		subst["cameraID"] = *film.CameraID
+	}
+
+	if film.ID != nil {
+		subst["filmID"] = *film.ID
+	}
+
+	if frame.Number != nil {
+		subst["frameNo"] = *frame.Number
+	}
+
+	if film.Stock != nil {
+		subst["stock"] = film.Stock.ID
+		subst["process"] = string(film.Stock.Process)
+		subst["boxSpeed"] = film.Stock.BoxSpeed
+	}
+
+	return subst, nil
+}
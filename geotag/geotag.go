@@ -0,0 +1,141 @@
+package geotag
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+const (
+	// DefaultMaxGap is the default maximum time between two track points
+	// position could be interpolated between
+	DefaultMaxGap = 30 * time.Minute
+)
+
+var (
+	// ErrOutOfRange ...
+	ErrOutOfRange = errors.New("timestamp is out of track log time range")
+
+	// ErrGapTooLarge ...
+	ErrGapTooLarge = errors.New("track log gap around timestamp is too large")
+)
+
+// Geotagger looks up positions for timestamps in the track log
+type Geotagger struct {
+	track  Track
+	maxGap time.Duration
+	offset time.Duration
+}
+
+// New creates new Geotagger object. offset is added to every timestamp
+// before the lookup to compensate camera clock difference against GPS
+// time. Zero maxGap means DefaultMaxGap.
+func New(track Track, maxGap, offset time.Duration) *Geotagger {
+	if maxGap == 0 {
+		maxGap = DefaultMaxGap
+	}
+
+	t := make(Track, len(track))
+	copy(t, track)
+	t.sort()
+
+	return &Geotagger{
+		track:  t,
+		maxGap: maxGap,
+		offset: offset,
+	}
+}
+
+// Locate returns position for the timestamp linearly interpolated
+// between the nearest track points
+func (g *Geotagger) Locate(ts time.Time) (*types.Position, error) {
+	ts = ts.Add(g.offset)
+
+	idx := sort.Search(len(g.track), func(i int) bool {
+		return !g.track[i].Time.Before(ts)
+	})
+
+	if idx < len(g.track) && g.track[idx].Time.Equal(ts) {
+		p := g.track[idx].Position
+		return &p, nil
+	}
+
+	if idx == 0 || idx == len(g.track) {
+		return nil, ErrOutOfRange
+	}
+
+	a, b := g.track[idx-1], g.track[idx]
+	gap := b.Time.Sub(a.Time)
+	if gap > g.maxGap {
+		return nil, ErrGapTooLarge
+	}
+
+	k := float64(ts.Sub(a.Time)) / float64(gap)
+	p := &types.Position{
+		Latitude:  a.Latitude + (b.Latitude-a.Latitude)*k,
+		Longitude: a.Longitude + (b.Longitude-a.Longitude)*k,
+	}
+
+	if a.Altitude != nil && b.Altitude != nil {
+		alt := *a.Altitude + (*b.Altitude-*a.Altitude)*k
+		p.Altitude = &alt
+	}
+
+	return p, nil
+}
+
+// Report summarizes positions set by Apply
+type Report struct {
+	Frames  int
+	Located int
+
+	// Problems are the frames failed to be located
+	Problems []string
+}
+
+func (r Report) String() string {
+	return fmt.Sprintf("%d of %d frames located", r.Located, r.Frames)
+}
+
+// Apply sets position of every frame of the films having no position yet
+// to the one located by the frame timestamp. Frames without timestamp or
+// failed to be located are left untouched and reported.
+func (g *Geotagger) Apply(films []*types.Film) *Report {
+	report := &Report{Problems: []string{}}
+	for _, film := range films {
+		for _, f := range film.Frames {
+			if f.Position != nil {
+				continue
+			}
+			report.Frames++
+
+			frameNo := "?"
+			if f.Number != nil {
+				frameNo = strconv.FormatInt(*f.Number, 10)
+			}
+
+			if f.Timestamp == nil || f.Timestamp.IsZero() {
+				report.Problems = append(report.Problems, fmt.Sprintf(
+					"film %s frame %s: no timestamp recorded", film.FullID(), frameNo))
+				continue
+			}
+
+			pos, err := g.Locate(*f.Timestamp)
+			if err != nil {
+				report.Problems = append(report.Problems, fmt.Sprintf(
+					"film %s frame %s: %s", film.FullID(), frameNo, err))
+				continue
+			}
+
+			f.Position = pos
+			report.Located++
+		}
+	}
+
+	return report
+}
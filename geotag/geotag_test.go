@@ -0,0 +1,140 @@
+package geotag
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+func TestLocate(t *testing.T) {
+	r := require.New(t)
+
+	track, err := ParseFile("testdata/track.gpx")
+	r.NoError(err)
+
+	cet, err := time.LoadLocation("CET")
+	r.NoError(err)
+
+	type testCase struct {
+		name        string
+		maxGap      time.Duration
+		offset      time.Duration
+		ts          time.Time
+		expPosition *types.Position
+		expError    error
+	}
+
+	tcs := []testCase{
+		{
+			name: "exact match",
+			ts:   time.Date(2019, 10, 7, 18, 0, 0, 0, time.UTC),
+			expPosition: &types.Position{
+				Latitude:  55.75,
+				Longitude: 37.61,
+				Altitude:  types.PtrFloat64(150),
+			},
+		},
+		{
+			name: "interpolated in other timezone",
+			ts:   time.Date(2019, 10, 7, 20, 1, 0, 0, cet),
+			expPosition: &types.Position{
+				Latitude:  55.7525,
+				Longitude: 37.615,
+				Altitude:  types.PtrFloat64(152.5),
+			},
+		},
+		{
+			name:   "interpolated with offset",
+			offset: 3 * time.Minute,
+			ts:     time.Date(2019, 10, 7, 18, 0, 0, 0, time.UTC),
+			expPosition: &types.Position{
+				Latitude:  55.7575,
+				Longitude: 37.625,
+				Altitude:  types.PtrFloat64(157.5),
+			},
+		},
+		{
+			name: "interpolated without altitude",
+			ts:   time.Date(2019, 10, 7, 18, 47, 0, 0, time.UTC),
+			expPosition: &types.Position{
+				Latitude:  55.78,
+				Longitude: 37.665,
+			},
+			maxGap: 2 * time.Hour,
+		},
+		{
+			name:     "gap is too large",
+			ts:       time.Date(2019, 10, 7, 18, 17, 0, 0, time.UTC),
+			expError: ErrGapTooLarge,
+		},
+		{
+			name:     "before track start",
+			ts:       time.Date(2019, 10, 7, 17, 59, 59, 0, time.UTC),
+			expError: ErrOutOfRange,
+		},
+		{
+			name:     "after track end",
+			ts:       time.Date(2019, 10, 7, 19, 30, 1, 0, time.UTC),
+			expError: ErrOutOfRange,
+		},
+	}
+
+	for _, tc := range tcs {
+		g := New(track, tc.maxGap, tc.offset)
+
+		pos, err := g.Locate(tc.ts)
+		if tc.expError != nil {
+			r.Errorf(err, tc.name)
+			r.Equalf(tc.expError, err, tc.name)
+			continue
+		}
+
+		r.NoErrorf(err, tc.name)
+		r.InDeltaf(tc.expPosition.Latitude, pos.Latitude, 1e-9, tc.name)
+		r.InDeltaf(tc.expPosition.Longitude, pos.Longitude, 1e-9, tc.name)
+		if tc.expPosition.Altitude == nil {
+			r.Nilf(pos.Altitude, tc.name)
+		} else {
+			r.NotNilf(pos.Altitude, tc.name)
+			r.InDeltaf(*tc.expPosition.Altitude, *pos.Altitude, 1e-9, tc.name)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	r := require.New(t)
+
+	track, err := ParseFile("testdata/track.gpx")
+	r.NoError(err)
+
+	recorded := &types.Position{Latitude: 1, Longitude: 2}
+	films := []*types.Film{
+		{
+			ID:       types.PtrInt64(139),
+			CameraID: types.PtrUint8(1),
+			Frames: []*types.Frame{
+				{Number: types.PtrInt64(1), Timestamp: types.PtrTime(time.Date(2019, 10, 7, 18, 0, 0, 0, time.UTC))},
+				{Number: types.PtrInt64(2), Timestamp: types.PtrTime(time.Date(2019, 10, 7, 17, 0, 0, 0, time.UTC))},
+				{Number: types.PtrInt64(3)},
+				{Number: types.PtrInt64(4), Timestamp: types.PtrTime(time.Date(2019, 10, 7, 17, 0, 0, 0, time.UTC)), Position: recorded},
+			},
+		},
+	}
+
+	report := New(track, 0, 0).Apply(films)
+	r.Equal("1 of 3 frames located", report.String())
+	r.Equal([]string{
+		"film 01-139 frame 2: timestamp is out of track log time range",
+		"film 01-139 frame 3: no timestamp recorded",
+	}, report.Problems)
+
+	frames := films[0].Frames
+	r.NotNil(frames[0].Position)
+	r.InDelta(55.75, frames[0].Position.Latitude, 1e-9)
+	r.Nil(frames[1].Position)
+	r.Nil(frames[2].Position)
+	r.Equal(recorded, frames[3].Position)
+}
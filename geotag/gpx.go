@@ -0,0 +1,56 @@
+package geotag
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type gpxDocument struct {
+	Tracks []struct {
+		Segments []struct {
+			Points []struct {
+				Latitude  float64  `xml:"lat,attr"`
+				Longitude float64  `xml:"lon,attr"`
+				Elevation *float64 `xml:"ele"`
+				Time      string   `xml:"time"`
+			} `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+}
+
+// ParseGPX reads track points from GPX document
+func ParseGPX(r io.Reader) (Track, error) {
+	var doc gpxDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, errors.Wrap(err, "error decoding GPX document")
+	}
+
+	t := Track{}
+	for _, trk := range doc.Tracks {
+		for _, seg := range trk.Segments {
+			for _, pt := range seg.Points {
+				if pt.Time == "" {
+					continue
+				}
+
+				ts, err := time.Parse(time.RFC3339, pt.Time)
+				if err != nil {
+					return nil, errors.Wrap(err, "error parsing GPX track point time")
+				}
+
+				p := Point{Time: ts}
+				p.Latitude = pt.Latitude
+				p.Longitude = pt.Longitude
+				p.Altitude = pt.Elevation
+
+				t = append(t, p)
+			}
+		}
+	}
+	t.sort()
+
+	return t, nil
+}
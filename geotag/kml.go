@@ -0,0 +1,123 @@
+package geotag
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ParseKML reads track points from KML document. Both gx:Track elements
+// and Placemarks with TimeStamp and Point are supported.
+func ParseKML(r io.Reader) (Track, error) {
+	dec := xml.NewDecoder(r)
+
+	var (
+		t      = Track{}
+		stack  []string
+		whens  []string
+		coords []string
+	)
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "error decoding KML document")
+		}
+
+		switch e := tok.(type) {
+		case xml.StartElement:
+			stack = append(stack, e.Name.Local)
+			if e.Name.Local == "Track" || e.Name.Local == "Placemark" {
+				whens, coords = nil, nil
+			}
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+			if e.Name.Local != "Track" && e.Name.Local != "Placemark" {
+				continue
+			}
+
+			if e.Name.Local == "Track" && len(whens) != len(coords) {
+				return nil, errors.Errorf("KML track has %d timestamps but %d coordinates", len(whens), len(coords))
+			}
+
+			for i := 0; i < len(whens) && i < len(coords); i++ {
+				p, err := kmlPoint(whens[i], coords[i], e.Name.Local == "Track")
+				if err != nil {
+					return nil, err
+				}
+				t = append(t, *p)
+			}
+			whens, coords = nil, nil
+		case xml.CharData:
+			if len(stack) == 0 {
+				continue
+			}
+			switch stack[len(stack)-1] {
+			case "when":
+				whens = append(whens, strings.TrimSpace(string(e)))
+			case "coord":
+				coords = append(coords, strings.TrimSpace(string(e)))
+			case "coordinates":
+				if inside(stack, "Point") {
+					coords = append(coords, strings.TrimSpace(string(e)))
+				}
+			}
+		}
+	}
+	t.sort()
+
+	return t, nil
+}
+
+func inside(stack []string, name string) bool {
+	for _, s := range stack {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// kmlPoint builds Point from KML values: gx:coord is space separated
+// while Point coordinates are comma separated, both are lon, lat[, alt]
+func kmlPoint(when, coord string, isTrack bool) (*Point, error) {
+	ts, err := time.Parse(time.RFC3339, when)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing KML timestamp")
+	}
+
+	var fields []string
+	if isTrack {
+		fields = strings.Fields(coord)
+	} else {
+		fields = strings.Split(coord, ",")
+	}
+	if len(fields) < 2 {
+		return nil, errors.Errorf("malformed KML coordinates `%s`", coord)
+	}
+
+	values := make([]float64, len(fields))
+	for i, f := range fields {
+		v, err := strconv.ParseFloat(strings.TrimSpace(f), 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "malformed KML coordinates `%s`", coord)
+		}
+		values[i] = v
+	}
+
+	p := &Point{Time: ts}
+	p.Longitude = values[0]
+	p.Latitude = values[1]
+	if len(values) > 2 {
+		p.Altitude = &values[2]
+	}
+
+	return p, nil
+}
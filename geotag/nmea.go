@@ -0,0 +1,166 @@
+package geotag
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ParseNMEA reads track points from NMEA 0183 log. Positions are taken
+// from RMC sentences, altitude is taken from GGA sentences with the same
+// time of day. Sentences with wrong or malformed checksum are skipped as
+// corrupted by the logger.
+func ParseNMEA(r io.Reader) (Track, error) {
+	sc := bufio.NewScanner(r)
+
+	var (
+		t         = Track{}
+		fixTimes  []string
+		altitudes = map[string]float64{}
+		lineNo    int
+	)
+
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimSpace(sc.Text())
+		if !strings.HasPrefix(line, "$") {
+			continue
+		}
+
+		fields, err := nmeaFields(line)
+		if err != nil {
+			continue
+		}
+		if len(fields[0]) < 5 {
+			continue
+		}
+
+		switch fields[0][len(fields[0])-3:] {
+		case "RMC":
+			if len(fields) < 10 || fields[2] != "A" {
+				continue
+			}
+
+			ts, err := nmeaTimestamp(fields[9], fields[1])
+			if err != nil {
+				return nil, errors.Wrapf(err, "line %d: error parsing RMC timestamp", lineNo)
+			}
+
+			lat, err := nmeaCoordinate(fields[3], fields[4])
+			if err != nil {
+				return nil, errors.Wrapf(err, "line %d", lineNo)
+			}
+			lon, err := nmeaCoordinate(fields[5], fields[6])
+			if err != nil {
+				return nil, errors.Wrapf(err, "line %d", lineNo)
+			}
+
+			p := Point{Time: ts}
+			p.Latitude = lat
+			p.Longitude = lon
+			t = append(t, p)
+			fixTimes = append(fixTimes, fields[1])
+		case "GGA":
+			if len(fields) < 10 || fields[6] == "0" || fields[9] == "" {
+				continue
+			}
+
+			alt, err := strconv.ParseFloat(fields[9], 64)
+			if err != nil {
+				return nil, errors.Wrapf(err, "line %d: error parsing GGA altitude", lineNo)
+			}
+			altitudes[fields[1]] = alt
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range t {
+		if alt, ok := altitudes[fixTimes[i]]; ok {
+			t[i].Altitude = &alt
+		}
+	}
+	t.sort()
+
+	return t, nil
+}
+
+func nmeaFields(line string) ([]string, error) {
+	body := line[1:]
+	if idx := strings.IndexByte(body, '*'); idx >= 0 {
+		sum, err := strconv.ParseUint(body[idx+1:], 16, 8)
+		if err != nil {
+			return nil, errors.Errorf("malformed NMEA checksum in `%s`", line)
+		}
+		body = body[:idx]
+
+		var calc byte
+		for i := 0; i < len(body); i++ {
+			calc ^= body[i]
+		}
+		if calc != byte(sum) {
+			return nil, errors.Errorf("NMEA checksum mismatch in `%s`: expected %02X", line, calc)
+		}
+	}
+
+	return strings.Split(body, ","), nil
+}
+
+// nmeaCoordinate converts (d)ddmm.mmmm with hemisphere to decimal degrees
+func nmeaCoordinate(v, hemisphere string) (float64, error) {
+	idx := strings.IndexByte(v, '.')
+	if idx < 0 {
+		idx = len(v)
+	}
+	if idx < 3 {
+		return 0, errors.Errorf("malformed NMEA coordinate `%s`", v)
+	}
+
+	deg, err := strconv.ParseFloat(v[:idx-2], 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "malformed NMEA coordinate `%s`", v)
+	}
+	minutes, err := strconv.ParseFloat(v[idx-2:], 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "malformed NMEA coordinate `%s`", v)
+	}
+
+	c := deg + minutes/60
+	switch hemisphere {
+	case "N", "E":
+	case "S", "W":
+		c = -c
+	default:
+		return 0, errors.Errorf("unknown NMEA hemisphere `%s`", hemisphere)
+	}
+
+	return c, nil
+}
+
+// nmeaTimestamp builds UTC time from ddmmyy date and hhmmss[.sss] time
+func nmeaTimestamp(date, tod string) (time.Time, error) {
+	frac := ""
+	if idx := strings.IndexByte(tod, '.'); idx >= 0 {
+		tod, frac = tod[:idx], tod[idx:]
+	}
+
+	ts, err := time.Parse("020106150405", date+tod)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if frac != "" {
+		f, err := strconv.ParseFloat("0"+frac, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		ts = ts.Add(time.Duration(f * float64(time.Second)))
+	}
+
+	return ts, nil
+}
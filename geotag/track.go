@@ -0,0 +1,81 @@
+package geotag
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+// Point is a single track log record
+type Point struct {
+	types.Position
+
+	Time time.Time
+}
+
+// Track is a list of points ordered by time
+type Track []Point
+
+var (
+	// ErrUnknownFormat ...
+	ErrUnknownFormat = errors.New("unknown track log format: GPX, KML and NMEA are supported")
+
+	// ErrEmptyTrack ...
+	ErrEmptyTrack = errors.New("track log contains no points")
+)
+
+// ParseFile reads track log from file detecting its format by contents
+func ParseFile(path string) (Track, error) {
+	fp, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+
+	return Parse(fp)
+}
+
+// Parse reads track log detecting its format by contents
+func Parse(r io.Reader) (Track, error) {
+	rd := bufio.NewReader(r)
+
+	head, err := rd.Peek(512)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	head = bytes.TrimLeft(head, "\xef\xbb\xbf \t\r\n")
+
+	var t Track
+	switch {
+	case bytes.HasPrefix(head, []byte("$")):
+		t, err = ParseNMEA(rd)
+	case bytes.Contains(head, []byte("<gpx")):
+		t, err = ParseGPX(rd)
+	case bytes.Contains(head, []byte("<kml")):
+		t, err = ParseKML(rd)
+	default:
+		return nil, ErrUnknownFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(t) == 0 {
+		return nil, ErrEmptyTrack
+	}
+
+	return t, nil
+}
+
+func (t Track) sort() {
+	sort.SliceStable(t, func(i, j int) bool {
+		return t[i].Time.Before(t[j].Time)
+	})
+}
@@ -0,0 +1,127 @@
+package geotag
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+func TestParseFile(t *testing.T) {
+	r := require.New(t)
+
+	type testCase struct {
+		name     string
+		filename string
+		expTrack Track
+	}
+
+	expTrack := Track{
+		{
+			Time: time.Date(2019, 10, 7, 18, 0, 0, 0, time.UTC),
+			Position: types.Position{
+				Latitude:  55.75,
+				Longitude: 37.61,
+				Altitude:  types.PtrFloat64(150),
+			},
+		},
+		{
+			Time: time.Date(2019, 10, 7, 18, 4, 0, 0, time.UTC),
+			Position: types.Position{
+				Latitude:  55.76,
+				Longitude: 37.63,
+				Altitude:  types.PtrFloat64(160),
+			},
+		},
+		{
+			Time: time.Date(2019, 10, 7, 19, 30, 0, 0, time.UTC),
+			Position: types.Position{
+				Latitude:  55.8,
+				Longitude: 37.7,
+			},
+		},
+	}
+
+	tcs := []testCase{
+		{
+			name:     "GPX",
+			filename: "testdata/track.gpx",
+			expTrack: expTrack,
+		},
+		{
+			name:     "KML",
+			filename: "testdata/track.kml",
+			expTrack: expTrack,
+		},
+		{
+			name:     "NMEA",
+			filename: "testdata/track.nmea",
+			expTrack: expTrack,
+		},
+	}
+
+	for _, tc := range tcs {
+		track, err := ParseFile(tc.filename)
+		r.NoErrorf(err, tc.name)
+		r.Lenf(track, len(tc.expTrack), tc.name)
+
+		for i := range tc.expTrack {
+			r.Truef(tc.expTrack[i].Time.Equal(track[i].Time), "%s: point %d: %s != %s", tc.name, i, tc.expTrack[i].Time, track[i].Time)
+			r.InDeltaf(tc.expTrack[i].Latitude, track[i].Latitude, 1e-9, tc.name)
+			r.InDeltaf(tc.expTrack[i].Longitude, track[i].Longitude, 1e-9, tc.name)
+			if tc.expTrack[i].Altitude == nil {
+				r.Nilf(track[i].Altitude, tc.name)
+			} else {
+				r.NotNilf(track[i].Altitude, tc.name)
+				r.InDeltaf(*tc.expTrack[i].Altitude, *track[i].Altitude, 1e-9, tc.name)
+			}
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	r := require.New(t)
+
+	type testCase struct {
+		name     string
+		input    string
+		expError string
+	}
+
+	tcs := []testCase{
+		{
+			name:     "unknown format",
+			input:    "lat,lon\n1,2\n",
+			expError: ErrUnknownFormat.Error(),
+		},
+		{
+			name:     "empty track",
+			input:    `<gpx version="1.1"></gpx>`,
+			expError: ErrEmptyTrack.Error(),
+		},
+		{
+			name:     "NMEA sentence with checksum mismatch is skipped",
+			input:    "$GPRMC,180000.00,A,5545.0000,N,03736.6000,E,0.0,0.0,071019,,,A*00\n",
+			expError: ErrEmptyTrack.Error(),
+		},
+		{
+			name:     "NMEA unknown hemisphere",
+			input:    "$GPRMC,180000.00,A,5545.0000,X,03736.6000,E,0.0,0.0,071019,,,A\n",
+			expError: "line 1: unknown NMEA hemisphere `X`",
+		},
+		{
+			name:     "KML track with mismatched lengths",
+			input:    `<kml><gx:Track><when>2019-10-07T18:00:00Z</when></gx:Track></kml>`,
+			expError: "KML track has 1 timestamps but 0 coordinates",
+		},
+	}
+
+	for _, tc := range tcs {
+		_, err := Parse(strings.NewReader(tc.input))
+		r.Errorf(err, tc.name)
+		r.Equalf(tc.expError, err.Error(), tc.name)
+	}
+}
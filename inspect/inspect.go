@@ -0,0 +1,323 @@
+package inspect
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+// Column of the frames table
+type Column struct {
+	Name   string
+	Header string
+
+	value func(*types.Frame) string
+}
+
+// Columns are all the columns available
+var Columns = []Column{
+	{Name: "no", Header: "NO", value: func(f *types.Frame) string { return intValue(f.Number) }},
+	{Name: "timestamp", Header: "TIMESTAMP", value: func(f *types.Frame) string { return timeValue(f.Timestamp) }},
+	{Name: "tv", Header: "TV", value: func(f *types.Frame) string { return strValue(f.Tv) }},
+	{Name: "av", Header: "AV", value: func(f *types.Frame) string {
+		if f.Av == nil {
+			return ""
+		}
+		return f.Av.String()
+	}},
+	{Name: "iso", Header: "ISO", value: func(f *types.Frame) string { return intValue(f.ISO) }},
+	{Name: "ec", Header: "EC", value: func(f *types.Frame) string { return compensationValue(f.ExposureCompensation) }},
+	{Name: "fec", Header: "FEC", value: func(f *types.Frame) string { return compensationValue(f.FlashCompensation) }},
+	{Name: "focal", Header: "FOCAL", value: func(f *types.Frame) string {
+		if f.FocalLength == nil {
+			return ""
+		}
+		return fmt.Sprintf("%dmm", *f.FocalLength)
+	}},
+	{Name: "lens", Header: "LENS", value: func(f *types.Frame) string { return strValue(f.Lens) }},
+	{Name: "shooting", Header: "SHOOTING", value: func(f *types.Frame) string {
+		if f.ShootingMode == nil {
+			return ""
+		}
+		return f.ShootingMode.String()
+	}},
+	{Name: "metering", Header: "METERING", value: func(f *types.Frame) string {
+		if f.MeteringMode == nil {
+			return ""
+		}
+		return f.MeteringMode.String()
+	}},
+	{Name: "flash", Header: "FLASH", value: func(f *types.Frame) string {
+		if f.FlashMode == nil {
+			return ""
+		}
+		return f.FlashMode.String()
+	}},
+	{Name: "af", Header: "AF", value: func(f *types.Frame) string {
+		if f.AFMode == nil {
+			return ""
+		}
+		return f.AFMode.String()
+	}},
+	{Name: "advance", Header: "ADVANCE", value: func(f *types.Frame) string {
+		if f.FilmAdvanceMode == nil {
+			return ""
+		}
+		return f.FilmAdvanceMode.String()
+	}},
+	{Name: "multiple", Header: "MULTIPLE", value: func(f *types.Frame) string {
+		if f.MultipleExposure == nil {
+			return ""
+		}
+		return f.MultipleExposure.String()
+	}},
+	{Name: "position", Header: "POSITION", value: func(f *types.Frame) string {
+		if f.Position == nil {
+			return ""
+		}
+		return fmt.Sprintf("%.5f,%.5f", f.Position.Latitude, f.Position.Longitude)
+	}},
+	{Name: "remarks", Header: "REMARKS", value: func(f *types.Frame) string { return strValue(f.Remarks) }},
+}
+
+// DefaultColumns are the names of the columns printed unless selected
+var DefaultColumns = []string{"no", "timestamp", "tv", "av", "iso", "ec", "focal", "shooting", "metering", "remarks"}
+
+// Frame marks printed in the first column of the frames table
+const (
+	MarkFlagged     = "*"
+	MarkNoTimestamp = "!"
+	MarkEmpty       = "-"
+)
+
+// Options of the output
+type Options struct {
+	// Columns of the frames table, DefaultColumns are used if empty
+	Columns []Column
+
+	// Camera returns camera name printed along with camera ID, optional
+	Camera func(*types.Film) string
+}
+
+// Value returns the column value of the frame, empty if it's not recorded
+func (c Column) Value(f *types.Frame) string {
+	return c.value(f)
+}
+
+// ParseColumns looks up columns by the comma separated list of names
+func ParseColumns(s string) ([]Column, error) {
+	columns := []Column{}
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		c, ok := lookupColumn(name)
+		if !ok {
+			names := make([]string, len(Columns))
+			for i, c := range Columns {
+				names[i] = c.Name
+			}
+			return nil, errors.Errorf("unknown column `%s`, available columns: %s", name, strings.Join(names, ", "))
+		}
+		columns = append(columns, c)
+	}
+
+	if len(columns) == 0 {
+		return nil, errors.New("no columns selected")
+	}
+	return columns, nil
+}
+
+func lookupColumn(name string) (Column, bool) {
+	for _, c := range Columns {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Column{}, false
+}
+
+// Write prints every film properties followed by the table of its frames.
+// Flagged frames, frames without timestamp and empty frames are marked,
+// the legend is printed after the films if any frame is marked.
+func Write(w io.Writer, films []*types.Film, opts Options) error {
+	columns := opts.Columns
+	if len(columns) == 0 {
+		for _, name := range DefaultColumns {
+			c, _ := lookupColumn(name)
+			columns = append(columns, c)
+		}
+	}
+
+	ew := &errWriter{w: w}
+	marked := false
+	for i, f := range films {
+		if i > 0 {
+			ew.printf("\n")
+		}
+		writeFilm(ew, f, opts.Camera)
+		if len(f.Frames) == 0 {
+			continue
+		}
+		ew.printf("\n")
+		if writeFrames(ew, f, columns) {
+			marked = true
+		}
+	}
+
+	if marked {
+		ew.printf("\nMarks: %s flagged, %s no timestamp, %s empty frame\n", MarkFlagged, MarkNoTimestamp, MarkEmpty)
+	}
+
+	return ew.err
+}
+
+func writeFilm(ew *errWriter, f *types.Film, camera func(*types.Film) string) {
+	cameraID := ""
+	if f.CameraID != nil {
+		cameraID = fmt.Sprintf("%02d", *f.CameraID)
+	}
+	if camera != nil {
+		if name := camera(f); name != "" {
+			cameraID += " (" + name + ")"
+		}
+	}
+
+	stock := ""
+	if f.Stock != nil {
+		stock = f.Stock.String()
+	}
+
+	frames := fmt.Sprintf("%d present", len(f.Frames))
+	if f.FrameCount != nil {
+		frames = fmt.Sprintf("%d present of %d counted", len(f.Frames), *f.FrameCount)
+	}
+
+	ew.printf("Film:      %s\n", f.FullID())
+	ew.printf("Camera:    %s\n", cameraID)
+	ew.printf("Title:     %s\n", strValue(f.Title))
+	ew.printf("Loaded:    %s\n", timeValue(f.FilmLoadedTimestamp))
+	ew.printf("ISO (DX):  %s\n", intValue(f.ISO))
+	ew.printf("Frames:    %s\n", frames)
+	if f.Stock != nil {
+		ew.printf("Stock:     %s\n", stock)
+	}
+	if f.Remarks != nil {
+		ew.printf("Remarks:   %s\n", strValue(f.Remarks))
+	}
+}
+
+// writeFrames prints the table of frames and reports whether any frame is
+// marked
+func writeFrames(ew *errWriter, f *types.Film, columns []Column) bool {
+	buf := &bytes.Buffer{}
+	tw := tabwriter.NewWriter(buf, 0, 4, 2, ' ', 0)
+
+	header := []string{""}
+	for _, c := range columns {
+		header = append(header, c.Header)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	marked := false
+	for _, fr := range f.Frames {
+		row := []string{Marks(f, fr)}
+		if row[0] != "" {
+			marked = true
+		}
+		for _, c := range columns {
+			row = append(row, c.Value(fr))
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	// the buffer never fails, padding of empty trailing cells is trimmed
+	_ = tw.Flush()
+	for _, l := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		ew.printf("%s\n", strings.TrimRight(l, " "))
+	}
+	return marked
+}
+
+// Marks returns the marks of the frame of the film
+func Marks(film *types.Film, f *types.Frame) string {
+	marks := ""
+	if f.Flag != nil && *f.Flag {
+		marks += MarkFlagged
+	}
+	if IsEmpty(film, f) {
+		return marks + MarkEmpty
+	}
+	if f.Timestamp == nil || f.Timestamp.IsZero() {
+		marks += MarkNoTimestamp
+	}
+	return marks
+}
+
+// IsEmpty reports whether the frame of the film has no exposure data
+// recorded. Lens data and the film ISO (DX) copied into every frame by
+// parser are not exposure data.
+func IsEmpty(film *types.Film, f *types.Frame) bool {
+	filmISO := f.ISO == nil || (film.ISO != nil && *f.ISO == *film.ISO)
+	return f.Tv == nil && f.Av == nil && filmISO &&
+		f.ShootingMode == nil && f.MeteringMode == nil &&
+		(f.Timestamp == nil || f.Timestamp.IsZero())
+}
+
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) Write(p []byte) (int, error) {
+	if ew.err != nil {
+		return 0, ew.err
+	}
+	n, err := ew.w.Write(p)
+	ew.err = err
+	return n, err
+}
+
+func (ew *errWriter) printf(format string, args ...interface{}) {
+	fmt.Fprintf(ew, format, args...)
+}
+
+func strValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return strings.TrimSpace(*s)
+}
+
+func intValue(i *int64) string {
+	if i == nil {
+		return ""
+	}
+	return strconv.FormatInt(*i, 10)
+}
+
+func timeValue(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func compensationValue(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	if *v > 0 {
+		return "+" + strconv.FormatFloat(*v, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
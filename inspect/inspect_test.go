@@ -0,0 +1,218 @@
+package inspect
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	parser "github.com/teran/eos-1v-tagger/parser"
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+func TestWrite(t *testing.T) {
+	r := require.New(t)
+
+	films := []*types.Film{
+		{
+			ID:                  types.PtrInt64(139),
+			CameraID:            types.PtrUint8(1),
+			Title:               types.PtrString("SampleTest film #139"),
+			FilmLoadedTimestamp: types.PtrTime(time.Date(2019, 9, 28, 10, 21, 32, 0, time.UTC)),
+			ISO:                 types.PtrInt64(400),
+			FrameCount:          types.PtrInt64(4),
+			Remarks:             types.PtrString("test remarks"),
+			Frames: []*types.Frame{
+				{
+					Number:               types.PtrInt64(1),
+					Tv:                   types.PtrString("1/40"),
+					Av:                   types.PtrAperture(1.4),
+					ISO:                  types.PtrInt64(400),
+					ExposureCompensation: types.PtrFloat64(0.3),
+					FocalLength:          types.PtrInt64(24),
+					ShootingMode:         types.PtrShootingMode(types.ShootingModeAperturePriorityAE),
+					Timestamp:            types.PtrTime(time.Date(2019, 10, 7, 20, 2, 18, 0, time.UTC)),
+				},
+				{
+					Flag:                 types.PtrBool(true),
+					Number:               types.PtrInt64(2),
+					Tv:                   types.PtrString("1/60"),
+					Av:                   types.PtrAperture(2.8),
+					ExposureCompensation: types.PtrFloat64(-1),
+					Remarks:              types.PtrString("keeper"),
+				},
+				{
+					Number: types.PtrInt64(3),
+				},
+			},
+		},
+		{
+			ID:       types.PtrInt64(12),
+			CameraID: types.PtrUint8(20),
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	err := Write(buf, films, Options{
+		Camera: func(f *types.Film) string {
+			if *f.CameraID == 1 {
+				return "Canon EOS-1V"
+			}
+			return ""
+		},
+	})
+	r.NoError(err)
+	r.Equal(`Film:      01-139
+Camera:    01 (Canon EOS-1V)
+Title:     SampleTest film #139
+Loaded:    2019-09-28T10:21:32Z
+ISO (DX):  400
+Frames:    3 present of 4 counted
+Remarks:   test remarks
+
+    NO  TIMESTAMP             TV    AV   ISO  EC    FOCAL  SHOOTING              METERING  REMARKS
+    1   2019-10-07T20:02:18Z  1/40  1.4  400  +0.3  24mm   Aperture-priority AE
+*!  2                         1/60  2.8       -1                                           keeper
+-   3
+
+Film:      20-012
+Camera:    20
+Title:     
+Loaded:    
+ISO (DX):  
+Frames:    0 present
+
+Marks: * flagged, ! no timestamp, - empty frame
+`, buf.String())
+}
+
+func TestWriteColumns(t *testing.T) {
+	r := require.New(t)
+
+	columns, err := ParseColumns("no, tv,AV,position")
+	r.NoError(err)
+
+	buf := &bytes.Buffer{}
+	err = Write(buf, []*types.Film{
+		{
+			ID:       types.PtrInt64(1),
+			CameraID: types.PtrUint8(0),
+			Frames: []*types.Frame{
+				{Number: types.PtrInt64(1), Tv: types.PtrString("1/125"), Av: types.PtrAperture(8), Timestamp: types.PtrTime(time.Now())},
+				{Number: types.PtrInt64(2), Timestamp: types.PtrTime(time.Now()), Position: &types.Position{Latitude: 55.755833, Longitude: -37.617222}},
+			},
+		},
+	}, Options{Columns: columns})
+	r.NoError(err)
+	r.Equal(`Film:      00-001
+Camera:    00
+Title:     
+Loaded:    
+ISO (DX):  
+Frames:    2 present
+
+  NO  TV     AV   POSITION
+  1   1/125  8.0
+  2               55.75583,-37.61722
+`, buf.String())
+}
+
+func TestParseColumns(t *testing.T) {
+	r := require.New(t)
+
+	_, err := ParseColumns("no,shutter")
+	r.Error(err)
+	r.Equal("unknown column `shutter`, available columns: no, timestamp, tv, av, iso, ec, fec, focal, lens, shooting, metering, flash, af, advance, multiple, position, remarks", err.Error())
+
+	_, err = ParseColumns(" , ")
+	r.Error(err)
+	r.Equal("no columns selected", err.Error())
+}
+
+func TestMarksCSV(t *testing.T) {
+	r := require.New(t)
+
+	type testCase struct {
+		name     string
+		filename string
+		expMarks []string
+	}
+
+	tcs := []testCase{
+		{
+			name:     "partial data",
+			filename: "../parser/testdata/partial-data.csv",
+			expMarks: []string{"-", "-", "-", "!", "!", "!", "!", "!", "!", "!", "!", "*!", "!", "", "*"},
+		},
+		{
+			name:     "unexposed frames",
+			filename: "../parser/testdata/unexposed-frames.csv",
+			expMarks: []string{"", "-", "*-"},
+		},
+	}
+
+	for _, tc := range tcs {
+		p, err := parser.New(tc.filename, types.TimestampFormatUS.TimeLayout(), func(uint8) *time.Location { return time.UTC })
+		r.NoErrorf(err, tc.name)
+
+		films, err := p.Parse()
+		p.Close()
+		r.NoErrorf(err, tc.name)
+		r.Lenf(films, 1, tc.name)
+
+		marks := []string{}
+		for _, f := range films[0].Frames {
+			marks = append(marks, Marks(films[0], f))
+		}
+		r.Equalf(tc.expMarks, marks, tc.name)
+	}
+}
+
+func TestMarks(t *testing.T) {
+	r := require.New(t)
+
+	type testCase struct {
+		name     string
+		frame    *types.Frame
+		expected string
+	}
+
+	tcs := []testCase{
+		{
+			name:     "regular frame",
+			frame:    &types.Frame{Tv: types.PtrString("1/60"), Timestamp: types.PtrTime(time.Now())},
+			expected: "",
+		},
+		{
+			name:     "empty flagged frame",
+			frame:    &types.Frame{Flag: types.PtrBool(true), Number: types.PtrInt64(3)},
+			expected: "*-",
+		},
+		{
+			name:     "no timestamp",
+			frame:    &types.Frame{Av: types.PtrAperture(4)},
+			expected: "!",
+		},
+		{
+			name:     "unflagged",
+			frame:    &types.Frame{Flag: types.PtrBool(false), Av: types.PtrAperture(4), Timestamp: types.PtrTime(time.Now())},
+			expected: "",
+		},
+		{
+			name:     "film ISO and lens data only",
+			frame:    &types.Frame{Number: types.PtrInt64(2), ISO: types.PtrInt64(400), FocalLength: types.PtrInt64(35)},
+			expected: "-",
+		},
+		{
+			name:     "ISO set for the frame",
+			frame:    &types.Frame{Number: types.PtrInt64(2), ISO: types.PtrInt64(640)},
+			expected: "!",
+		},
+	}
+
+	film := &types.Film{ISO: types.PtrInt64(400)}
+	for _, tc := range tcs {
+		r.Equalf(tc.expected, Marks(film, tc.frame), tc.name)
+	}
+}
@@ -0,0 +1,209 @@
+package merge
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+// Input is the set of films parsed from a single source
+type Input struct {
+	Source string
+	Films  []*types.Film
+}
+
+// Conflict describes two different versions of the same frame found in
+// different inputs. The version from the later input is used.
+type Conflict struct {
+	FilmID  string
+	FrameNo int64
+	Sources [2]string
+	Fields  []string
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("film %s frame %d: %s and %s differ in %s; using %s",
+		c.FilmID, c.FrameNo, c.Sources[0], c.Sources[1], strings.Join(c.Fields, ", "), c.Sources[1])
+}
+
+// Report summarizes the merge
+type Report struct {
+	Inputs     int
+	Films      int
+	Frames     int
+	Duplicates int
+	Conflicts  []Conflict
+}
+
+func (r Report) String() string {
+	return fmt.Sprintf("%d inputs merged into %d films with %d frames, %d duplicate frames skipped, %d conflicts",
+		r.Inputs, r.Films, r.Frames, r.Duplicates, len(r.Conflicts))
+}
+
+type film struct {
+	*types.Film
+
+	// frames maps frame number to the input the frame version came from
+	frames map[int64]string
+}
+
+// Films merges films from several inputs into single film set. Films are
+// matched by camera ID and film ID: film properties set in the later input
+// override the earlier ones, frames are matched by frame number. Identical
+// frames are deduplicated, conflicting versions of the same frame are
+// reported and resolved in favour of the later input. Frames without number
+// are deduplicated if identical to the ones of the earlier inputs including
+// the timestamp, otherwise they're added.
+//
+// Films are returned in the order of first appearance with frames sorted
+// by number. Input films are not modified.
+func Films(inputs []Input) ([]*types.Film, *Report) {
+	report := &Report{
+		Inputs:    len(inputs),
+		Conflicts: []Conflict{},
+	}
+
+	order := []string{}
+	films := map[string]*film{}
+	for _, in := range inputs {
+		for _, f := range in.Films {
+			id := f.FullID()
+
+			m, ok := films[id]
+			if !ok {
+				cp := *f
+				cp.Frames = nil
+				m = &film{Film: &cp, frames: map[int64]string{}}
+				films[id] = m
+				order = append(order, id)
+			} else {
+				mergeFilm(m.Film, f)
+			}
+
+			// frames without number are matched by content to the ones of
+			// the earlier inputs, each of them matches once
+			earlier := len(m.Frames)
+			matched := map[int]bool{}
+			for _, fr := range f.Frames {
+				if fr.Number == nil {
+					idx := unnumberedFrameIndex(m.Frames[:earlier], fr, matched)
+					if idx < 0 {
+						m.Frames = append(m.Frames, fr)
+						continue
+					}
+					matched[idx] = true
+					report.Duplicates++
+					continue
+				}
+
+				idx := frameIndex(m.Frames, *fr.Number)
+				if idx < 0 {
+					m.Frames = append(m.Frames, fr)
+					m.frames[*fr.Number] = in.Source
+					continue
+				}
+
+				fields := diffFrames(m.Frames[idx], fr)
+				if len(fields) == 0 {
+					report.Duplicates++
+					continue
+				}
+
+				report.Conflicts = append(report.Conflicts, Conflict{
+					FilmID:  id,
+					FrameNo: *fr.Number,
+					Sources: [2]string{m.frames[*fr.Number], in.Source},
+					Fields:  fields,
+				})
+				m.Frames[idx] = fr
+				m.frames[*fr.Number] = in.Source
+			}
+		}
+	}
+
+	result := make([]*types.Film, len(order))
+	for i, id := range order {
+		f := films[id].Film
+		sort.SliceStable(f.Frames, func(i, j int) bool {
+			if f.Frames[i].Number == nil || f.Frames[j].Number == nil {
+				return f.Frames[j].Number == nil && f.Frames[i].Number != nil
+			}
+			return *f.Frames[i].Number < *f.Frames[j].Number
+		})
+		result[i] = f
+
+		report.Films++
+		report.Frames += len(f.Frames)
+	}
+
+	return result, report
+}
+
+func frameIndex(frames []*types.Frame, number int64) int {
+	for i, fr := range frames {
+		if fr.Number != nil && *fr.Number == number {
+			return i
+		}
+	}
+	return -1
+}
+
+// unnumberedFrameIndex returns the index of the frame without number
+// identical to fr skipping the matched ones
+func unnumberedFrameIndex(frames []*types.Frame, fr *types.Frame, matched map[int]bool) int {
+	for i, f := range frames {
+		if f.Number == nil && !matched[i] && len(diffFrames(f, fr)) == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// mergeFilm sets film properties set in src over dst, frames are skipped
+func mergeFilm(dst, src *types.Film) {
+	dv := reflect.ValueOf(dst).Elem()
+	sv := reflect.ValueOf(src).Elem()
+	for i := 0; i < sv.NumField(); i++ {
+		f := sv.Field(i)
+		if f.Kind() != reflect.Ptr || f.IsNil() {
+			continue
+		}
+		dv.Field(i).Set(f)
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// diffFrames returns names of the fields differing between frames
+func diffFrames(a, b *types.Frame) []string {
+	fields := []string{}
+
+	av := reflect.ValueOf(a).Elem()
+	bv := reflect.ValueOf(b).Elem()
+	for i := 0; i < av.NumField(); i++ {
+		if !equalValues(av.Field(i), bv.Field(i)) {
+			fields = append(fields, av.Type().Field(i).Name)
+		}
+	}
+
+	return fields
+}
+
+func equalValues(a, b reflect.Value) bool {
+	if a.Kind() == reflect.Ptr {
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		a, b = a.Elem(), b.Elem()
+	}
+
+	if a.Type() == timeType {
+		return a.Interface().(time.Time).Equal(b.Interface().(time.Time))
+	}
+
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
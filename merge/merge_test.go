@@ -0,0 +1,151 @@
+package merge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+func TestFilms(t *testing.T) {
+	r := require.New(t)
+
+	ts := time.Date(2019, 10, 7, 20, 2, 18, 0, time.UTC)
+	frame := func(n int64, tv string) *types.Frame {
+		return &types.Frame{
+			Number:    types.PtrInt64(n),
+			Tv:        types.PtrString(tv),
+			Timestamp: types.PtrTime(ts.Add(time.Duration(n) * time.Minute)),
+		}
+	}
+
+	first := Input{
+		Source: "a.csv",
+		Films: []*types.Film{
+			{
+				ID:       types.PtrInt64(139),
+				CameraID: types.PtrUint8(1),
+				Frames:   []*types.Frame{frame(1, "1/60"), frame(2, "1/125")},
+			},
+		},
+	}
+
+	second := Input{
+		Source: "b.csv",
+		Films: []*types.Film{
+			{
+				ID:       types.PtrInt64(140),
+				CameraID: types.PtrUint8(1),
+				Frames:   []*types.Frame{frame(1, "1/250")},
+			},
+			{
+				ID:         types.PtrInt64(139),
+				CameraID:   types.PtrUint8(1),
+				FrameCount: types.PtrInt64(3),
+				Frames: []*types.Frame{
+					frame(3, "1/30"),
+					{
+						Number:    types.PtrInt64(1),
+						Tv:        types.PtrString("1/60"),
+						Timestamp: types.PtrTime(ts.Add(time.Minute).In(time.FixedZone("MSK", 3*3600))),
+					},
+					frame(2, "1/500"),
+				},
+			},
+		},
+	}
+
+	films, report := Films([]Input{first, second})
+	r.Equal([]*types.Film{
+		{
+			ID:         types.PtrInt64(139),
+			CameraID:   types.PtrUint8(1),
+			FrameCount: types.PtrInt64(3),
+			Frames:     []*types.Frame{frame(1, "1/60"), frame(2, "1/500"), frame(3, "1/30")},
+		},
+		{
+			ID:       types.PtrInt64(140),
+			CameraID: types.PtrUint8(1),
+			Frames:   []*types.Frame{frame(1, "1/250")},
+		},
+	}, films)
+
+	r.Equal(&Report{
+		Inputs:     2,
+		Films:      2,
+		Frames:     4,
+		Duplicates: 1,
+		Conflicts: []Conflict{
+			{
+				FilmID:  "01-139",
+				FrameNo: 2,
+				Sources: [2]string{"a.csv", "b.csv"},
+				Fields:  []string{"Tv"},
+			},
+		},
+	}, report)
+	r.Equal("film 01-139 frame 2: a.csv and b.csv differ in Tv; using b.csv", report.Conflicts[0].String())
+
+	// input films are left intact
+	r.Len(first.Films[0].Frames, 2)
+	r.Nil(first.Films[0].FrameCount)
+}
+
+func TestFilmsSingleInput(t *testing.T) {
+	r := require.New(t)
+
+	in := []*types.Film{
+		{
+			ID:       types.PtrInt64(1),
+			CameraID: types.PtrUint8(9),
+			Frames: []*types.Frame{
+				{Number: types.PtrInt64(1)},
+				{Number: types.PtrInt64(2)},
+			},
+		},
+	}
+
+	films, report := Films([]Input{{Source: "a.csv", Films: in}})
+	r.Equal(in, films)
+	r.Equal(&Report{Inputs: 1, Films: 1, Frames: 2, Conflicts: []Conflict{}}, report)
+}
+
+func TestFilmsUnnumberedFrames(t *testing.T) {
+	r := require.New(t)
+
+	ts := types.PtrTime(time.Date(2019, 10, 7, 20, 2, 18, 0, time.UTC))
+	first := []*types.Film{
+		{
+			ID:       types.PtrInt64(1),
+			CameraID: types.PtrUint8(9),
+			Frames: []*types.Frame{
+				{Tv: types.PtrString("1/60"), Timestamp: ts},
+				{Tv: types.PtrString("1/60")},
+			},
+		},
+	}
+	second := []*types.Film{
+		{
+			ID:       types.PtrInt64(1),
+			CameraID: types.PtrUint8(9),
+			Frames: []*types.Frame{
+				{Tv: types.PtrString("1/60"), Timestamp: ts},
+				{Tv: types.PtrString("1/125"), Timestamp: ts},
+				{Tv: types.PtrString("1/60")},
+				{Tv: types.PtrString("1/60")},
+			},
+		},
+	}
+
+	films, report := Films([]Input{{Source: "a.csv", Films: first}, {Source: "b.csv", Films: second}})
+	r.Len(films, 1)
+	r.Equal(2, report.Duplicates)
+	r.Equal([]*types.Frame{
+		first[0].Frames[0],
+		first[0].Frames[1],
+		second[0].Frames[1],
+		second[0].Frames[3],
+	}, films[0].Frames)
+}
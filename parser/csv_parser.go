@@ -20,6 +20,16 @@ type CSVParser struct {
 	rc              io.ReadCloser
 	tzfn            func(uint8) *time.Location
 	timestampFormat string
+	layouts         *DateLayouts
+}
+
+// DateLayouts are the layouts of film loaded, frame and battery loaded dates
+// of ES-E1 CSV file. ES-E1 software doesn't zero pad day and month but
+// edited files might have them padded.
+type DateLayouts struct {
+	Film    string
+	Frame   string
+	Battery string
 }
 
 var (
@@ -53,6 +63,9 @@ func (p *CSVParser) Close() error {
 func (p *CSVParser) Parse() ([]*types.Film, error) {
 	rd := bufio.NewReader(p.rc)
 
+	layout := dateLayout(p.timestampFormat)
+	p.layouts = &DateLayouts{Film: layout, Frame: layout, Battery: layout}
+
 	films := []*types.Film{}
 	var f *types.Film
 	for {
@@ -78,6 +91,7 @@ func (p *CSVParser) Parse() ([]*types.Film, error) {
 			if err != nil {
 				return nil, err
 			}
+			p.layouts.Film = padLayout(p.layouts.Film, strings.Split(str, ",")[6])
 			break
 		case isFilmRemarksHeader(str):
 			f.Remarks = parseFilmRemarks(str)
@@ -93,6 +107,10 @@ func (p *CSVParser) Parse() ([]*types.Film, error) {
 				fr.ISO = f.ISO
 			}
 
+			ss := strings.Split(str, ",")
+			p.layouts.Frame = padLayout(p.layouts.Frame, ss[15])
+			p.layouts.Battery = padLayout(p.layouts.Battery, ss[18])
+
 			f.Frames = append(f.Frames, fr)
 		}
 	}
@@ -104,6 +122,12 @@ func (p *CSVParser) Parse() ([]*types.Film, error) {
 	return films, nil
 }
 
+// DateLayouts returns layouts of the dates of the file parsed, nil if the
+// file is not parsed yet
+func (p *CSVParser) DateLayouts() *DateLayouts {
+	return p.layouts
+}
+
 func parseFilmData(s string, timestampFormat string, tzfn func(uint8) *time.Location) (*types.Film, error) {
 	ss := strings.Split(s, ",")
 
@@ -158,8 +182,9 @@ func parseFrameData(s string, film *types.Film, timestampFormat string, tzfn fun
 		return nil, fmt.Errorf("wrong amount of columns for frame: %d: `%s`", len(ss), s)
 	}
 
-	// ss[2:] is everything except flag and number fields
-	if isEmptySliceOfStrings(ss[2:]) {
+	// ss[1:] is everything except flag field, frames with number only are
+	// kept as ES-E1 records them for frames without exposure data
+	if isEmptySliceOfStrings(ss[1:]) {
 		return nil, ErrEmptyFrame
 	}
 
@@ -214,6 +239,15 @@ func parseFrameData(s string, film *types.Film, timestampFormat string, tzfn fun
 			err, "Possible solution: consider using `-timestamp-format` to specify proper format for timestamps")
 	}
 
+	// ES-E1 records the date without time of day for some frames
+	var date *time.Time
+	if timestamp == nil && strings.TrimSpace(ss[16]) == "" {
+		date, err = parseDate(ss[15], tzfn(*film.CameraID), timestampFormat)
+		if err != nil && err != ErrNotProvided {
+			return nil, errors.Wrapf(err, "error parsing date value; frameNo=%d", *frameID)
+		}
+	}
+
 	batteryTimestamp, err := parseTimestamp(ss[18], ss[19], tzfn(*film.CameraID), timestampFormat)
 	if err != nil && err != ErrNotProvided {
 		return nil, errors.Wrapf(err, "error parsing timestamp value; frameNo=%d", *frameID)
@@ -276,6 +310,7 @@ func parseFrameData(s string, film *types.Film, timestampFormat string, tzfn fun
 		AFMode:               afMode,
 		BulbExposureTime:     bulbExposureTime,
 		Timestamp:            timestamp,
+		Date:                 date,
 		MultipleExposure:     multipleExposure,
 		BatteryLoadedDate:    batteryTimestamp,
 		Remarks:              remarks,
@@ -303,6 +338,42 @@ func parseTimestamp(d, t string, tz *time.Location, timestampFormat string) (*ti
 	return &ts, nil
 }
 
+func parseDate(d string, tz *time.Location, timestampFormat string) (*time.Time, error) {
+	if strings.TrimSpace(d) == "" {
+		return nil, ErrNotProvided
+	}
+	ts, err := time.ParseInLocation(dateLayout(timestampFormat), strings.TrimSpace(d), tz)
+	if err != nil {
+		return nil, err
+	}
+	return &ts, nil
+}
+
+// dateLayout returns the date part of timestamp layout
+func dateLayout(timestampFormat string) string {
+	if idx := strings.IndexByte(timestampFormat, 'T'); idx >= 0 {
+		return timestampFormat[:idx]
+	}
+	return timestampFormat
+}
+
+// padLayout zero pads day and month of the date layout if they're padded
+// in the date
+func padLayout(layout, date string) string {
+	lp := strings.Split(layout, "/")
+	dp := strings.Split(strings.TrimSpace(date), "/")
+	if len(lp) != len(dp) {
+		return layout
+	}
+
+	for i := range lp {
+		if (lp[i] == "1" || lp[i] == "2") && len(dp[i]) == 2 && dp[i][0] == '0' {
+			lp[i] = "0" + lp[i]
+		}
+	}
+	return strings.Join(lp, "/")
+}
+
 func isFilmHeader(s string) bool {
 	return strings.HasPrefix(strings.TrimLeft(s, "*"), ",Film ID,")
 }
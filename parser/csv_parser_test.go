@@ -196,6 +196,7 @@ func TestFilmWithPartialTimestampsEUFormatted(t *testing.T) {
 					FilmAdvanceMode:      types.PtrFilmAdvanceMode(types.FilmAdvanceModeSingleFrame),
 					AFMode:               types.PtrAFMode(types.AFModeOneShotAF),
 					Timestamp:            nil,
+					Date:                 types.PtrTime(time.Date(2019, 7, 16, 0, 0, 0, 0, tz)),
 					MultipleExposure:     types.PtrMultipleExposure(types.MultipleExposureOff),
 					BatteryLoadedDate:    nil,
 					ExposureCompensation: types.PtrFloat64(-5),
@@ -497,6 +498,30 @@ func TestEmptyFrame(t *testing.T) {
 	r.Equal(ErrEmptyFrame, err)
 }
 
+func TestUnexposedFrames(t *testing.T) {
+	r := require.New(t)
+
+	p, err := New("testdata/unexposed-frames.csv", types.TimestampFormatUS.TimeLayout(), func(uint8) *time.Location { return time.UTC })
+	r.NoError(err)
+	defer p.Close()
+
+	films, err := p.Parse()
+	r.NoError(err)
+	r.Len(films, 1)
+	r.Len(films[0].Frames, 3)
+
+	r.Equal(&types.Frame{
+		Flag:   types.PtrBool(false),
+		Number: types.PtrInt64(2),
+		ISO:    types.PtrInt64(400),
+	}, films[0].Frames[1])
+	r.Equal(&types.Frame{
+		Flag:   types.PtrBool(true),
+		Number: types.PtrInt64(3),
+		ISO:    types.PtrInt64(400),
+	}, films[0].Frames[2])
+}
+
 func mustParseTimestamp(t *testing.T, ts string, tz *time.Location, tf string) *time.Time {
 	r := require.New(t)
 
@@ -508,3 +533,26 @@ func mustParseTimestamp(t *testing.T, ts string, tz *time.Location, tf string) *
 
 	return tt
 }
+
+func TestPadLayout(t *testing.T) {
+	r := require.New(t)
+
+	type testCase struct {
+		name     string
+		layout   string
+		date     string
+		expected string
+	}
+
+	tcs := []testCase{
+		{name: "not padded", layout: "2/1/2006", date: "28/9/2019", expected: "2/1/2006"},
+		{name: "padded month", layout: "2/1/2006", date: "28/09/2019", expected: "2/01/2006"},
+		{name: "padded day and month", layout: "1/2/2006", date: "07/01/2019", expected: "01/02/2006"},
+		{name: "two digit values", layout: "1/2/2006", date: "10/28/2019", expected: "1/2/2006"},
+		{name: "empty date", layout: "1/2/2006", date: "", expected: "1/2/2006"},
+	}
+
+	for _, tc := range tcs {
+		r.Equalf(tc.expected, padLayout(tc.layout, tc.date), tc.name)
+	}
+}
@@ -0,0 +1,243 @@
+package tagger
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+const (
+	filmHeader  = ",Film ID,%s,Title,%s,Date and time film loaded,%s,%s,Frame count,%s,ISO (DX),%s"
+	frameHeader = ",Frame No.,Focal length,Max. aperture,Tv,Av,ISO (M),Exposure compensation,Flash exposure compensation,Flash mode,Metering mode,Shooting mode,Film advance mode,AF mode,Bulb exposure time,Date,Time,Multiple exposure,Battery-loaded date,Battery-loaded time,Remarks"
+)
+
+// CSVWriter type
+type CSVWriter struct {
+	w          io.Writer
+	tzfn       func(uint8) *time.Location
+	layouts    DateLayouts
+	timeLayout string
+	lineEnding string
+}
+
+// NewWriter creates new CSVWriter object writing films in ES-E1 CSV format.
+// Timestamps are written in the timezone returned by tzfn for the camera
+// in the same format CSVParser reads them, day and month are not zero
+// padded unless set with SetDateLayouts. Frame dates recorded without time
+// of day are written as is. Lines are terminated with CRLF as ES-E1
+// software does, see SetLineEnding.
+//
+// Film loaded and battery loaded dates without time of day are not kept by
+// CSVParser so they're not written back.
+func NewWriter(w io.Writer, timestampFormat string, tzfn func(uint8) *time.Location) *CSVWriter {
+	timeLayout := ""
+	if idx := strings.IndexByte(timestampFormat, 'T'); idx >= 0 {
+		timeLayout = timestampFormat[idx+1:]
+	}
+
+	layout := dateLayout(timestampFormat)
+	return &CSVWriter{
+		w:          w,
+		tzfn:       tzfn,
+		layouts:    DateLayouts{Film: layout, Frame: layout, Battery: layout},
+		timeLayout: timeLayout,
+		lineEnding: "\r\n",
+	}
+}
+
+// SetDateLayouts sets the layouts of the dates, e.g. the ones of the file
+// parsed to write it back as is
+func (w *CSVWriter) SetDateLayouts(l DateLayouts) *CSVWriter {
+	w.layouts = l
+	return w
+}
+
+// SetLineEnding sets the line terminator
+func (w *CSVWriter) SetLineEnding(eol string) *CSVWriter {
+	w.lineEnding = eol
+	return w
+}
+
+// Write ...
+func (w *CSVWriter) Write(films []*types.Film) error {
+	lines := []string{}
+	for i, f := range films {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+
+		fl, err := w.filmLines(f)
+		if err != nil {
+			return errors.Wrapf(err, "film %s", f.FullID())
+		}
+		lines = append(lines, fl...)
+	}
+
+	for _, l := range lines {
+		if _, err := io.WriteString(w.w, l+w.lineEnding); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *CSVWriter) filmLines(f *types.Film) ([]string, error) {
+	var cameraID uint8
+	if f.CameraID != nil {
+		cameraID = *f.CameraID
+	}
+
+	date, tm := w.formatTimestamp(f.FilmLoadedTimestamp, cameraID, w.layouts.Film)
+	header := []string{f.FullID(), stringValue(f.Title), date, tm, intValue(f.FrameCount), intValue(f.ISO)}
+	if err := checkColumns(header); err != nil {
+		return nil, err
+	}
+
+	remarks := ""
+	if f.Remarks != nil {
+		remarks = *f.Remarks
+	}
+
+	lines := []string{
+		fmt.Sprintf(filmHeader, header[0], header[1], header[2], header[3], header[4], header[5]),
+		",Remarks," + remarks,
+		"",
+		frameHeader,
+	}
+
+	for _, fr := range f.Frames {
+		columns := w.frameColumns(f, fr, cameraID)
+		if err := checkColumns(columns); err != nil {
+			return nil, errors.Wrapf(err, "frame %s", columns[1])
+		}
+		lines = append(lines, strings.Join(columns, ","))
+	}
+
+	return lines, nil
+}
+
+func (w *CSVWriter) frameColumns(f *types.Film, fr *types.Frame, cameraID uint8) []string {
+	columns := make([]string, 21)
+
+	if fr.Flag != nil && *fr.Flag {
+		columns[0] = "*"
+	}
+
+	columns[1] = intValue(fr.Number)
+
+	if fr.FocalLength != nil {
+		columns[2] = fmt.Sprintf("%dmm", *fr.FocalLength)
+	}
+
+	if fr.MaxAperture != nil {
+		columns[3] = fr.MaxAperture.String()
+	}
+
+	if fr.Tv != nil {
+		columns[4] = fmt.Sprintf(`="%s"`, *fr.Tv)
+	}
+
+	if fr.Av != nil {
+		columns[5] = fr.Av.String()
+	}
+
+	// CSVParser fills missing frame ISO with the film one so it's omitted
+	// here to get the same CSV back
+	if fr.ISO != nil && (f.ISO == nil || *fr.ISO != *f.ISO) {
+		columns[6] = strconv.FormatInt(*fr.ISO, 10)
+	}
+
+	columns[7] = compensationValue(fr.ExposureCompensation)
+	columns[8] = compensationValue(fr.FlashCompensation)
+
+	if fr.FlashMode != nil {
+		columns[9] = fr.FlashMode.String()
+	}
+
+	if fr.MeteringMode != nil {
+		columns[10] = fr.MeteringMode.String()
+	}
+
+	if fr.ShootingMode != nil {
+		columns[11] = fr.ShootingMode.String()
+	}
+
+	if fr.FilmAdvanceMode != nil {
+		columns[12] = fr.FilmAdvanceMode.String()
+	}
+
+	if fr.AFMode != nil {
+		columns[13] = fr.AFMode.String()
+	}
+
+	columns[14] = stringValue(fr.BulbExposureTime)
+	columns[15], columns[16] = w.formatTimestamp(fr.Timestamp, cameraID, w.layouts.Frame)
+	if fr.Timestamp == nil && fr.Date != nil {
+		columns[15], _ = w.formatTimestamp(fr.Date, cameraID, w.layouts.Frame)
+	}
+
+	if fr.MultipleExposure != nil {
+		columns[17] = fr.MultipleExposure.String()
+	}
+
+	columns[18], columns[19] = w.formatTimestamp(fr.BatteryLoadedDate, cameraID, w.layouts.Battery)
+	columns[20] = stringValue(fr.Remarks)
+
+	return columns
+}
+
+func (w *CSVWriter) formatTimestamp(t *time.Time, cameraID uint8, dateLayout string) (string, string) {
+	if t == nil || t.IsZero() {
+		return "", ""
+	}
+
+	ts := *t
+	if w.tzfn != nil {
+		ts = ts.In(w.tzfn(cameraID))
+	}
+
+	return ts.Format(dateLayout), ts.Format(w.timeLayout)
+}
+
+// checkColumns makes sure values don't break the layout: CSVParser splits
+// lines by comma and doesn't support quoting
+func checkColumns(columns []string) error {
+	for _, c := range columns {
+		if strings.ContainsAny(c, ",\r\n") {
+			return errors.Errorf("value `%s` contains comma or line break which is not supported by ES-E1 CSV format", c)
+		}
+	}
+	return nil
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func intValue(i *int64) string {
+	if i == nil {
+		return ""
+	}
+	return strconv.FormatInt(*i, 10)
+}
+
+// compensationValue formats exposure compensation value, ES-E1 writes zero
+// compensation as `0.0`
+func compensationValue(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	if *v == 0 {
+		return "0.0"
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
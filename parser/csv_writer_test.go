@@ -0,0 +1,123 @@
+package tagger
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+func TestCSVWriterRoundTrip(t *testing.T) {
+	r := require.New(t)
+
+	tz, err := time.LoadLocation("CET")
+	r.NoError(err)
+	tzfn := func(uint8) *time.Location { return tz }
+
+	// inputs CSVWriter can't write back
+	unsupported := map[string]string{
+		"testdata/nikon-f6.csv":    "Nikon F6 format",
+		"testdata/empty-frame.csv": "frame line without number and data is rejected by CSVParser",
+	}
+
+	fns, err := filepath.Glob("testdata/*.csv")
+	r.NoError(err)
+	r.NotEmpty(fns)
+
+	for _, fn := range fns {
+		if _, ok := unsupported[fn]; ok {
+			continue
+		}
+
+		expected, err := ioutil.ReadFile(fn)
+		r.NoErrorf(err, fn)
+
+		timestampFormat := types.TimestampFormatUS.TimeLayout()
+		if strings.HasSuffix(fn, "-eu.csv") {
+			timestampFormat = types.TimestampFormatEU.TimeLayout()
+		}
+
+		lineEnding := "\n"
+		if bytes.Contains(expected, []byte("\r\n")) {
+			lineEnding = "\r\n"
+		}
+
+		p, err := New(fn, timestampFormat, tzfn)
+		r.NoErrorf(err, fn)
+
+		films, err := p.Parse()
+		r.NoErrorf(err, fn)
+		r.NoErrorf(p.Close(), fn)
+
+		buf := &bytes.Buffer{}
+		err = NewWriter(buf, timestampFormat, tzfn).
+			SetDateLayouts(*p.DateLayouts()).
+			SetLineEnding(lineEnding).
+			Write(films)
+		r.NoErrorf(err, fn)
+		r.Equalf(string(expected), buf.String(), fn)
+	}
+}
+
+func TestCSVWriterEU(t *testing.T) {
+	r := require.New(t)
+
+	tzfn := func(uint8) *time.Location { return time.UTC }
+	films := []*types.Film{
+		{
+			ID:                  types.PtrInt64(7),
+			CameraID:            types.PtrUint8(2),
+			Title:               types.PtrString("Portra"),
+			FilmLoadedTimestamp: types.PtrTime(time.Date(2019, 9, 28, 10, 21, 32, 0, time.UTC)),
+			ISO:                 types.PtrInt64(400),
+			Frames: []*types.Frame{
+				{
+					Flag:      types.PtrBool(true),
+					Number:    types.PtrInt64(1),
+					Tv:        types.PtrString("1/40"),
+					ISO:       types.PtrInt64(400),
+					Timestamp: types.PtrTime(time.Date(2019, 10, 7, 20, 2, 18, 0, time.FixedZone("MSK", 3*3600))),
+				},
+			},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	err := NewWriter(buf, types.TimestampFormatEU.TimeLayout(), tzfn).Write(films)
+	r.NoError(err)
+	r.Equal(strings.Join([]string{
+		",Film ID,02-007,Title,Portra,Date and time film loaded,28/9/2019,10:21:32,Frame count,,ISO (DX),400",
+		",Remarks,",
+		"",
+		frameHeader,
+		`*,1,,,="1/40",,,,,,,,,,,7/10/2019,17:02:18,,,,`,
+		"",
+	}, "\r\n"), buf.String())
+}
+
+func TestCSVWriterInvalidValue(t *testing.T) {
+	r := require.New(t)
+
+	films := []*types.Film{
+		{
+			ID:       types.PtrInt64(7),
+			CameraID: types.PtrUint8(2),
+			Frames: []*types.Frame{
+				{
+					Number:  types.PtrInt64(3),
+					Remarks: types.PtrString("cloudy, windy"),
+				},
+			},
+		},
+	}
+
+	err := NewWriter(&bytes.Buffer{}, types.TimestampFormatUS.TimeLayout(), nil).Write(films)
+	r.Error(err)
+	r.Equal("film 02-007: frame 3: value `cloudy, windy` contains comma or line break which is not supported by ES-E1 CSV format", err.Error())
+}
@@ -0,0 +1,160 @@
+package tagger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// detectSize is the amount of bytes read from the file to detect the format
+const detectSize = 4096
+
+// DetectFormat detects the format of the file by its content: ES-E1 `,Film
+// ID,` header, other vendors' CSV headers, JSON and YAML documents, film log
+// tables. Formats are checked in the order of registration. If none of
+// them recognizes the content the first format registered for the file
+// extension is used.
+func DetectFormat(fn string) (*Format, error) {
+	fp, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+
+	head := make([]byte, detectSize)
+	n, err := io.ReadFull(fp, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	head = head[:n]
+
+	for i := range registry {
+		if f := &registry[i]; f.Detect != nil && f.Detect(head) {
+			return f, nil
+		}
+	}
+
+	ext := strings.ToLower(filepath.Ext(fn))
+	for i := range registry {
+		if hasExtension(&registry[i], ext) {
+			return &registry[i], nil
+		}
+	}
+
+	return nil, errors.Errorf("unable to detect format of %s, use -input-format to set it", fn)
+}
+
+func hasExtension(f *Format, ext string) bool {
+	for _, e := range f.Extensions {
+		if e == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// firstLine returns the first non-empty line of the data skipping `#`
+// comments
+func firstLine(head []byte) string {
+	for _, l := range strings.Split(string(head), "\n") {
+		l = strings.TrimSpace(strings.TrimPrefix(l, "\ufeff"))
+		if l != "" && !strings.HasPrefix(l, "#") {
+			return l
+		}
+	}
+	return ""
+}
+
+func isESE1(head []byte) bool {
+	return isFilmHeader(firstLine(head))
+}
+
+// isJSONDocument reports whether the data is JSON object with `films` key
+// as produced by -export
+func isJSONDocument(head []byte) bool {
+	delim, keys := jsonKeys(head)
+	return delim == '{' && keys["films"]
+}
+
+// isExifNotes reports whether the data is JSON list of rolls or single
+// roll object with `frames` key
+func isExifNotes(head []byte) bool {
+	delim, keys := jsonKeys(head)
+	switch delim {
+	case '[':
+		return true
+	case '{':
+		return keys["frames"] && !keys["films"]
+	}
+	return false
+}
+
+func isYAMLDocument(head []byte) bool {
+	l := firstLine(head)
+	return l == "---" || strings.HasPrefix(l, "%YAML") ||
+		strings.HasPrefix(l, "version:") || strings.HasPrefix(l, "films:")
+}
+
+func isFilmLog(head []byte) bool {
+	l := firstLine(head)
+	if i := strings.Index(l, "#"); i >= 0 {
+		l = strings.TrimSpace(l[:i])
+	}
+	return l == "[film]" || l == "[[frame]]"
+}
+
+// jsonKeys returns the opening delimiter of JSON object or list of objects
+// and the keys of the top level object (or the first object of the list).
+// Data is usually truncated so the keys found before the end of data are
+// returned.
+func jsonKeys(head []byte) (json.Delim, map[string]bool) {
+	keys := map[string]bool{}
+
+	dec := json.NewDecoder(bytes.NewReader(bytes.TrimPrefix(head, []byte("\ufeff"))))
+	tok, err := dec.Token()
+	if err != nil {
+		return 0, keys
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok || (delim != '{' && delim != '[') {
+		return 0, keys
+	}
+
+	if delim == '[' {
+		tok, err := dec.Token()
+		if err != nil {
+			return 0, keys
+		}
+		if tok == json.Delim(']') {
+			return delim, keys
+		}
+		if tok != json.Delim('{') {
+			return 0, keys
+		}
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+		key, ok := tok.(string)
+		if !ok {
+			break
+		}
+		keys[key] = true
+
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			break
+		}
+	}
+
+	return delim, keys
+}
@@ -0,0 +1,129 @@
+package tagger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+func TestDetectFormat(t *testing.T) {
+	r := require.New(t)
+
+	type testCase struct {
+		name     string
+		fn       string
+		expected types.InputFormat
+	}
+
+	tcs := []testCase{
+		{
+			name:     "ES-E1 CSV",
+			fn:       "testdata/two-films.csv",
+			expected: types.InputFormatCSV,
+		},
+		{
+			name:     "Nikon CSV",
+			fn:       "testdata/nikon-f6.csv",
+			expected: types.InputFormatNikon,
+		},
+		{
+			name:     "YAML document",
+			fn:       "testdata/two-films.yaml",
+			expected: types.InputFormatYAML,
+		},
+		{
+			name:     "JSON document",
+			fn:       "testdata/two-films.json",
+			expected: types.InputFormatJSON,
+		},
+		{
+			name:     "Exif Notes",
+			fn:       "testdata/exif-notes.json",
+			expected: types.InputFormatExifNotes,
+		},
+		{
+			name:     "film log",
+			fn:       "testdata/fm2.filmlog",
+			expected: types.InputFormatFilmLog,
+		},
+		{
+			name:     "ES-E1 CSV without the extension",
+			fn:       "testdata/detect/two-films",
+			expected: types.InputFormatCSV,
+		},
+		{
+			name:     "unrecognized content, format by extension",
+			fn:       "testdata/detect/unknown.csv",
+			expected: types.InputFormatCSV,
+		},
+	}
+
+	for _, tc := range tcs {
+		f, err := DetectFormat(tc.fn)
+		r.NoErrorf(err, tc.name)
+		r.Equalf(tc.expected, f.Name, tc.name)
+	}
+}
+
+func TestDetectFormatUnknown(t *testing.T) {
+	r := require.New(t)
+
+	_, err := DetectFormat("testdata/detect/unknown")
+	r.Error(err)
+	r.Equal("unable to detect format of testdata/detect/unknown, use -input-format to set it", err.Error())
+}
+
+func TestDetectors(t *testing.T) {
+	r := require.New(t)
+
+	type testCase struct {
+		name     string
+		head     string
+		expected types.InputFormat
+	}
+
+	tcs := []testCase{
+		{
+			name:     "ES-E1 header with byte order mark",
+			head:     "\ufeff,Film ID,Camera ID,Title\r\n",
+			expected: types.InputFormatCSV,
+		},
+		{
+			name:     "Nikon header",
+			head:     "Camera,Film No.,Frame No.,Shutter Speed,Aperture\n",
+			expected: types.InputFormatNikon,
+		},
+		{
+			name:     "JSON document truncated",
+			head:     `{"version": 1, "films": [{"id": 1, "frames": [{"num`,
+			expected: types.InputFormatJSON,
+		},
+		{
+			name:     "single Exif Notes roll",
+			head:     `{"name": "roll", "frames": [`,
+			expected: types.InputFormatExifNotes,
+		},
+		{
+			name:     "YAML document marker",
+			head:     "# exported\n---\nversion: 1\n",
+			expected: types.InputFormatYAML,
+		},
+		{
+			name:     "film log starting with frame",
+			head:     "\n[[frame]] # first one\nshutter = 30\n",
+			expected: types.InputFormatFilmLog,
+		},
+	}
+
+	for _, tc := range tcs {
+		detected := []types.InputFormat{}
+		for _, f := range Formats() {
+			if f.Detect != nil && f.Detect([]byte(tc.head)) {
+				detected = append(detected, f.Name)
+			}
+		}
+		r.Equalf([]types.InputFormat{tc.expected}, detected, tc.name)
+	}
+}
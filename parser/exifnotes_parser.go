@@ -0,0 +1,272 @@
+package tagger
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+// ExifNotesParser reads roll exports of Exif Notes Android app. Both
+// a single roll object and a list of rolls are supported.
+//
+// Exif Notes doesn't have camera IDs so the films are assigned to camera
+// ID 0, film ID is the roll ID if it's exported or the roll date as
+// YYYYMMDDhhmmss number otherwise, rolls having neither are rejected.
+// The IDs are stable across exports, though rolls of the same ID (e.g.
+// exported from different devices or loaded at the same second) collide
+// when merged. Timestamps are in the timezone of camera 0.
+type ExifNotesParser struct {
+	rc   io.ReadCloser
+	tzfn func(uint8) *time.Location
+}
+
+type exifNotesRoll struct {
+	ID        *int64           `json:"id"`
+	Name      *string          `json:"name"`
+	Date      *string          `json:"date"`
+	Note      *string          `json:"note"`
+	Camera    *exifNotesGear   `json:"camera"`
+	ISO       *int64           `json:"iso"`
+	PushPull  *string          `json:"pushPull"`
+	FilmStock *exifNotesStock  `json:"filmStock"`
+	Frames    []exifNotesFrame `json:"frames"`
+}
+
+type exifNotesGear struct {
+	Make         string `json:"make"`
+	Model        string `json:"model"`
+	SerialNumber string `json:"serialNumber"`
+}
+
+type exifNotesStock struct {
+	Make  string `json:"make"`
+	Model string `json:"model"`
+	ISO   int64  `json:"iso"`
+}
+
+type exifNotesFrame struct {
+	Count         *int64             `json:"count"`
+	Date          *string            `json:"date"`
+	Shutter       *string            `json:"shutter"`
+	Aperture      *string            `json:"aperture"`
+	FocalLength   *int64             `json:"focalLength"`
+	ExposureComp  *string            `json:"exposureComp"`
+	NoOfExposures *int64             `json:"noOfExposures"`
+	FlashUsed     *bool              `json:"flashUsed"`
+	Lens          *exifNotesGear     `json:"lens"`
+	Location      *exifNotesLocation `json:"location"`
+	Note          *string            `json:"note"`
+}
+
+type exifNotesLocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// NewExifNotes creates new ExifNotesParser object
+func NewExifNotes(fn string, tzfn func(uint8) *time.Location) (*ExifNotesParser, error) {
+	fp, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExifNotesParser{
+		rc:   fp,
+		tzfn: tzfn,
+	}, nil
+}
+
+// Close ...
+func (p *ExifNotesParser) Close() error {
+	return p.rc.Close()
+}
+
+// Parse ...
+func (p *ExifNotesParser) Parse() ([]*types.Film, error) {
+	data, err := ioutil.ReadAll(p.rc)
+	if err != nil {
+		return nil, err
+	}
+
+	rolls := []exifNotesRoll{}
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "{") {
+		var roll exifNotesRoll
+		err = json.Unmarshal(data, &roll)
+		rolls = append(rolls, roll)
+	} else {
+		err = json.Unmarshal(data, &rolls)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "error decoding Exif Notes export")
+	}
+
+	loc := time.UTC
+	if p.tzfn != nil {
+		loc = p.tzfn(0)
+	}
+
+	films := make([]*types.Film, len(rolls))
+	for i, roll := range rolls {
+		f, err := roll.film(loc)
+		if err != nil {
+			return nil, errors.Wrapf(err, "roll %d", i+1)
+		}
+		films[i] = f
+	}
+
+	return films, nil
+}
+
+func (r exifNotesRoll) film(loc *time.Location) (*types.Film, error) {
+	f := &types.Film{
+		ID:       r.ID,
+		CameraID: types.PtrUint8(0),
+		Title:    nonEmpty(r.Name),
+		ISO:      r.ISO,
+		Remarks:  nonEmpty(r.Note),
+	}
+
+	if r.Camera != nil {
+		f.Make = nonEmpty(&r.Camera.Make)
+		f.Model = nonEmpty(&r.Camera.Model)
+	}
+
+	if r.FilmStock != nil {
+		f.Stock = &types.FilmStock{
+			Manufacturer: r.FilmStock.Make,
+			Name:         r.FilmStock.Model,
+			BoxSpeed:     r.FilmStock.ISO,
+		}
+	}
+
+	if r.PushPull != nil && strings.TrimSpace(*r.PushPull) != "" {
+		v, err := types.ParseStops(*r.PushPull)
+		if err != nil {
+			return nil, errors.Wrap(err, "error parsing push/pull value")
+		}
+		f.PushPull = &v
+	}
+
+	var err error
+	if f.FilmLoadedTimestamp, err = parseExifNotesDate(r.Date, loc); err != nil {
+		return nil, errors.Wrap(err, "error parsing roll date")
+	}
+
+	if f.ID == nil {
+		if f.FilmLoadedTimestamp == nil {
+			return nil, errors.New("roll has neither `id` nor `date` to make film ID of")
+		}
+		id, err := strconv.ParseInt(f.FilmLoadedTimestamp.Format("20060102150405"), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		f.ID = &id
+	}
+
+	for i, fr := range r.Frames {
+		frame, err := fr.frame(int64(i+1), loc)
+		if err != nil {
+			return nil, errors.Wrapf(err, "frame %d", i+1)
+		}
+		f.Frames = append(f.Frames, frame)
+	}
+
+	return f, nil
+}
+
+func (fr exifNotesFrame) frame(idx int64, loc *time.Location) (*types.Frame, error) {
+	f := &types.Frame{
+		Number:      types.PtrInt64(idx),
+		FocalLength: fr.FocalLength,
+		Tv:          nonEmpty(fr.Shutter),
+		Remarks:     nonEmpty(fr.Note),
+	}
+
+	if fr.Count != nil {
+		f.Number = fr.Count
+	}
+
+	var err error
+	if f.Timestamp, err = parseExifNotesDate(fr.Date, loc); err != nil {
+		return nil, errors.Wrap(err, "error parsing frame date")
+	}
+
+	if fr.Aperture != nil && strings.TrimSpace(*fr.Aperture) != "" {
+		v, err := strconv.ParseFloat(strings.TrimPrefix(strings.TrimSpace(*fr.Aperture), "f/"), 64)
+		if err != nil {
+			return nil, errors.Errorf("invalid aperture value `%s`", *fr.Aperture)
+		}
+		av := types.Aperture(v)
+		f.Av = &av
+	}
+
+	if fr.ExposureComp != nil && strings.TrimSpace(*fr.ExposureComp) != "" {
+		v, err := types.ParseStops(*fr.ExposureComp)
+		if err != nil {
+			return nil, errors.Wrap(err, "error parsing exposure compensation")
+		}
+		f.ExposureCompensation = &v
+	}
+
+	if fr.NoOfExposures != nil {
+		me := types.MultipleExposureOff
+		if *fr.NoOfExposures > 1 {
+			me = types.MultipleExposureOn
+		}
+		f.MultipleExposure = &me
+	}
+
+	if fr.FlashUsed != nil {
+		fm := types.FlashModeOff
+		if *fr.FlashUsed {
+			fm = types.FlashModeOn
+		}
+		f.FlashMode = &fm
+	}
+
+	if fr.Lens != nil {
+		name := strings.TrimSpace(fr.Lens.Model)
+		if fr.Lens.Make != "" && !strings.HasPrefix(name, fr.Lens.Make) {
+			name = fr.Lens.Make + " " + name
+		}
+		f.Lens = nonEmpty(&name)
+	}
+
+	if fr.Location != nil {
+		f.Position = &types.Position{
+			Latitude:  fr.Location.Latitude,
+			Longitude: fr.Location.Longitude,
+		}
+	}
+
+	return f, nil
+}
+
+func parseExifNotesDate(s *string, loc *time.Location) (*time.Time, error) {
+	if s == nil || strings.TrimSpace(*s) == "" {
+		return nil, nil
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, strings.TrimSpace(*s), loc); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, errors.Errorf("invalid date `%s`", *s)
+}
+
+func nonEmpty(s *string) *string {
+	if s == nil || strings.TrimSpace(*s) == "" {
+		return nil
+	}
+	v := strings.TrimSpace(*s)
+	return &v
+}
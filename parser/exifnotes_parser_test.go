@@ -0,0 +1,116 @@
+package tagger
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+func TestExifNotesParser(t *testing.T) {
+	r := require.New(t)
+
+	tz, err := time.LoadLocation("Europe/Helsinki")
+	r.NoError(err)
+
+	p, err := NewExifNotes("testdata/exif-notes.json", func(cameraID uint8) *time.Location {
+		r.Equal(uint8(0), cameraID)
+		return tz
+	})
+	r.NoError(err)
+	defer func() {
+		r.NoError(p.Close())
+	}()
+
+	films, err := p.Parse()
+	r.NoError(err)
+
+	stops := func(s string) *float64 {
+		v, err := types.ParseStops(s)
+		r.NoError(err)
+		return &v
+	}
+
+	multipleExposureOn := types.MultipleExposureOn
+	multipleExposureOff := types.MultipleExposureOff
+
+	r.Equal([]*types.Film{
+		{
+			ID:                  types.PtrInt64(7),
+			CameraID:            types.PtrUint8(0),
+			Title:               types.PtrString("Helsinki walk"),
+			Make:                types.PtrString("Canon"),
+			Model:               types.PtrString("A-1"),
+			FilmLoadedTimestamp: types.PtrTime(time.Date(2023, 4, 15, 9, 30, 0, 0, tz)),
+			ISO:                 types.PtrInt64(800),
+			Remarks:             types.PtrString("pushed one stop"),
+			Stock: &types.FilmStock{
+				Manufacturer: "Kodak",
+				Name:         "Tri-X 400",
+				BoxSpeed:     400,
+			},
+			PushPull: types.PtrFloat64(1),
+			Frames: []*types.Frame{
+				{
+					Number:               types.PtrInt64(1),
+					Timestamp:            types.PtrTime(time.Date(2023, 4, 15, 9, 42, 10, 0, tz)),
+					Tv:                   types.PtrString("1/125"),
+					Av:                   types.PtrAperture(8),
+					FocalLength:          types.PtrInt64(50),
+					ExposureCompensation: types.PtrFloat64(0),
+					MultipleExposure:     &multipleExposureOff,
+					FlashMode:            types.PtrFlashMode(types.FlashModeOff),
+					Lens:                 types.PtrString("Canon FD 50mm f/1.4"),
+					Position: &types.Position{
+						Latitude:  60.169857,
+						Longitude: 24.938379,
+					},
+					Remarks: types.PtrString("cathedral"),
+				},
+				{
+					Number:               types.PtrInt64(2),
+					Timestamp:            types.PtrTime(time.Date(2023, 4, 15, 9, 50, 0, 0, tz)),
+					Tv:                   types.PtrString("1/60"),
+					Av:                   types.PtrAperture(5.6),
+					FocalLength:          types.PtrInt64(50),
+					ExposureCompensation: stops("-1 2/3"),
+					MultipleExposure:     &multipleExposureOn,
+					FlashMode:            types.PtrFlashMode(types.FlashModeOn),
+					Lens:                 types.PtrString("Canon FD 50mm f/1.4"),
+				},
+				{
+					Number: types.PtrInt64(3),
+					Tv:     types.PtrString(`2"`),
+					Av:     types.PtrAperture(16),
+				},
+			},
+		},
+	}, films)
+}
+
+func TestExifNotesParserSingleRoll(t *testing.T) {
+	r := require.New(t)
+
+	p := &ExifNotesParser{rc: ioutil.NopCloser(strings.NewReader(`{"name": "roll", "date": "2023-04-15T09:30:00", "frames": [{"shutter": "1/30"}]}`))}
+	films, err := p.Parse()
+	r.NoError(err)
+	r.Len(films, 1)
+	r.Equal(int64(20230415093000), *films[0].ID)
+	r.Equal("00-20230415093000", films[0].FullID())
+	r.Equal("1/30", *films[0].Frames[0].Tv)
+	r.Equal(int64(1), *films[0].Frames[0].Number)
+
+	p = &ExifNotesParser{rc: ioutil.NopCloser(strings.NewReader(`{"name": "roll", "frames": [{"shutter": "1/30"}]}`))}
+	_, err = p.Parse()
+	r.Error(err)
+	r.Equal("roll 1: roll has neither `id` nor `date` to make film ID of", err.Error())
+
+	p = &ExifNotesParser{rc: ioutil.NopCloser(strings.NewReader(`{"id": 3, "frames": [{"aperture": "wide"}]}`))}
+	_, err = p.Parse()
+	r.Error(err)
+	r.Equal("roll 1: frame 1: invalid aperture value `wide`", err.Error())
+}
@@ -0,0 +1,563 @@
+package tagger
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+// FilmLogParser reads hand-written film logs for cameras without data
+// memory. Film log is a line based format of its own, usually stored with
+// `.filmlog` extension. It looks like TOML but it's not: dates and times are
+// written unquoted in any of the layouts below, `[film]` section is repeated
+// for every film and only the keys listed below are known.
+//
+//	# Nikon FM2, roll #12
+//	[film]
+//	id = 12
+//	camera_id = 20
+//	make = "Nikon"
+//	model = "FM2"
+//	title = "Kodak Portra 400"
+//	loaded = 2020-05-01 10:00
+//	iso = 400
+//	lens = "Nikkor 50mm f/1.4"
+//
+//	[[frame]]
+//	shutter = "1/125"
+//	aperture = 5.6
+//	focal_length = 50
+//	time = 10:05
+//	notes = "harbour"
+//
+//	[[frame]]
+//	same = true   # shutter, aperture, focal length and lens of the previous frame
+//	shutter = "1/250"
+//
+// Every line is either `[film]` or `[[frame]]` section header or
+// `key = value` pair of the section, `#` starts a comment. Values are
+// double or single quoted strings, integer or decimal numbers, `true` or
+// `false` and unquoted dates and times: `2006-01-02 15:04[:05]`, RFC3339 or
+// `15:04[:05]`.
+//
+// Film keys are id (required), camera_id, title, make, model, loaded, iso,
+// frame_count, remarks, lens and location. Frame keys are number, same,
+// shutter, aperture, focal_length, lens, iso, exposure_compensation, flag,
+// time and notes.
+//
+// Several films could be listed in a single file, frames belong to the
+// film listed above them. Frames are numbered sequentially unless `number`
+// is set. Timestamps without timezone offset are in the camera timezone,
+// time without date is on the date of the previous frame or film load.
+type FilmLogParser struct {
+	rc   io.ReadCloser
+	tzfn func(uint8) *time.Location
+}
+
+// LineError is an error in the film log pointing to the line
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+// NewFilmLog creates new FilmLogParser object
+func NewFilmLog(fn string, tzfn func(uint8) *time.Location) (*FilmLogParser, error) {
+	fp, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FilmLogParser{
+		rc:   fp,
+		tzfn: tzfn,
+	}, nil
+}
+
+// Close ...
+func (p *FilmLogParser) Close() error {
+	return p.rc.Close()
+}
+
+type logTable struct {
+	name    string
+	line    int
+	entries []logEntry
+}
+
+type logEntry struct {
+	key   string
+	value interface{}
+	line  int
+}
+
+// logDatetime is unquoted date and/or time value
+type logDatetime string
+
+var (
+	filmLogKeys = map[string]struct{}{
+		"id": {}, "camera_id": {}, "title": {}, "make": {}, "model": {}, "loaded": {},
+		"iso": {}, "frame_count": {}, "remarks": {}, "lens": {}, "location": {},
+	}
+	frameLogKeys = map[string]struct{}{
+		"number": {}, "same": {}, "shutter": {}, "aperture": {}, "focal_length": {}, "lens": {},
+		"iso": {}, "exposure_compensation": {}, "flag": {}, "time": {}, "notes": {},
+	}
+)
+
+// Parse ...
+func (p *FilmLogParser) Parse() ([]*types.Film, error) {
+	tables, err := readTables(p.rc)
+	if err != nil {
+		return nil, err
+	}
+
+	films := []*types.Film{}
+	var (
+		film *types.Film
+		lens *string
+		prev *types.Frame
+	)
+	for _, t := range tables {
+		switch t.name {
+		case "film":
+			film, lens, err = p.parseFilm(t)
+			if err != nil {
+				return nil, err
+			}
+			films = append(films, film)
+			prev = nil
+		case "frame":
+			if film == nil {
+				return nil, &LineError{Line: t.line, Err: errors.New("frame is listed before any film")}
+			}
+
+			fr, err := p.parseFrame(t, film, lens, prev)
+			if err != nil {
+				return nil, err
+			}
+			film.Frames = append(film.Frames, fr)
+			prev = fr
+		}
+	}
+
+	return films, nil
+}
+
+// parseFilm returns the film and the default lens of its frames
+func (p *FilmLogParser) parseFilm(t logTable) (*types.Film, *string, error) {
+	film := &types.Film{
+		CameraID: types.PtrUint8(0),
+	}
+	var lens *string
+
+	// camera ID is required to look up the timezone so it's handled first
+	for _, e := range t.entries {
+		if e.key != "camera_id" {
+			continue
+		}
+		v, err := intEntry(e)
+		if err != nil {
+			return nil, nil, err
+		}
+		if v < 0 || v > 255 {
+			return nil, nil, &LineError{Line: e.line, Err: errors.Errorf("camera ID %d is out of range 0-255", v)}
+		}
+		film.CameraID = types.PtrUint8(uint8(v))
+	}
+
+	for _, e := range t.entries {
+		var err error
+		switch e.key {
+		case "id":
+			var v int64
+			v, err = intEntry(e)
+			film.ID = &v
+		case "title":
+			film.Title, err = stringEntry(e)
+		case "make":
+			film.Make, err = stringEntry(e)
+		case "model":
+			film.Model, err = stringEntry(e)
+		case "loaded":
+			film.FilmLoadedTimestamp, err = p.timeEntry(e, *film.CameraID, nil)
+		case "iso":
+			var v int64
+			v, err = positiveIntEntry(e)
+			film.ISO = &v
+		case "frame_count":
+			var v int64
+			v, err = positiveIntEntry(e)
+			film.FrameCount = &v
+		case "remarks":
+			film.Remarks, err = stringEntry(e)
+		case "lens":
+			lens, err = stringEntry(e)
+		case "location":
+			film.Location, err = stringEntry(e)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if film.ID == nil {
+		return nil, nil, &LineError{Line: t.line, Err: errors.New("film `id` is required")}
+	}
+
+	return film, lens, nil
+}
+
+func (p *FilmLogParser) parseFrame(t logTable, film *types.Film, lens *string, prev *types.Frame) (*types.Frame, error) {
+	fr := &types.Frame{
+		Flag: types.PtrBool(false),
+		ISO:  film.ISO,
+		Lens: lens,
+	}
+
+	same := false
+	for _, e := range t.entries {
+		if e.key != "same" {
+			continue
+		}
+		v, ok := e.value.(bool)
+		if !ok {
+			return nil, typeError(e, "boolean")
+		}
+		same = v
+	}
+
+	if same {
+		if prev == nil {
+			return nil, &LineError{Line: t.line, Err: errors.New("`same` is set on the first frame of the film")}
+		}
+		fr.Tv = prev.Tv
+		fr.Av = prev.Av
+		fr.FocalLength = prev.FocalLength
+		fr.Lens = prev.Lens
+		fr.ISO = prev.ISO
+		fr.ExposureCompensation = prev.ExposureCompensation
+	}
+
+	fr.Number = types.PtrInt64(1)
+	if prev != nil {
+		fr.Number = types.PtrInt64(*prev.Number + 1)
+	}
+
+	var prevTimestamp *time.Time
+	if prev != nil && prev.Timestamp != nil {
+		prevTimestamp = prev.Timestamp
+	} else {
+		prevTimestamp = film.FilmLoadedTimestamp
+	}
+
+	for _, e := range t.entries {
+		var err error
+		switch e.key {
+		case "number":
+			var v int64
+			v, err = positiveIntEntry(e)
+			if err == nil && prev != nil && v <= *prev.Number {
+				err = &LineError{Line: e.line, Err: errors.Errorf("frame number %d is not greater than previous frame number %d", v, *prev.Number)}
+			}
+			fr.Number = &v
+		case "shutter":
+			fr.Tv, err = shutterEntry(e)
+		case "aperture":
+			var v float64
+			v, err = positiveFloatEntry(e)
+			av := types.Aperture(v)
+			fr.Av = &av
+		case "focal_length":
+			var v int64
+			v, err = positiveIntEntry(e)
+			fr.FocalLength = &v
+		case "lens":
+			fr.Lens, err = stringEntry(e)
+		case "iso":
+			var v int64
+			v, err = positiveIntEntry(e)
+			fr.ISO = &v
+		case "exposure_compensation":
+			var v float64
+			v, err = floatEntry(e)
+			fr.ExposureCompensation = &v
+		case "flag":
+			v, ok := e.value.(bool)
+			if !ok {
+				err = typeError(e, "boolean")
+			}
+			fr.Flag = &v
+		case "time":
+			fr.Timestamp, err = p.timeEntry(e, *film.CameraID, prevTimestamp)
+		case "notes":
+			fr.Remarks, err = stringEntry(e)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return fr, nil
+}
+
+var (
+	dateLayouts = []string{
+		time.RFC3339,
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+		"2006-01-02T15:04",
+		"2006-01-02 15:04",
+		"2006-01-02",
+	}
+	timeLayouts = []string{
+		"15:04:05",
+		"15:04",
+	}
+)
+
+func (p *FilmLogParser) timeEntry(e logEntry, cameraID uint8, date *time.Time) (*time.Time, error) {
+	var s string
+	switch v := e.value.(type) {
+	case logDatetime:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return nil, typeError(e, "date and time")
+	}
+
+	loc := time.UTC
+	if p.tzfn != nil {
+		loc = p.tzfn(cameraID)
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return &t, nil
+		}
+	}
+
+	for _, layout := range timeLayouts {
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			continue
+		}
+		if date == nil {
+			return nil, &LineError{Line: e.line, Err: errors.Errorf("time `%s` has no date and there's no film `loaded` date or previous frame time to take it from", s)}
+		}
+		d := date.In(loc)
+		ts := time.Date(d.Year(), d.Month(), d.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc)
+		return &ts, nil
+	}
+
+	return nil, &LineError{Line: e.line, Err: errors.Errorf("invalid date and time `%s`, expected `2006-01-02 15:04[:05]`, RFC3339 or `15:04[:05]`", s)}
+}
+
+func typeError(e logEntry, expected string) error {
+	return &LineError{Line: e.line, Err: errors.Errorf("%s value expected for `%s`", expected, e.key)}
+}
+
+func stringEntry(e logEntry) (*string, error) {
+	v, ok := e.value.(string)
+	if !ok {
+		return nil, typeError(e, "string")
+	}
+	return &v, nil
+}
+
+func shutterEntry(e logEntry) (*string, error) {
+	var s string
+	switch v := e.value.(type) {
+	case string:
+		s = strings.TrimSpace(v)
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	default:
+		return nil, typeError(e, "string")
+	}
+
+	if !isShutterSpeed(s) {
+		return nil, &LineError{Line: e.line, Err: errors.Errorf("invalid shutter speed `%s`, expected `1/125`, `2` or `2\"`", s)}
+	}
+	return &s, nil
+}
+
+func isShutterSpeed(s string) bool {
+	s = strings.TrimSuffix(s, `"`)
+	s = strings.TrimPrefix(s, "1/")
+	if s == "" {
+		return false
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	return err == nil && v > 0
+}
+
+func intEntry(e logEntry) (int64, error) {
+	v, ok := e.value.(int64)
+	if !ok {
+		return 0, typeError(e, "integer")
+	}
+	return v, nil
+}
+
+func positiveIntEntry(e logEntry) (int64, error) {
+	v, err := intEntry(e)
+	if err != nil {
+		return 0, err
+	}
+	if v <= 0 {
+		return 0, &LineError{Line: e.line, Err: errors.Errorf("positive value expected for `%s`, got %d", e.key, v)}
+	}
+	return v, nil
+}
+
+func floatEntry(e logEntry) (float64, error) {
+	switch v := e.value.(type) {
+	case float64:
+		return v, nil
+	case int64:
+		return float64(v), nil
+	}
+	return 0, typeError(e, "number")
+}
+
+func positiveFloatEntry(e logEntry) (float64, error) {
+	v, err := floatEntry(e)
+	if err != nil {
+		return 0, err
+	}
+	if v <= 0 {
+		return 0, &LineError{Line: e.line, Err: errors.Errorf("positive value expected for `%s`, got %v", e.key, v)}
+	}
+	return v, nil
+}
+
+// readTables reads film log into the list of tables validating the syntax
+// and the keys
+func readTables(r io.Reader) ([]logTable, error) {
+	tables := []logTable{}
+
+	sc := bufio.NewScanner(r)
+	line := 0
+	for sc.Scan() {
+		line++
+		s := strings.TrimSpace(stripComment(sc.Text()))
+		if s == "" {
+			continue
+		}
+
+		switch s {
+		case "[film]":
+			tables = append(tables, logTable{name: "film", line: line})
+			continue
+		case "[[frame]]":
+			tables = append(tables, logTable{name: "frame", line: line})
+			continue
+		}
+
+		if strings.HasPrefix(s, "[") {
+			return nil, &LineError{Line: line, Err: errors.Errorf("unknown section `%s`, `[film]` or `[[frame]]` expected", s)}
+		}
+
+		if len(tables) == 0 {
+			return nil, &LineError{Line: line, Err: errors.New("value is set outside of `[film]` or `[[frame]]` section")}
+		}
+		t := &tables[len(tables)-1]
+
+		idx := strings.IndexByte(s, '=')
+		if idx < 0 {
+			return nil, &LineError{Line: line, Err: errors.Errorf("`key = value` expected, got `%s`", s)}
+		}
+
+		key := strings.TrimSpace(s[:idx])
+		keys := filmLogKeys
+		if t.name == "frame" {
+			keys = frameLogKeys
+		}
+		if _, ok := keys[key]; !ok {
+			return nil, &LineError{Line: line, Err: errors.Errorf("unknown %s key `%s`", t.name, key)}
+		}
+		for _, e := range t.entries {
+			if e.key == key {
+				return nil, &LineError{Line: line, Err: errors.Errorf("`%s` is already set on line %d", key, e.line)}
+			}
+		}
+
+		value, err := parseLogValue(strings.TrimSpace(s[idx+1:]))
+		if err != nil {
+			return nil, &LineError{Line: line, Err: errors.Wrapf(err, "invalid value of `%s`", key)}
+		}
+
+		t.entries = append(t.entries, logEntry{key: key, value: value, line: line})
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+// stripComment removes `#` comment which is not within a string
+func stripComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0 && c == '\\' && quote == '"':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '"' || c == '\''):
+			quote = c
+		case quote == 0 && c == '#':
+			return s[:i]
+		}
+	}
+	return s
+}
+
+func parseLogValue(s string) (interface{}, error) {
+	switch {
+	case s == "":
+		return nil, errors.New("empty value")
+	case s == "true":
+		return true, nil
+	case s == "false":
+		return false, nil
+	case strings.HasPrefix(s, `"`):
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return nil, errors.Errorf("malformed string %s", s)
+		}
+		return v, nil
+	case strings.HasPrefix(s, "'"):
+		if len(s) < 2 || !strings.HasSuffix(s, "'") || strings.Contains(s[1:len(s)-1], "'") {
+			return nil, errors.Errorf("malformed string %s", s)
+		}
+		return s[1 : len(s)-1], nil
+	}
+
+	if v, err := strconv.ParseInt(strings.Replace(s, "_", "", -1), 10, 64); err == nil {
+		return v, nil
+	}
+	if v, err := strconv.ParseFloat(strings.Replace(s, "_", "", -1), 64); err == nil {
+		return v, nil
+	}
+	if s[0] >= '0' && s[0] <= '9' && strings.ContainsAny(s, "-:") {
+		return logDatetime(s), nil
+	}
+
+	return nil, errors.Errorf("unsupported value `%s`, strings have to be quoted", s)
+}
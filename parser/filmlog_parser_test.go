@@ -0,0 +1,167 @@
+package tagger
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+func TestFilmLogParser(t *testing.T) {
+	r := require.New(t)
+
+	tz, err := time.LoadLocation("Europe/Moscow")
+	r.NoError(err)
+
+	p, err := NewFilmLog("testdata/fm2.filmlog", func(cameraID uint8) *time.Location {
+		r.Equal(uint8(20), cameraID)
+		return tz
+	})
+	r.NoError(err)
+	defer func() {
+		r.NoError(p.Close())
+	}()
+
+	films, err := p.Parse()
+	r.NoError(err)
+	r.Equal([]*types.Film{
+		{
+			ID:                  types.PtrInt64(12),
+			CameraID:            types.PtrUint8(20),
+			Make:                types.PtrString("Nikon"),
+			Model:               types.PtrString("FM2"),
+			Title:               types.PtrString("Kodak Portra 400 # roll 12"),
+			FilmLoadedTimestamp: types.PtrTime(time.Date(2020, 5, 1, 10, 0, 0, 0, tz)),
+			ISO:                 types.PtrInt64(400),
+			Frames: []*types.Frame{
+				{
+					Flag:        types.PtrBool(false),
+					Number:      types.PtrInt64(1),
+					Tv:          types.PtrString("1/125"),
+					Av:          types.PtrAperture(5.6),
+					FocalLength: types.PtrInt64(50),
+					Lens:        types.PtrString("Nikkor 50mm f/1.4"),
+					ISO:         types.PtrInt64(400),
+					Timestamp:   types.PtrTime(time.Date(2020, 5, 1, 10, 5, 0, 0, tz)),
+					Remarks:     types.PtrString("harbour"),
+				},
+				{
+					Flag:        types.PtrBool(false),
+					Number:      types.PtrInt64(2),
+					Tv:          types.PtrString("1/250"),
+					Av:          types.PtrAperture(5.6),
+					FocalLength: types.PtrInt64(50),
+					Lens:        types.PtrString("Nikkor 50mm f/1.4"),
+					ISO:         types.PtrInt64(400),
+					Timestamp:   types.PtrTime(time.Date(2020, 5, 1, 10, 7, 30, 0, tz)),
+				},
+				{
+					Flag:        types.PtrBool(true),
+					Number:      types.PtrInt64(5),
+					Tv:          types.PtrString(`2"`),
+					Av:          types.PtrAperture(16),
+					FocalLength: types.PtrInt64(28),
+					Lens:        types.PtrString("Nikkor 28mm f/2.8"),
+					ISO:         types.PtrInt64(400),
+					Timestamp:   types.PtrTime(time.Date(2020, 5, 2, 21, 30, 0, 0, time.FixedZone("", 2*3600))),
+				},
+			},
+		},
+		{
+			ID:       types.PtrInt64(13),
+			CameraID: types.PtrUint8(20),
+			Frames: []*types.Frame{
+				{
+					Flag:   types.PtrBool(false),
+					Number: types.PtrInt64(1),
+					Tv:     types.PtrString("30"),
+					Av:     types.PtrAperture(8),
+				},
+			},
+		},
+	}, films)
+}
+
+func TestFilmLogParserErrors(t *testing.T) {
+	r := require.New(t)
+
+	type testCase struct {
+		name     string
+		input    string
+		expError string
+	}
+
+	tcs := []testCase{
+		{
+			name:     "frame before film",
+			input:    "[[frame]]\nshutter = \"1/60\"\n",
+			expError: "line 1: frame is listed before any film",
+		},
+		{
+			name:     "film without ID",
+			input:    "# roll\n\n[film]\ntitle = \"test\"\n",
+			expError: "line 3: film `id` is required",
+		},
+		{
+			name:     "unknown key",
+			input:    "[film]\nid = 1\n[[frame]]\nspeed = \"1/60\"\n",
+			expError: "line 4: unknown frame key `speed`",
+		},
+		{
+			name:     "unknown section",
+			input:    "[film]\nid = 1\n[frames]\n",
+			expError: "line 3: unknown section `[frames]`, `[film]` or `[[frame]]` expected",
+		},
+		{
+			name:     "duplicate key",
+			input:    "[film]\nid = 1\n[[frame]]\naperture = 2\naperture = 4\n",
+			expError: "line 5: `aperture` is already set on line 4",
+		},
+		{
+			name:     "unquoted string",
+			input:    "[film]\nid = 1\ntitle = Portra\n",
+			expError: "line 3: invalid value of `title`: unsupported value `Portra`, strings have to be quoted",
+		},
+		{
+			name:     "invalid shutter speed",
+			input:    "[film]\nid = 1\n[[frame]]\n\nshutter = \"fast\"\n",
+			expError: "line 5: invalid shutter speed `fast`, expected `1/125`, `2` or `2\"`",
+		},
+		{
+			name:     "wrong value type",
+			input:    "[film]\nid = 1\n[[frame]]\naperture = \"f/8\"\n",
+			expError: "line 4: number value expected for `aperture`",
+		},
+		{
+			name:     "same on the first frame",
+			input:    "[film]\nid = 1\n[[frame]]\nsame = true\n",
+			expError: "line 3: `same` is set on the first frame of the film",
+		},
+		{
+			name:     "frame numbers out of order",
+			input:    "[film]\nid = 1\n[[frame]]\nnumber = 3\n[[frame]]\nnumber = 2\n",
+			expError: "line 6: frame number 2 is not greater than previous frame number 3",
+		},
+		{
+			name:     "time without date",
+			input:    "[film]\nid = 1\n[[frame]]\ntime = 10:05\n",
+			expError: "line 4: time `10:05` has no date and there's no film `loaded` date or previous frame time to take it from",
+		},
+		{
+			name:     "camera ID out of range",
+			input:    "[film]\nid = 1\ncamera_id = 300\n",
+			expError: "line 3: camera ID 300 is out of range 0-255",
+		},
+	}
+
+	for _, tc := range tcs {
+		p := &FilmLogParser{rc: ioutil.NopCloser(strings.NewReader(tc.input))}
+		_, err := p.Parse()
+		r.Errorf(err, tc.name)
+		r.Equalf(tc.expError, err.Error(), tc.name)
+	}
+}
@@ -0,0 +1,333 @@
+package tagger
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+// NikonParser reads per-frame CSV exports of Nikon data memory transfer
+// tools (F6, F100, F5 with MV-1/MC-33). Columns are mapped by header name
+// so the column order and the set of exported columns may vary, unknown
+// columns are skipped. Frames are grouped into films by camera ID (if
+// exported, 0 otherwise) and film number.
+type NikonParser struct {
+	rc   io.ReadCloser
+	tzfn func(uint8) *time.Location
+}
+
+type nikonColumn int
+
+const (
+	nikonCamera nikonColumn = iota
+	nikonCameraID
+	nikonFilm
+	nikonFrame
+	nikonShutter
+	nikonAperture
+	nikonFocalLength
+	nikonExposureMode
+	nikonMetering
+	nikonExposureComp
+	nikonFlashComp
+	nikonFlashMode
+	nikonISO
+	nikonDate
+	nikonTime
+	nikonLens
+	nikonComment
+)
+
+var nikonHeaders = map[string]nikonColumn{
+	"camera":                nikonCamera,
+	"camera model":          nikonCamera,
+	"camera id":             nikonCameraID,
+	"film no.":              nikonFilm,
+	"film no":               nikonFilm,
+	"film number":           nikonFilm,
+	"frame no.":             nikonFrame,
+	"frame no":              nikonFrame,
+	"frame number":          nikonFrame,
+	"shutter speed":         nikonShutter,
+	"shutter":               nikonShutter,
+	"aperture":              nikonAperture,
+	"f-number":              nikonAperture,
+	"focal length":          nikonFocalLength,
+	"exposure mode":         nikonExposureMode,
+	"metering system":       nikonMetering,
+	"metering mode":         nikonMetering,
+	"exposure comp.":        nikonExposureComp,
+	"exposure compensation": nikonExposureComp,
+	"flash comp.":           nikonFlashComp,
+	"flash compensation":    nikonFlashComp,
+	"flash mode":            nikonFlashMode,
+	"flash sync mode":       nikonFlashMode,
+	"iso":                   nikonISO,
+	"iso sensitivity":       nikonISO,
+	"date":                  nikonDate,
+	"time":                  nikonTime,
+	"lens":                  nikonLens,
+	"comment":               nikonComment,
+	"memo":                  nikonComment,
+}
+
+var (
+	nikonExposureModes = map[string]types.ShootingMode{
+		"program":           types.ShootingModeProgramAE,
+		"programmed auto":   types.ShootingModeProgramAE,
+		"p":                 types.ShootingModeProgramAE,
+		"aperture priority": types.ShootingModeAperturePriorityAE,
+		"a":                 types.ShootingModeAperturePriorityAE,
+		"shutter priority":  types.ShootingModeShutterSpeedPriorityAE,
+		"s":                 types.ShootingModeShutterSpeedPriorityAE,
+		"manual":            types.ShootingModeManualExposure,
+		"m":                 types.ShootingModeManualExposure,
+	}
+	nikonMeteringModes = map[string]types.MeteringMode{
+		"matrix":          types.MeteringModeEvaluative,
+		"3d matrix":       types.MeteringModeEvaluative,
+		"center-weighted": types.MeteringModeCenterAveraging,
+		"centre-weighted": types.MeteringModeCenterAveraging,
+		"spot":            types.MeteringModeSpot,
+	}
+	nikonDateLayouts = []string{
+		"2006/01/02",
+		"2006-01-02",
+		"2006.01.02",
+	}
+)
+
+// NewNikon creates new NikonParser object
+func NewNikon(fn string, tzfn func(uint8) *time.Location) (*NikonParser, error) {
+	fp, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NikonParser{
+		rc:   fp,
+		tzfn: tzfn,
+	}, nil
+}
+
+// Close ...
+func (p *NikonParser) Close() error {
+	return p.rc.Close()
+}
+
+// Parse ...
+func (p *NikonParser) Parse() ([]*types.Film, error) {
+	rd := csv.NewReader(p.rc)
+	rd.FieldsPerRecord = -1
+	rd.TrimLeadingSpace = true
+
+	header, err := rd.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, errors.New("no header line found")
+		}
+		return nil, err
+	}
+
+	columns := map[nikonColumn]int{}
+	for i, h := range header {
+		name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		if c, ok := nikonHeaders[name]; ok {
+			columns[c] = i
+		}
+	}
+
+	for _, c := range []nikonColumn{nikonFilm, nikonFrame} {
+		if _, ok := columns[c]; !ok {
+			return nil, errors.New("line 1: `Film No.` and `Frame No.` columns are required")
+		}
+	}
+
+	films := []*types.Film{}
+	index := map[string]*types.Film{}
+	line := 1
+	for {
+		record, err := rd.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, err
+		}
+
+		if isEmptySliceOfStrings(record) {
+			continue
+		}
+
+		film, frame, err := p.parseRecord(record, columns)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", line)
+		}
+
+		key := film.FullID()
+		f, ok := index[key]
+		if !ok {
+			f = film
+			index[key] = f
+			films = append(films, f)
+		}
+		f.Frames = append(f.Frames, frame)
+	}
+
+	return films, nil
+}
+
+func (p *NikonParser) parseRecord(record []string, columns map[nikonColumn]int) (*types.Film, *types.Frame, error) {
+	value := func(c nikonColumn) string {
+		i, ok := columns[c]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	film := &types.Film{
+		CameraID: types.PtrUint8(0),
+	}
+
+	if v := value(nikonCameraID); v != "" {
+		id, err := strconv.ParseUint(v, 10, 8)
+		if err != nil {
+			return nil, nil, errors.Errorf("invalid camera ID `%s`", v)
+		}
+		film.CameraID = types.PtrUint8(uint8(id))
+	}
+
+	filmID, err := strconv.ParseInt(value(nikonFilm), 10, 64)
+	if err != nil {
+		return nil, nil, errors.Errorf("invalid film number `%s`", value(nikonFilm))
+	}
+	film.ID = &filmID
+
+	if v := value(nikonCamera); v != "" {
+		if !strings.HasPrefix(strings.ToLower(v), "nikon") {
+			v = "Nikon " + v
+		}
+		film.Model = &v
+	}
+
+	frameNo, err := strconv.ParseInt(value(nikonFrame), 10, 64)
+	if err != nil {
+		return nil, nil, errors.Errorf("invalid frame number `%s`", value(nikonFrame))
+	}
+
+	frame := &types.Frame{
+		Number: &frameNo,
+	}
+
+	if v := value(nikonShutter); v != "" {
+		frame.Tv = &v
+	}
+
+	if v := value(nikonAperture); v != "" {
+		av, err := types.ApertureFromString(strings.TrimPrefix(strings.TrimPrefix(strings.ToLower(v), "f"), "/"))
+		if err != nil {
+			return nil, nil, errors.Errorf("invalid aperture `%s`", v)
+		}
+		frame.Av = av
+	}
+
+	if v := value(nikonFocalLength); v != "" {
+		fl, err := parseFocalLength(strings.TrimSpace(strings.TrimSuffix(v, "mm")))
+		if err != nil {
+			return nil, nil, errors.Errorf("invalid focal length `%s`", v)
+		}
+		frame.FocalLength = fl
+	}
+
+	if v := value(nikonExposureMode); v != "" {
+		sm, ok := nikonExposureModes[strings.ToLower(v)]
+		if !ok {
+			return nil, nil, errors.Errorf("unknown exposure mode `%s`", v)
+		}
+		frame.ShootingMode = &sm
+	}
+
+	if v := value(nikonMetering); v != "" {
+		mm, ok := nikonMeteringModes[strings.ToLower(v)]
+		if !ok {
+			return nil, nil, errors.Errorf("unknown metering system `%s`", v)
+		}
+		frame.MeteringMode = &mm
+	}
+
+	if frame.ExposureCompensation, err = parseFloat(value(nikonExposureComp)); err != nil && err != ErrNotProvided {
+		return nil, nil, errors.Errorf("invalid exposure compensation `%s`", value(nikonExposureComp))
+	}
+
+	if frame.FlashCompensation, err = parseFloat(value(nikonFlashComp)); err != nil && err != ErrNotProvided {
+		return nil, nil, errors.Errorf("invalid flash compensation `%s`", value(nikonFlashComp))
+	}
+
+	// Nikon exports sync mode of the flash fired, empty value means no flash
+	if v := value(nikonFlashMode); v != "" {
+		fm := types.FlashModeOn
+		if strings.EqualFold(v, "off") {
+			fm = types.FlashModeOff
+		}
+		frame.FlashMode = &fm
+	}
+
+	if frame.ISO, err = parseISO(value(nikonISO)); err != nil && err != ErrNotProvided {
+		return nil, nil, errors.Errorf("invalid ISO `%s`", value(nikonISO))
+	}
+
+	if d := value(nikonDate); d != "" {
+		ts, err := p.parseTimestamp(d, value(nikonTime), *film.CameraID)
+		if err != nil {
+			return nil, nil, err
+		}
+		frame.Timestamp = ts
+	}
+
+	if v := value(nikonLens); v != "" {
+		frame.Lens = &v
+	}
+
+	if v := value(nikonComment); v != "" {
+		frame.Remarks = &v
+	}
+
+	return film, frame, nil
+}
+
+func (p *NikonParser) parseTimestamp(d, t string, cameraID uint8) (*time.Time, error) {
+	loc := time.UTC
+	if p.tzfn != nil {
+		loc = p.tzfn(cameraID)
+	}
+
+	if t == "" {
+		t = "00:00:00"
+	}
+
+	for _, layout := range nikonDateLayouts {
+		for _, tl := range timeLayouts {
+			if ts, err := time.ParseInLocation(layout+" "+tl, d+" "+t, loc); err == nil {
+				return &ts, nil
+			}
+		}
+	}
+	return nil, errors.Errorf("invalid date and time `%s %s`", d, t)
+}
+
+func isNikonCSV(head []byte) bool {
+	l := strings.ToLower(firstLine(head))
+	if isFilmHeader(l) {
+		return false
+	}
+	return strings.Contains(l, "frame no") && strings.Contains(l, "shutter")
+}
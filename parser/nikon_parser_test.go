@@ -0,0 +1,124 @@
+package tagger
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+func TestNikonParser(t *testing.T) {
+	r := require.New(t)
+
+	tz, err := time.LoadLocation("Asia/Tokyo")
+	r.NoError(err)
+
+	p, err := NewNikon("testdata/nikon-f6.csv", func(cameraID uint8) *time.Location {
+		r.Equal(uint8(0), cameraID)
+		return tz
+	})
+	r.NoError(err)
+	defer func() {
+		r.NoError(p.Close())
+	}()
+
+	films, err := p.Parse()
+	r.NoError(err)
+
+	r.Equal([]*types.Film{
+		{
+			ID:       types.PtrInt64(1),
+			CameraID: types.PtrUint8(0),
+			Model:    types.PtrString("Nikon F6"),
+			Frames: []*types.Frame{
+				{
+					Number:               types.PtrInt64(1),
+					Tv:                   types.PtrString("1/250"),
+					Av:                   types.PtrAperture(8),
+					FocalLength:          types.PtrInt64(50),
+					ShootingMode:         types.PtrShootingMode(types.ShootingModeAperturePriorityAE),
+					MeteringMode:         types.PtrMeteringMode(types.MeteringModeEvaluative),
+					ExposureCompensation: types.PtrFloat64(0),
+					ISO:                  types.PtrInt64(400),
+					Timestamp:            types.PtrTime(time.Date(2020, 5, 1, 10, 15, 30, 0, tz)),
+					Lens:                 types.PtrString("AF-S Nikkor 50mm f/1.8G"),
+				},
+				{
+					Number:               types.PtrInt64(2),
+					Tv:                   types.PtrString("1/60"),
+					Av:                   types.PtrAperture(2.8),
+					FocalLength:          types.PtrInt64(35),
+					ShootingMode:         types.PtrShootingMode(types.ShootingModeManualExposure),
+					MeteringMode:         types.PtrMeteringMode(types.MeteringModeSpot),
+					ExposureCompensation: types.PtrFloat64(-0.7),
+					FlashMode:            types.PtrFlashMode(types.FlashModeOn),
+					ISO:                  types.PtrInt64(400),
+					Timestamp:            types.PtrTime(time.Date(2020, 5, 1, 10, 20, 0, 0, tz)),
+					Remarks:              types.PtrString("indoor"),
+				},
+			},
+		},
+		{
+			ID:       types.PtrInt64(2),
+			CameraID: types.PtrUint8(0),
+			Model:    types.PtrString("Nikon F6"),
+			Frames: []*types.Frame{
+				{
+					Number:               types.PtrInt64(1),
+					Tv:                   types.PtrString("1/1000"),
+					Av:                   types.PtrAperture(5.6),
+					FocalLength:          types.PtrInt64(85),
+					ShootingMode:         types.PtrShootingMode(types.ShootingModeProgramAE),
+					MeteringMode:         types.PtrMeteringMode(types.MeteringModeCenterAveraging),
+					ExposureCompensation: types.PtrFloat64(1),
+					ISO:                  types.PtrInt64(100),
+					Timestamp:            types.PtrTime(time.Date(2020, 5, 2, 8, 0, 0, 0, tz)),
+				},
+			},
+		},
+	}, films)
+}
+
+func TestNikonParserErrors(t *testing.T) {
+	r := require.New(t)
+
+	type testCase struct {
+		name  string
+		input string
+		err   string
+	}
+
+	tcs := []testCase{
+		{
+			name:  "no frame number column",
+			input: "Film No.,Shutter Speed\n1,1/60\n",
+			err:   "line 1: `Film No.` and `Frame No.` columns are required",
+		},
+		{
+			name:  "invalid frame number",
+			input: "Film No.,Frame No.\n1,1\n1,x\n",
+			err:   "line 3: invalid frame number `x`",
+		},
+		{
+			name:  "unknown exposure mode",
+			input: "Film No.,Frame No.,Exposure Mode\n1,1,Auto\n",
+			err:   "line 2: unknown exposure mode `Auto`",
+		},
+		{
+			name:  "empty file",
+			input: "",
+			err:   "no header line found",
+		},
+	}
+
+	for _, tc := range tcs {
+		p := &NikonParser{rc: ioutil.NopCloser(strings.NewReader(tc.input))}
+		_, err := p.Parse()
+		r.Errorf(err, tc.name)
+		r.Equalf(tc.err, err.Error(), tc.name)
+	}
+}
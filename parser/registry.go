@@ -0,0 +1,161 @@
+package tagger
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+// Options are passed to format parsers on opening the file
+type Options struct {
+	// TimestampFormat is the layout of ES-E1 CSV timestamps
+	TimestampFormat string
+
+	// TZ returns the timezone of camera for timestamps without offset
+	TZ func(uint8) *time.Location
+}
+
+// Format describes input format known to the parser
+type Format struct {
+	Name        types.InputFormat
+	Description string
+
+	// Extensions are file extensions of the format including the dot
+	Extensions []string
+
+	// Detect reports whether the beginning of the file looks like the
+	// format, see DetectFormat
+	Detect func(head []byte) bool
+
+	// Defaults are camera properties (i.e. EXIF Make and Model) used for
+	// films of the format unless set in configuration or in the file
+	Defaults types.CameraProfile
+
+	Open func(fn string, opts Options) (Source, error)
+}
+
+var registry = []Format{}
+
+func init() {
+	Register(Format{
+		Name:        types.InputFormatCSV,
+		Description: "Canon ES-E1 CSV export",
+		Extensions:  []string{".csv"},
+		Detect:      isESE1,
+		Defaults: types.CameraProfile{
+			Make:  types.PtrString("Canon"),
+			Model: types.PtrString("Canon EOS-1V"),
+		},
+		Open: func(fn string, opts Options) (Source, error) {
+			return New(fn, opts.TimestampFormat, opts.TZ)
+		},
+	})
+	Register(Format{
+		Name:        types.InputFormatNikon,
+		Description: "Nikon data memory CSV export",
+		Extensions:  []string{".csv"},
+		Detect:      isNikonCSV,
+		Defaults: types.CameraProfile{
+			Make: types.PtrString("Nikon"),
+		},
+		Open: func(fn string, opts Options) (Source, error) {
+			return NewNikon(fn, opts.TZ)
+		},
+	})
+	Register(Format{
+		Name:        types.InputFormatJSON,
+		Description: "JSON document produced by -export",
+		Extensions:  []string{".json"},
+		Detect:      isJSONDocument,
+		Open: func(fn string, opts Options) (Source, error) {
+			return NewDocument(fn, types.DataFormatJSON)
+		},
+	})
+	Register(Format{
+		Name:        types.InputFormatYAML,
+		Description: "YAML document produced by -export",
+		Extensions:  []string{".yaml", ".yml"},
+		Detect:      isYAMLDocument,
+		Open: func(fn string, opts Options) (Source, error) {
+			return NewDocument(fn, types.DataFormatYAML)
+		},
+	})
+	Register(Format{
+		Name:        types.InputFormatFilmLog,
+		Description: "hand-written film log",
+		Extensions:  []string{".filmlog"},
+		Detect:      isFilmLog,
+		Open: func(fn string, opts Options) (Source, error) {
+			return NewFilmLog(fn, opts.TZ)
+		},
+	})
+	Register(Format{
+		Name:        types.InputFormatExifNotes,
+		Description: "Exif Notes app JSON export",
+		Extensions:  []string{".json"},
+		Detect:      isExifNotes,
+		Open: func(fn string, opts Options) (Source, error) {
+			return NewExifNotes(fn, opts.TZ)
+		},
+	})
+}
+
+// Register adds the format to the registry. It panics if the format with
+// the same name is already registered.
+func Register(f Format) {
+	if _, err := LookupFormat(f.Name); err == nil {
+		panic("parser: format " + string(f.Name) + " is registered twice")
+	}
+	registry = append(registry, f)
+}
+
+// Formats returns registered formats sorted by name
+func Formats() []Format {
+	formats := make([]Format, len(registry))
+	copy(formats, registry)
+	sort.Slice(formats, func(i, j int) bool { return formats[i].Name < formats[j].Name })
+	return formats
+}
+
+// LookupFormat returns the format registered under the name
+func LookupFormat(name types.InputFormat) (*Format, error) {
+	for i := range registry {
+		if registry[i].Name == name {
+			return &registry[i], nil
+		}
+	}
+
+	names := []string{}
+	for _, f := range Formats() {
+		names = append(names, string(f.Name))
+	}
+	return nil, errors.Errorf("unknown input format `%s`, available formats: %s", name, strings.Join(names, ", "))
+}
+
+// Open creates Source for the file in the format specified or detected by
+// DetectFormat if format is empty. The format is returned along with the
+// source to look up format defaults.
+func Open(fn string, format types.InputFormat, opts Options) (Source, *Format, error) {
+	var (
+		f   *Format
+		err error
+	)
+	if format == types.InputFormatAuto {
+		f, err = DetectFormat(fn)
+	} else {
+		f, err = LookupFormat(format)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s, err := f.Open(fn, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, f, nil
+}
@@ -0,0 +1,29 @@
+package tagger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+func TestLookupFormat(t *testing.T) {
+	r := require.New(t)
+
+	f, err := LookupFormat(types.InputFormatNikon)
+	r.NoError(err)
+	r.Equal("Nikon", *f.Defaults.Make)
+
+	_, err = LookupFormat("pentax")
+	r.Error(err)
+	r.Equal("unknown input format `pentax`, available formats: csv, exifnotes, json, log, nikon, yaml", err.Error())
+}
+
+func TestRegisterTwice(t *testing.T) {
+	r := require.New(t)
+
+	r.Panics(func() {
+		Register(Format{Name: types.InputFormatCSV})
+	})
+}
@@ -0,0 +1,56 @@
+package tagger
+
+import (
+	"os"
+
+	export "github.com/teran/eos-1v-tagger/export"
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+var (
+	_ Source = (*CSVParser)(nil)
+	_ Source = (*DocumentParser)(nil)
+	_ Source = (*FilmLogParser)(nil)
+	_ Source = (*ExifNotesParser)(nil)
+	_ Source = (*NikonParser)(nil)
+)
+
+// Source is a source of films to tag
+type Source interface {
+	Parse() ([]*types.Film, error)
+	Close() error
+}
+
+// DocumentParser reads films from JSON or YAML export document
+type DocumentParser struct {
+	fp     *os.File
+	format types.DataFormat
+}
+
+// NewDocument creates new DocumentParser object
+func NewDocument(fn string, format types.DataFormat) (*DocumentParser, error) {
+	fp, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DocumentParser{
+		fp:     fp,
+		format: format,
+	}, nil
+}
+
+// Close ...
+func (p *DocumentParser) Close() error {
+	return p.fp.Close()
+}
+
+// Parse ...
+func (p *DocumentParser) Parse() ([]*types.Film, error) {
+	doc, err := export.Decode(p.fp, p.format)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.Films, nil
+}
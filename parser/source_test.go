@@ -0,0 +1,68 @@
+package tagger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+func TestOpen(t *testing.T) {
+	r := require.New(t)
+
+	tzfn := func(uint8) *time.Location { return time.UTC }
+
+	parse := func(fn string, format types.InputFormat) []*types.Film {
+		s, _, err := Open(fn, format, Options{TimestampFormat: types.TimestampFormatUS.TimeLayout(), TZ: tzfn})
+		r.NoError(err)
+		defer func() {
+			r.NoError(s.Close())
+		}()
+
+		films, err := s.Parse()
+		r.NoError(err)
+		return films
+	}
+
+	expected := parse("testdata/two-films.csv", "")
+	r.Len(expected, 2)
+
+	type testCase struct {
+		name   string
+		fn     string
+		format types.InputFormat
+	}
+
+	tcs := []testCase{
+		{
+			name: "JSON by extension",
+			fn:   "testdata/two-films.json",
+		},
+		{
+			name: "YAML by extension",
+			fn:   "testdata/two-films.yaml",
+		},
+		{
+			name:   "JSON set explicitly",
+			fn:     "testdata/two-films.json",
+			format: types.InputFormatJSON,
+		},
+	}
+
+	for _, tc := range tcs {
+		r.Equalf(expected, parse(tc.fn, tc.format), tc.name)
+	}
+}
+
+func TestOpenMismatchedFormat(t *testing.T) {
+	r := require.New(t)
+
+	s, _, err := Open("testdata/two-films.csv", types.InputFormatJSON, Options{TimestampFormat: types.TimestampFormatUS.TimeLayout()})
+	r.NoError(err)
+	defer s.Close()
+
+	_, err = s.Parse()
+	r.Error(err)
+}
@@ -0,0 +1,188 @@
+package sidecar
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+
+	catalog "github.com/teran/eos-1v-tagger/catalog"
+	correction "github.com/teran/eos-1v-tagger/correction"
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+const (
+	filePrefix = "film-"
+	fileSuffix = ".yaml"
+)
+
+// Sidecar is per-film metadata overrides file stored next to the scans
+// as `film-<camera ID>-<film ID>.yaml`, e.g. `film-01-139.yaml`
+type Sidecar struct {
+	// FilmID is the film ID in ES-E1 notation, defaults to the one from filename
+	FilmID    string   `yaml:"film-id"`
+	Title     *string  `yaml:"title"`
+	Remarks   *string  `yaml:"remarks"`
+	Stock     *string  `yaml:"stock"`
+	Lens      *string  `yaml:"lens"`
+	Timezone  *string  `yaml:"timezone"`
+	Copyright *string  `yaml:"copyright"`
+	Location  *string  `yaml:"location"`
+	Developer *string  `yaml:"developer"`
+	Lab       *string  `yaml:"lab"`
+	PushPull  *float64 `yaml:"push-pull"`
+
+	path string
+}
+
+// LoadFile reads sidecar file
+func LoadFile(path string) (*Sidecar, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Sidecar{path: path}
+	if err := yaml.UnmarshalStrict(data, s); err != nil {
+		return nil, errors.Wrapf(err, "error decoding %s", path)
+	}
+
+	if s.FilmID == "" {
+		name := filepath.Base(path)
+		s.FilmID = strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), fileSuffix)
+	}
+
+	return s, nil
+}
+
+// LoadDir reads all the sidecar files from the directory
+func LoadDir(dir string) ([]*Sidecar, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, filePrefix+"*"+fileSuffix))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	scs := []*Sidecar{}
+	for _, p := range paths {
+		s, err := LoadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		scs = append(scs, s)
+	}
+
+	return scs, nil
+}
+
+// Apply merges sidecars over the films they belong to. Stock and lens are
+// looked up in the catalogs by key and name respectively. Sidecars for
+// films missing in films list are reported as errors.
+func Apply(films []*types.Film, scs []*Sidecar, stocks map[string]types.FilmStock, lenses []types.Lens) error {
+	byID := map[string]*types.Film{}
+	for _, f := range films {
+		byID[f.FullID()] = f
+	}
+
+	problems := []string{}
+	for _, s := range scs {
+		film, ok := byID[s.FilmID]
+		if !ok {
+			problems = append(problems, errors.Errorf("%s: film %s is not present in the input", s.path, s.FilmID).Error())
+			continue
+		}
+
+		if err := s.apply(film, stocks, lenses); err != nil {
+			problems = append(problems, errors.Wrap(err, s.path).Error())
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.Errorf("error applying film sidecars:\n  %s", strings.Join(problems, "\n  "))
+	}
+
+	return nil
+}
+
+// AssignFilmStocks assigns stocks from the catalog to the films by
+// assignments and title patterns, see catalog.AssignFilmStocks. Stocks set
+// by sidecars are kept. It's called after Apply so title patterns match
+// sidecar titles.
+func AssignFilmStocks(films []*types.Film, scs []*Sidecar, stocks map[string]types.FilmStock, assignments map[string]string) error {
+	sidecarStocks := map[string]bool{}
+	for _, s := range scs {
+		if s.Stock != nil {
+			sidecarStocks[s.FilmID] = true
+		}
+	}
+
+	stockFilms := []*types.Film{}
+	for _, film := range films {
+		if !sidecarStocks[film.FullID()] {
+			stockFilms = append(stockFilms, film)
+		}
+	}
+
+	return catalog.AssignFilmStocks(stockFilms, stocks, assignments)
+}
+
+func (s *Sidecar) apply(film *types.Film, stocks map[string]types.FilmStock, lenses []types.Lens) error {
+	if s.Stock != nil {
+		stock, ok := stocks[*s.Stock]
+		if !ok {
+			return errors.Errorf("unknown film stock `%s`", *s.Stock)
+		}
+		stock.ID = *s.Stock
+		film.Stock = &stock
+	}
+
+	if s.Lens != nil {
+		lens := catalog.FindLens(lenses, *s.Lens)
+		if lens == nil {
+			return errors.Errorf("unknown lens `%s`", *s.Lens)
+		}
+		film.Lens = lens
+	}
+
+	if s.Timezone != nil {
+		loc, err := time.LoadLocation(*s.Timezone)
+		if err != nil {
+			return err
+		}
+		correction.RelocateFilm(film, loc)
+	}
+
+	if s.Title != nil {
+		film.Title = s.Title
+	}
+
+	if s.Remarks != nil {
+		film.Remarks = s.Remarks
+	}
+
+	if s.Copyright != nil {
+		film.Copyright = s.Copyright
+	}
+
+	if s.Location != nil {
+		film.Location = s.Location
+	}
+
+	if s.Developer != nil {
+		film.Developer = s.Developer
+	}
+
+	if s.Lab != nil {
+		film.Lab = s.Lab
+	}
+
+	if s.PushPull != nil {
+		film.PushPull = s.PushPull
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package sidecar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+func TestLoadDir(t *testing.T) {
+	r := require.New(t)
+
+	scs, err := LoadDir("testdata")
+	r.NoError(err)
+	r.Equal([]*Sidecar{
+		{
+			FilmID:    "01-139",
+			Title:     types.PtrString("Kyoto, day one"),
+			Stock:     types.PtrString("portra400"),
+			Lens:      types.PtrString("EF50mm f/1.4 USM"),
+			Timezone:  types.PtrString("Asia/Tokyo"),
+			Copyright: types.PtrString("Jane Doe"),
+			Location:  types.PtrString("Kyoto, Japan"),
+			Developer: types.PtrString("Kodak Flexicolor"),
+			Lab:       types.PtrString("Local Lab"),
+			PushPull:  types.PtrFloat64(1),
+			path:      "testdata/film-01-139.yaml",
+		},
+		{
+			FilmID:  "01-140",
+			Remarks: types.PtrString("rewound mid-roll"),
+			path:    "testdata/film-01-140.yaml",
+		},
+	}, scs)
+
+	_, err = LoadDir("testdata/invalid-key")
+	r.Error(err)
+}
+
+func TestApply(t *testing.T) {
+	r := require.New(t)
+
+	stocks := map[string]types.FilmStock{
+		"portra400": {Manufacturer: "Kodak", Name: "Portra 400", BoxSpeed: 400},
+	}
+	lenses := []types.Lens{
+		{Name: "EF50mm f/1.4 USM", FocalLength: types.Range{Min: 50, Max: 50}},
+	}
+
+	newFilms := func() []*types.Film {
+		return []*types.Film{
+			{
+				ID:       types.PtrInt64(139),
+				CameraID: types.PtrUint8(1),
+				Title:    types.PtrString("Original title"),
+				Frames: []*types.Frame{
+					{
+						Number:    types.PtrInt64(1),
+						Timestamp: types.PtrTime(time.Date(2019, 10, 7, 20, 2, 18, 0, time.UTC)),
+					},
+				},
+			},
+			{
+				ID:       types.PtrInt64(140),
+				CameraID: types.PtrUint8(1),
+				Title:    types.PtrString("Second film"),
+				Remarks:  types.PtrString("original remarks"),
+			},
+		}
+	}
+
+	scs, err := LoadDir("testdata")
+	r.NoError(err)
+
+	films := newFilms()
+	err = Apply(films, scs, stocks, lenses)
+	r.NoError(err)
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	r.NoError(err)
+
+	r.Equal("Kyoto, day one", *films[0].Title)
+	r.Equal("portra400", films[0].Stock.ID)
+	r.Equal("EF50mm f/1.4 USM", films[0].Lens.Name)
+	r.Equal("Jane Doe", *films[0].Copyright)
+	r.Equal("Kyoto, Japan", *films[0].Location)
+	r.Equal("Kodak Flexicolor", *films[0].Developer)
+	r.Equal("Local Lab", *films[0].Lab)
+	r.Equal(1.0, *films[0].PushPull)
+	r.Equal(time.Date(2019, 10, 7, 20, 2, 18, 0, tokyo), *films[0].Frames[0].Timestamp)
+
+	r.Equal("Second film", *films[1].Title)
+	r.Equal("rewound mid-roll", *films[1].Remarks)
+
+	unknown, err := LoadDir("testdata/unknown-film")
+	r.NoError(err)
+
+	err = Apply(newFilms(), unknown, stocks, lenses)
+	r.Error(err)
+	r.Equal("error applying film sidecars:\n  testdata/unknown-film/film-any.yaml: film 02-001 is not present in the input", err.Error())
+
+	err = Apply(newFilms(), scs, nil, nil)
+	r.Error(err)
+	r.Equal("error applying film sidecars:\n  testdata/film-01-139.yaml: unknown film stock `portra400`", err.Error())
+}
+
+func TestAssignFilmStocks(t *testing.T) {
+	r := require.New(t)
+
+	stocks := map[string]types.FilmStock{
+		"hp5":       {Manufacturer: "Ilford", Name: "HP5 Plus", BoxSpeed: 400, TitlePattern: "film"},
+		"portra400": {Manufacturer: "Kodak", Name: "Portra 400", BoxSpeed: 400, TitlePattern: "film"},
+	}
+
+	films := []*types.Film{
+		{ID: types.PtrInt64(139), CameraID: types.PtrUint8(1), Title: types.PtrString("First film")},
+		{ID: types.PtrInt64(140), CameraID: types.PtrUint8(1), Title: types.PtrString("Second film")},
+	}
+
+	scs, err := LoadDir("testdata")
+	r.NoError(err)
+
+	// stock of the first film is set by its sidecar
+	err = AssignFilmStocks(films, scs, stocks, nil)
+	r.NoError(err)
+	r.Nil(films[0].Stock)
+	r.Equal("hp5", films[1].Stock.ID)
+}
@@ -0,0 +1,285 @@
+package stats
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+// Count is the number of frames with the value
+type Count struct {
+	Value  string `json:"value"`
+	Frames int    `json:"frames"`
+}
+
+// Distribution of frames by value
+type Distribution []Count
+
+// Duration marshals to JSON as Go duration string
+type Duration time.Duration
+
+// MarshalJSON ...
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// DurationStats summarizes the time spent per roll: from film load (or the
+// first frame if load time is unknown) to the last frame
+type DurationStats struct {
+	Min  Duration `json:"min"`
+	Mean Duration `json:"mean"`
+	Max  Duration `json:"max"`
+}
+
+// Report is the statistics of a single film or a set of films
+type Report struct {
+	// FilmID is empty for the report across several films
+	FilmID string `json:"film_id,omitempty"`
+
+	Films  int `json:"films"`
+	Frames int `json:"frames"`
+
+	ShutterSpeeds Distribution `json:"shutter_speeds"`
+	Apertures     Distribution `json:"apertures"`
+	FocalLengths  Distribution `json:"focal_lengths"`
+	MeteringModes Distribution `json:"metering_modes"`
+	ShootingModes Distribution `json:"shooting_modes"`
+
+	// CompensatedFrames is the number of frames with non-zero exposure
+	// compensation
+	CompensatedFrames    int          `json:"compensated_frames"`
+	ExposureCompensation Distribution `json:"exposure_compensation"`
+
+	FramesPerDay Distribution `json:"frames_per_day"`
+
+	// RollDuration is nil if no film has timestamps
+	RollDuration *DurationStats `json:"roll_duration,omitempty"`
+
+	// PushPull is the distribution of frame ISO relative to the film DX ISO
+	// in stops, film push/pull is used for frames without ISO
+	PushPull Distribution `json:"push_pull"`
+}
+
+// Summary is the report of every film followed by the report across all
+// of them
+type Summary struct {
+	Films []Report `json:"films"`
+	Total Report   `json:"total"`
+}
+
+// Films computes statistics of every film and across all the films
+func Films(films []*types.Film) *Summary {
+	s := &Summary{
+		Films: make([]Report, len(films)),
+	}
+
+	total := newCollector()
+	for i, f := range films {
+		c := newCollector()
+		c.add(f)
+		total.add(f)
+
+		s.Films[i] = c.report()
+		s.Films[i].FilmID = f.FullID()
+	}
+	s.Total = total.report()
+
+	return s
+}
+
+type collector struct {
+	films, frames, compensated int
+
+	tv, av, focal, metering, shooting, ec, days, pushPull map[string]int
+
+	durations []time.Duration
+}
+
+func newCollector() *collector {
+	return &collector{
+		tv:       map[string]int{},
+		av:       map[string]int{},
+		focal:    map[string]int{},
+		metering: map[string]int{},
+		shooting: map[string]int{},
+		ec:       map[string]int{},
+		days:     map[string]int{},
+		pushPull: map[string]int{},
+	}
+}
+
+func (c *collector) add(f *types.Film) {
+	c.films++
+
+	var first, last *time.Time
+	for _, fr := range f.Frames {
+		c.frames++
+
+		if fr.Tv != nil && strings.TrimSpace(*fr.Tv) != "" {
+			c.tv[strings.TrimSpace(*fr.Tv)]++
+		}
+		if fr.Av != nil {
+			c.av[fr.Av.String()]++
+		}
+		if fr.FocalLength != nil {
+			c.focal[strconv.FormatInt(*fr.FocalLength, 10)+"mm"]++
+		}
+		if fr.MeteringMode != nil {
+			c.metering[fr.MeteringMode.String()]++
+		}
+		if fr.ShootingMode != nil {
+			c.shooting[fr.ShootingMode.String()]++
+		}
+		if fr.ExposureCompensation != nil {
+			c.ec[stops(*fr.ExposureCompensation)]++
+			if *fr.ExposureCompensation != 0 {
+				c.compensated++
+			}
+		}
+
+		switch {
+		case fr.ISO != nil && f.ISO != nil && *fr.ISO > 0 && *f.ISO > 0:
+			c.pushPull[stops(math.Log2(float64(*fr.ISO)/float64(*f.ISO)))]++
+		case fr.ISO == nil && f.PushPull != nil:
+			c.pushPull[stops(*f.PushPull)]++
+		}
+
+		if fr.Timestamp == nil || fr.Timestamp.IsZero() {
+			continue
+		}
+		c.days[fr.Timestamp.Format("2006-01-02")]++
+		if first == nil || fr.Timestamp.Before(*first) {
+			first = fr.Timestamp
+		}
+		if last == nil || fr.Timestamp.After(*last) {
+			last = fr.Timestamp
+		}
+	}
+
+	if f.FilmLoadedTimestamp != nil && !f.FilmLoadedTimestamp.IsZero() && first != nil && f.FilmLoadedTimestamp.Before(*first) {
+		first = f.FilmLoadedTimestamp
+	}
+	if first != nil {
+		c.durations = append(c.durations, last.Sub(*first))
+	}
+}
+
+func (c *collector) report() Report {
+	r := Report{
+		Films:                c.films,
+		Frames:               c.frames,
+		ShutterSpeeds:        distribution(c.tv, exposureSeconds),
+		Apertures:            distribution(c.av, number),
+		FocalLengths:         distribution(c.focal, number),
+		MeteringModes:        distribution(c.metering, nil),
+		ShootingModes:        distribution(c.shooting, nil),
+		CompensatedFrames:    c.compensated,
+		ExposureCompensation: distribution(c.ec, number),
+		FramesPerDay:         distribution(c.days, byValue),
+		PushPull:             distribution(c.pushPull, number),
+	}
+
+	if len(c.durations) > 0 {
+		ds := &DurationStats{
+			Min: Duration(c.durations[0]),
+			Max: Duration(c.durations[0]),
+		}
+		var sum time.Duration
+		for _, d := range c.durations {
+			sum += d
+			if Duration(d) < ds.Min {
+				ds.Min = Duration(d)
+			}
+			if Duration(d) > ds.Max {
+				ds.Max = Duration(d)
+			}
+		}
+		ds.Mean = Duration(sum / time.Duration(len(c.durations)))
+		r.RollDuration = ds
+	}
+
+	return r
+}
+
+// distribution sorts the counts by the numeric key of the value, values
+// without the key follow sorted by name. Nil key sorts by frames in
+// descending order.
+func distribution(counts map[string]int, key func(string) (float64, bool)) Distribution {
+	d := Distribution{}
+	for v, n := range counts {
+		d = append(d, Count{Value: v, Frames: n})
+	}
+
+	sort.Slice(d, func(i, j int) bool {
+		if key == nil {
+			if d[i].Frames != d[j].Frames {
+				return d[i].Frames > d[j].Frames
+			}
+			return d[i].Value < d[j].Value
+		}
+
+		ki, iok := key(d[i].Value)
+		kj, jok := key(d[j].Value)
+		switch {
+		case iok && jok && ki != kj:
+			return ki < kj
+		case iok != jok:
+			return iok
+		}
+		return d[i].Value < d[j].Value
+	})
+
+	return d
+}
+
+// stops formats the value in stops rounded to 1/10 of stop
+func stops(v float64) string {
+	v = math.Round(v*10) / 10
+	if v == 0 {
+		return "0"
+	}
+	s := strconv.FormatFloat(v, 'f', -1, 64)
+	if v > 0 {
+		s = "+" + s
+	}
+	return s
+}
+
+// byValue sorts distribution by value only
+func byValue(string) (float64, bool) {
+	return 0, true
+}
+
+func number(s string) (float64, bool) {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(s, "mm"), 64)
+	return v, err == nil
+}
+
+// exposureSeconds parses shutter speed as it's recorded by ES-E1: `1/250`,
+// `2"` or `0"5`
+func exposureSeconds(s string) (float64, bool) {
+	if i := strings.Index(s, "/"); i > 0 {
+		n, err := strconv.ParseFloat(s[:i], 64)
+		if err != nil {
+			return 0, false
+		}
+		d, err := strconv.ParseFloat(s[i+1:], 64)
+		if err != nil || d == 0 {
+			return 0, false
+		}
+		return n / d, true
+	}
+
+	s = strings.TrimSuffix(strings.Replace(s, `"`, ".", 1), ".")
+	v, err := strconv.ParseFloat(s, 64)
+	return v, err == nil
+}
@@ -0,0 +1,243 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+func testFilms() []*types.Film {
+	return []*types.Film{
+		{
+			ID:                  types.PtrInt64(139),
+			CameraID:            types.PtrUint8(1),
+			FilmLoadedTimestamp: types.PtrTime(time.Date(2019, 10, 7, 10, 0, 0, 0, time.UTC)),
+			ISO:                 types.PtrInt64(400),
+			Frames: []*types.Frame{
+				{
+					Number:               types.PtrInt64(1),
+					Tv:                   types.PtrString("1/40"),
+					Av:                   types.PtrAperture(1.4),
+					ISO:                  types.PtrInt64(400),
+					ExposureCompensation: types.PtrFloat64(0),
+					FocalLength:          types.PtrInt64(35),
+					MeteringMode:         types.PtrMeteringMode(types.MeteringModeEvaluative),
+					ShootingMode:         types.PtrShootingMode(types.ShootingModeAperturePriorityAE),
+					Timestamp:            types.PtrTime(time.Date(2019, 10, 7, 12, 0, 0, 0, time.UTC)),
+				},
+				{
+					Number:               types.PtrInt64(2),
+					Tv:                   types.PtrString(`2"`),
+					Av:                   types.PtrAperture(8),
+					ISO:                  types.PtrInt64(800),
+					ExposureCompensation: types.PtrFloat64(-0.7),
+					FocalLength:          types.PtrInt64(135),
+					MeteringMode:         types.PtrMeteringMode(types.MeteringModeSpot),
+					ShootingMode:         types.PtrShootingMode(types.ShootingModeManualExposure),
+					Timestamp:            types.PtrTime(time.Date(2019, 10, 8, 14, 0, 0, 0, time.UTC)),
+				},
+				{
+					Number:       types.PtrInt64(3),
+					Tv:           types.PtrString("1/250"),
+					Av:           types.PtrAperture(8),
+					ISO:          types.PtrInt64(200),
+					FocalLength:  types.PtrInt64(35),
+					MeteringMode: types.PtrMeteringMode(types.MeteringModeEvaluative),
+					ShootingMode: types.PtrShootingMode(types.ShootingModeAperturePriorityAE),
+					Timestamp:    types.PtrTime(time.Date(2019, 10, 8, 16, 0, 0, 0, time.UTC)),
+				},
+			},
+		},
+		{
+			ID:       types.PtrInt64(12),
+			CameraID: types.PtrUint8(20),
+			ISO:      types.PtrInt64(100),
+			PushPull: types.PtrFloat64(1),
+			Frames: []*types.Frame{
+				{
+					Number:    types.PtrInt64(1),
+					Tv:        types.PtrString("1/250"),
+					Av:        types.PtrAperture(5.6),
+					Timestamp: types.PtrTime(time.Date(2020, 5, 1, 9, 0, 0, 0, time.UTC)),
+				},
+				{
+					Number:    types.PtrInt64(2),
+					Tv:        types.PtrString("1/4000"),
+					Timestamp: types.PtrTime(time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)),
+				},
+			},
+		},
+		{
+			ID:       types.PtrInt64(13),
+			CameraID: types.PtrUint8(20),
+		},
+	}
+}
+
+func TestFilms(t *testing.T) {
+	r := require.New(t)
+
+	s := Films(testFilms())
+	r.Len(s.Films, 3)
+
+	f := s.Films[0]
+	r.Equal("01-139", f.FilmID)
+	r.Equal(1, f.Films)
+	r.Equal(3, f.Frames)
+	r.Equal(Distribution{{"1/250", 1}, {"1/40", 1}, {`2"`, 1}}, f.ShutterSpeeds)
+	r.Equal(Distribution{{"1.4", 1}, {"8.0", 2}}, f.Apertures)
+	r.Equal(Distribution{{"35mm", 2}, {"135mm", 1}}, f.FocalLengths)
+	r.Equal(Distribution{{"Evaluative", 2}, {"Spot", 1}}, f.MeteringModes)
+	r.Equal(Distribution{{"Aperture-priority AE", 2}, {"Manual exposure", 1}}, f.ShootingModes)
+	r.Equal(1, f.CompensatedFrames)
+	r.Equal(Distribution{{"-0.7", 1}, {"0", 1}}, f.ExposureCompensation)
+	r.Equal(Distribution{{"2019-10-07", 1}, {"2019-10-08", 2}}, f.FramesPerDay)
+	r.Equal(Distribution{{"-1", 1}, {"0", 1}, {"+1", 1}}, f.PushPull)
+	r.Equal(&DurationStats{
+		Min:  Duration(30 * time.Hour),
+		Mean: Duration(30 * time.Hour),
+		Max:  Duration(30 * time.Hour),
+	}, f.RollDuration)
+
+	f = s.Films[1]
+	r.Equal("20-012", f.FilmID)
+	r.Equal(Distribution{{"1/4000", 1}, {"1/250", 1}}, f.ShutterSpeeds)
+	r.Equal(Distribution{{"+1", 2}}, f.PushPull)
+	r.Equal(Distribution{}, f.ExposureCompensation)
+	r.Equal(Duration(time.Hour), f.RollDuration.Mean)
+
+	f = s.Films[2]
+	r.Equal(0, f.Frames)
+	r.Nil(f.RollDuration)
+
+	total := s.Total
+	r.Equal("", total.FilmID)
+	r.Equal(3, total.Films)
+	r.Equal(5, total.Frames)
+	r.Equal(Distribution{{"1/4000", 1}, {"1/250", 2}, {"1/40", 1}, {`2"`, 1}}, total.ShutterSpeeds)
+	r.Equal(Distribution{{"-1", 1}, {"0", 1}, {"+1", 3}}, total.PushPull)
+	r.Equal(&DurationStats{
+		Min:  Duration(time.Hour),
+		Mean: Duration(31 * time.Hour / 2),
+		Max:  Duration(30 * time.Hour),
+	}, total.RollDuration)
+}
+
+func TestStops(t *testing.T) {
+	r := require.New(t)
+
+	type testCase struct {
+		name     string
+		value    float64
+		expected string
+	}
+
+	tcs := []testCase{
+		{name: "zero", value: 0, expected: "0"},
+		{name: "negative zero after rounding", value: -0.04, expected: "0"},
+		{name: "positive", value: 1, expected: "+1"},
+		{name: "negative", value: -0.66, expected: "-0.7"},
+		{name: "third of stop", value: 0.3333, expected: "+0.3"},
+	}
+
+	for _, tc := range tcs {
+		r.Equalf(tc.expected, stops(tc.value), tc.name)
+	}
+}
+
+func TestExposureSeconds(t *testing.T) {
+	r := require.New(t)
+
+	type testCase struct {
+		name     string
+		value    string
+		expected float64
+		ok       bool
+	}
+
+	tcs := []testCase{
+		{name: "fraction", value: "1/250", expected: 0.004, ok: true},
+		{name: "seconds", value: `2"`, expected: 2, ok: true},
+		{name: "fractional seconds", value: `0"5`, expected: 0.5, ok: true},
+		{name: "plain number", value: "30", expected: 30, ok: true},
+		{name: "bulb", value: "bulb", ok: false},
+		{name: "zero denominator", value: "1/0", ok: false},
+	}
+
+	for _, tc := range tcs {
+		v, ok := exposureSeconds(tc.value)
+		r.Equalf(tc.ok, ok, tc.name)
+		r.InDeltaf(tc.expected, v, 1e-9, tc.name)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	r := require.New(t)
+
+	buf := &bytes.Buffer{}
+	err := WriteText(buf, Films(testFilms()[1:]))
+	r.NoError(err)
+	r.Equal(`Film 20-012: 2 frames
+  Roll duration:          1h0m0s
+  Shutter speed:          1/4000 (1), 1/250 (1)
+  Aperture:               5.6 (1)
+  Focal length:           -
+  Metering mode:          -
+  Shooting mode:          -
+  Exposure compensation:  -
+  Frames per day:         2020-05-01 (2)
+  ISO vs DX, stops:       +1 (2)
+
+Film 20-013: 0 frames
+  Roll duration:          -
+  Shutter speed:          -
+  Aperture:               -
+  Focal length:           -
+  Metering mode:          -
+  Shooting mode:          -
+  Exposure compensation:  -
+  Frames per day:         -
+  ISO vs DX, stops:       -
+
+All films: 2 films, 2 frames
+  Roll duration:          min 1h0m0s, mean 1h0m0s, max 1h0m0s
+  Shutter speed:          1/4000 (1), 1/250 (1)
+  Aperture:               5.6 (1)
+  Focal length:           -
+  Metering mode:          -
+  Shooting mode:          -
+  Exposure compensation:  -
+  Frames per day:         2020-05-01 (2)
+  ISO vs DX, stops:       +1 (2)
+`, buf.String())
+}
+
+func TestWriteJSON(t *testing.T) {
+	r := require.New(t)
+
+	buf := &bytes.Buffer{}
+	err := WriteJSON(buf, Films(testFilms()[1:2]))
+	r.NoError(err)
+
+	var v map[string]interface{}
+	r.NoError(json.Unmarshal(buf.Bytes(), &v))
+
+	total := v["total"].(map[string]interface{})
+	r.Equal(float64(2), total["frames"])
+	r.Equal(map[string]interface{}{
+		"min":  "1h0m0s",
+		"mean": "1h0m0s",
+		"max":  "1h0m0s",
+	}, total["roll_duration"])
+	r.Equal([]interface{}{
+		map[string]interface{}{"value": "+1", "frames": float64(2)},
+	}, total["push_pull"])
+
+	film := v["films"].([]interface{})[0].(map[string]interface{})
+	r.Equal("20-012", film["film_id"])
+}
@@ -0,0 +1,77 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// WriteText prints the report of every film followed by the report across
+// all the films if there are several of them
+func WriteText(w io.Writer, s *Summary) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	for i, r := range s.Films {
+		if i > 0 {
+			fmt.Fprintln(tw)
+		}
+		fmt.Fprintf(tw, "Film %s: %d frames\n", r.FilmID, r.Frames)
+		writeReport(tw, r)
+	}
+
+	if len(s.Films) > 1 {
+		fmt.Fprintf(tw, "\nAll films: %d films, %d frames\n", s.Total.Films, s.Total.Frames)
+		writeReport(tw, s.Total)
+	}
+
+	return tw.Flush()
+}
+
+// WriteJSON prints the summary as indented JSON
+func WriteJSON(w io.Writer, s *Summary) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+func writeReport(w io.Writer, r Report) {
+	row := func(name, value string) {
+		if value == "" {
+			value = "-"
+		}
+		fmt.Fprintf(w, "  %s:\t%s\n", name, value)
+	}
+
+	duration := ""
+	if d := r.RollDuration; d != nil {
+		duration = d.Mean.String()
+		if r.Films > 1 {
+			duration = fmt.Sprintf("min %s, mean %s, max %s", d.Min, d.Mean, d.Max)
+		}
+	}
+
+	compensation := ""
+	if len(r.ExposureCompensation) > 0 {
+		compensation = fmt.Sprintf("%d of %d frames; %s", r.CompensatedFrames, r.Frames, r.ExposureCompensation)
+	}
+
+	row("Roll duration", duration)
+	row("Shutter speed", r.ShutterSpeeds.String())
+	row("Aperture", r.Apertures.String())
+	row("Focal length", r.FocalLengths.String())
+	row("Metering mode", r.MeteringModes.String())
+	row("Shooting mode", r.ShootingModes.String())
+	row("Exposure compensation", compensation)
+	row("Frames per day", r.FramesPerDay.String())
+	row("ISO vs DX, stops", r.PushPull.String())
+}
+
+func (d Distribution) String() string {
+	values := make([]string, len(d))
+	for i, c := range d {
+		values[i] = fmt.Sprintf("%s (%d)", c.Value, c.Frames)
+	}
+	return strings.Join(values, ", ")
+}
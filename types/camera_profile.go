@@ -0,0 +1,83 @@
+package types
+
+// CameraProfile describes properties of the camera identified by ES-E1
+// camera ID. Unset fields fall back to the profile of camera ID 0.
+type CameraProfile struct {
+	Make            *string          `yaml:"make,omitempty"`
+	Model           *string          `yaml:"model,omitempty"`
+	SerialNumber    *string          `yaml:"serial-number,omitempty"`
+	Timezone        *Timezone        `yaml:"timezone,omitempty"`
+	ClockCorrection *ClockCorrection `yaml:"clock-correction,omitempty"`
+
+	// Artist is the photographer name written to Artist tag
+	Artist *string `yaml:"artist,omitempty"`
+
+	// Owner is the camera owner name written to OwnerName tag
+	Owner *string `yaml:"owner,omitempty"`
+
+	// Lens is the name of the lens from the lens catalog used on the
+	// camera when the lens could not be identified by frame data
+	Lens *string `yaml:"lens,omitempty"`
+}
+
+// Merge returns the profile with fields set in other overriding the ones
+// set in the profile
+func (cp CameraProfile) Merge(other CameraProfile) CameraProfile {
+	if other.Make != nil {
+		cp.Make = other.Make
+	}
+
+	if other.Model != nil {
+		cp.Model = other.Model
+	}
+
+	if other.SerialNumber != nil {
+		cp.SerialNumber = other.SerialNumber
+	}
+
+	if other.Timezone != nil {
+		cp.Timezone = other.Timezone
+	}
+
+	if other.ClockCorrection != nil {
+		cp.ClockCorrection = other.ClockCorrection
+	}
+
+	if other.Artist != nil {
+		cp.Artist = other.Artist
+	}
+
+	if other.Owner != nil {
+		cp.Owner = other.Owner
+	}
+
+	if other.Lens != nil {
+		cp.Lens = other.Lens
+	}
+
+	return cp
+}
+
+// Fields returns the names of the fields set in the profile as they are
+// named in YAML
+func (cp CameraProfile) Fields() []string {
+	fields := []string{}
+	for _, f := range []struct {
+		name string
+		set  bool
+	}{
+		{"make", cp.Make != nil},
+		{"model", cp.Model != nil},
+		{"serial-number", cp.SerialNumber != nil},
+		{"timezone", cp.Timezone != nil},
+		{"clock-correction", cp.ClockCorrection != nil},
+		{"artist", cp.Artist != nil},
+		{"owner", cp.Owner != nil},
+		{"lens", cp.Lens != nil},
+	} {
+		if f.set {
+			fields = append(fields, f.name)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCameraProfileMerge(t *testing.T) {
+	r := require.New(t)
+
+	base := CameraProfile{
+		Make:     PtrString("Canon"),
+		Model:    PtrString("EOS-1V"),
+		Timezone: PtrString("UTC"),
+		Artist:   PtrString("John Doe"),
+	}
+
+	p := base.Merge(CameraProfile{
+		Model:           PtrString("EOS-1V HS"),
+		SerialNumber:    PtrString("123456"),
+		ClockCorrection: &ClockCorrection{Offset: PtrDuration(time.Minute)},
+		Lens:            PtrString("EF50mm f/1.4 USM"),
+	})
+
+	r.Equal(CameraProfile{
+		Make:            PtrString("Canon"),
+		Model:           PtrString("EOS-1V HS"),
+		SerialNumber:    PtrString("123456"),
+		Timezone:        PtrString("UTC"),
+		ClockCorrection: &ClockCorrection{Offset: PtrDuration(time.Minute)},
+		Artist:          PtrString("John Doe"),
+		Lens:            PtrString("EF50mm f/1.4 USM"),
+	}, p)
+	r.Equal([]string{"make", "model", "serial-number", "timezone", "clock-correction", "artist", "lens"}, p.Fields())
+
+	// merge does not modify the receiver
+	r.Equal(PtrString("EOS-1V"), base.Model)
+	r.Nil(base.SerialNumber)
+}
@@ -0,0 +1,134 @@
+package types
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	_ flag.Value = (*CameraValues)(nil)
+	_ flag.Value = (*CameraDurations)(nil)
+)
+
+// CameraValues maps camera ID to a string value. Camera ID 0 holds the value
+// applied to cameras with no value of their own. As a flag it could be set
+// either as `value` (camera ID 0) or `<camera ID>=value`, several times.
+type CameraValues map[uint8]string
+
+// Set is a part of flag.Value implementation
+func (cv *CameraValues) Set(value string) error {
+	cameraID, v, err := ParseCameraValue(value)
+	if err != nil {
+		return err
+	}
+
+	if *cv == nil {
+		*cv = CameraValues{}
+	}
+	(*cv)[cameraID] = v
+	return nil
+}
+
+// String is a part of flag.Value implementation
+func (cv *CameraValues) String() string {
+	if cv == nil {
+		return ""
+	}
+
+	ids := make([]uint8, 0, len(*cv))
+	for id := range *cv {
+		ids = append(ids, id)
+	}
+	sortCameraIDs(ids)
+
+	pairs := make([]string, len(ids))
+	for i, id := range ids {
+		pairs[i] = fmt.Sprintf("%d=%s", id, (*cv)[id])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// CameraDurations maps camera ID to a duration value the same way
+// CameraValues does
+type CameraDurations map[uint8]time.Duration
+
+// Set is a part of flag.Value implementation
+func (cd *CameraDurations) Set(value string) error {
+	cameraID, v, err := ParseCameraValue(value)
+	if err != nil {
+		return err
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return err
+	}
+
+	if *cd == nil {
+		*cd = CameraDurations{}
+	}
+	(*cd)[cameraID] = d
+	return nil
+}
+
+// String is a part of flag.Value implementation
+func (cd *CameraDurations) String() string {
+	if cd == nil {
+		return ""
+	}
+
+	ids := make([]uint8, 0, len(*cd))
+	for id := range *cd {
+		ids = append(ids, id)
+	}
+	sortCameraIDs(ids)
+
+	pairs := make([]string, len(ids))
+	for i, id := range ids {
+		pairs[i] = fmt.Sprintf("%d=%s", id, (*cd)[id])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// ParseCameraValue parses `<camera ID>=value` notation. Values without
+// camera ID prefix are returned for camera ID 0.
+func ParseCameraValue(s string) (uint8, string, error) {
+	idx := strings.IndexByte(s, '=')
+	if idx < 0 {
+		return 0, strings.TrimSpace(s), nil
+	}
+
+	prefix := strings.TrimSpace(s[:idx])
+	cameraID, err := strconv.ParseUint(prefix, 10, 8)
+	if err != nil {
+		if isDigits(prefix) {
+			return 0, "", errors.Errorf("invalid camera ID `%s`", prefix)
+		}
+		// `=` is a part of the value itself
+		return 0, strings.TrimSpace(s), nil
+	}
+
+	return uint8(cameraID), strings.TrimSpace(s[idx+1:]), nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func sortCameraIDs(ids []uint8) {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+}
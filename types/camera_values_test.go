@@ -0,0 +1,81 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCameraValue(t *testing.T) {
+	r := require.New(t)
+
+	type testCase struct {
+		name        string
+		input       string
+		expCameraID uint8
+		expValue    string
+		expError    string
+	}
+
+	tcs := []testCase{
+		{
+			name:     "value without camera ID",
+			input:    "Europe/Moscow",
+			expValue: "Europe/Moscow",
+		},
+		{
+			name:        "value with camera ID",
+			input:       "9=Europe/Moscow",
+			expCameraID: 9,
+			expValue:    "Europe/Moscow",
+		},
+		{
+			name:        "spaces around",
+			input:       " 12 = Canon ",
+			expCameraID: 12,
+			expValue:    "Canon",
+		},
+		{
+			name:     "equal sign in value",
+			input:    "a=b",
+			expValue: "a=b",
+		},
+		{
+			name:     "camera ID out of range",
+			input:    "256=Canon",
+			expError: "invalid camera ID `256`",
+		},
+	}
+
+	for _, tc := range tcs {
+		cameraID, value, err := ParseCameraValue(tc.input)
+		if tc.expError != "" {
+			r.Errorf(err, tc.name)
+			r.Equalf(tc.expError, err.Error(), tc.name)
+			continue
+		}
+		r.NoErrorf(err, tc.name)
+		r.Equalf(tc.expCameraID, cameraID, tc.name)
+		r.Equalf(tc.expValue, value, tc.name)
+	}
+}
+
+func TestCameraValues(t *testing.T) {
+	r := require.New(t)
+
+	var cv CameraValues
+	r.NoError(cv.Set("UTC"))
+	r.NoError(cv.Set("9=Europe/Moscow"))
+	r.NoError(cv.Set("3=Asia/Tokyo"))
+	r.Equal(CameraValues{0: "UTC", 3: "Asia/Tokyo", 9: "Europe/Moscow"}, cv)
+	r.Equal("0=UTC,3=Asia/Tokyo,9=Europe/Moscow", cv.String())
+
+	var cd CameraDurations
+	r.NoError(cd.Set("-1h"))
+	r.NoError(cd.Set("9=2m30s"))
+	r.Equal(CameraDurations{0: -time.Hour, 9: 150 * time.Second}, cd)
+	r.Equal("0=-1h0m0s,9=2m30s", cd.String())
+
+	r.Error(cd.Set("9=blah"))
+}
@@ -0,0 +1,30 @@
+package types
+
+import "time"
+
+// ClockCorrection describes camera clock error either as a fixed offset
+// or as a linear drift between two reference points
+type ClockCorrection struct {
+	Offset     *time.Duration   `yaml:"offset"`
+	References []ClockReference `yaml:"references"`
+}
+
+// ClockReference is a pair of camera clock and actual time for the same moment
+type ClockReference struct {
+	Camera time.Time `yaml:"camera"`
+	Actual time.Time `yaml:"actual"`
+}
+
+// MarshalYAML is a part of yaml.Marshaler implementation
+func (cc ClockCorrection) MarshalYAML() (interface{}, error) {
+	type clockCorrection struct {
+		Offset     string           `yaml:"offset,omitempty"`
+		References []ClockReference `yaml:"references,omitempty"`
+	}
+
+	v := clockCorrection{References: cc.References}
+	if cc.Offset != nil {
+		v.Offset = cc.Offset.String()
+	}
+	return v, nil
+}
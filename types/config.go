@@ -1,21 +1,37 @@
 package types
 
+import (
+	"io"
+	"time"
+)
+
 // Config repository interface
 type Config interface {
 	GetDisplayHelp() bool
 	GetDisplayVersion() bool
+	GetCameraProfile(cameraID uint8) CameraProfile
+	GetCameraProfileWithDefaults(cameraID uint8, defaults CameraProfile) CameraProfile
 	GetCopyright() *string
 	GetExiftoolBinary() string
 	GetFilenamePattern() string
 	GetFileSource() *FileSource
+	GetFilmStocks() map[string]FilmStock
+	GetFilmStockAssignments() map[string]string
 	GetGeotag() *string
-	GetMakeByCameraID(cameraID uint8) *string
-	GetModelByCameraID(cameraID uint8) *string
-	GetSerialNumberByCameraID(cameraID uint8) *string
+	GetGeotagMaxGap() time.Duration
+	GetGeotagOffset() time.Duration
+	GetLenses() []Lens
+	GetSidecarDir() string
 	GetSetDigitized() bool
 	GetTimestampFormat() *TimestampFormat
-	GetTimezoneByCameraID(cameraID uint8) Timezone
+	GetTimezoneOverrides() []TimezoneOverride
+	GetWarnings() []string
 
+	FillFromEnv(environ []string) error
 	FillFromFlags(f Flags) error
 	FillFromYaml(path string) error
+
+	Show(w io.Writer) error
+	Validate() error
+	ValidateExiftool() error
 }
@@ -0,0 +1,44 @@
+package types
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	_ flag.Value   = (*DataFormat)(nil)
+	_ fmt.Stringer = (*DataFormat)(nil)
+)
+
+// DataFormat is the serialization format of exported films
+type DataFormat string
+
+var (
+	// DataFormatJSON ...
+	DataFormatJSON DataFormat = "json"
+
+	// DataFormatYAML ...
+	DataFormatYAML DataFormat = "yaml"
+)
+
+// Set ...
+func (df *DataFormat) Set(value string) error {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	switch DataFormat(value) {
+	case DataFormatJSON:
+		*df = DataFormatJSON
+	case "yml", DataFormatYAML:
+		*df = DataFormatYAML
+	default:
+		return errors.Errorf("unknown value `%s`, allowed values: 'json', 'yaml'", value)
+	}
+	return nil
+}
+
+func (df *DataFormat) String() string {
+	return string(*df)
+}
@@ -0,0 +1,53 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataFormat(t *testing.T) {
+	r := require.New(t)
+
+	type testCase struct {
+		name      string
+		input     string
+		expOutput DataFormat
+		expError  bool
+	}
+
+	tcs := []testCase{
+		{
+			name:      "json",
+			input:     "json",
+			expOutput: DataFormatJSON,
+		},
+		{
+			name:      "yaml",
+			input:     "YAML",
+			expOutput: DataFormatYAML,
+		},
+		{
+			name:      "yml alias",
+			input:     " yml ",
+			expOutput: DataFormatYAML,
+		},
+		{
+			name:     "unexpected value",
+			input:    "xml",
+			expError: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		var df DataFormat
+		err := df.Set(tc.input)
+		if tc.expError {
+			r.Errorf(err, tc.name)
+			continue
+		}
+		r.NoErrorf(err, tc.name)
+		r.Equalf(tc.expOutput, df, tc.name)
+		r.Equalf(string(tc.expOutput), df.String(), tc.name)
+	}
+}
@@ -0,0 +1,56 @@
+package types
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	_ flag.Value   = (*ExportFormat)(nil)
+	_ fmt.Stringer = (*ExportFormat)(nil)
+)
+
+// ExportFormat is the format films are printed in by `export` command:
+// JSON or YAML document or ES-E1 CSV
+type ExportFormat string
+
+var (
+	// ExportFormatJSON ...
+	ExportFormatJSON = ExportFormat(DataFormatJSON)
+
+	// ExportFormatYAML ...
+	ExportFormatYAML = ExportFormat(DataFormatYAML)
+
+	// ExportFormatCSV ...
+	ExportFormatCSV ExportFormat = "csv"
+)
+
+// Set ...
+func (ef *ExportFormat) Set(value string) error {
+	if strings.ToLower(strings.TrimSpace(value)) == string(ExportFormatCSV) {
+		*ef = ExportFormatCSV
+		return nil
+	}
+
+	var df DataFormat
+	if err := df.Set(value); err != nil {
+		return errors.Errorf("unknown value `%s`, allowed values: 'json', 'yaml', 'csv'", strings.TrimSpace(value))
+	}
+	*ef = ExportFormat(df)
+	return nil
+}
+
+func (ef *ExportFormat) String() string {
+	return string(*ef)
+}
+
+// DataFormat returns the document format or empty value for CSV
+func (ef ExportFormat) DataFormat() DataFormat {
+	if ef == ExportFormatCSV {
+		return ""
+	}
+	return DataFormat(ef)
+}
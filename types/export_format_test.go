@@ -0,0 +1,55 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportFormat(t *testing.T) {
+	r := require.New(t)
+
+	type testCase struct {
+		name      string
+		input     string
+		expOutput ExportFormat
+		expError  bool
+	}
+
+	tcs := []testCase{
+		{
+			name:      "json",
+			input:     "json",
+			expOutput: ExportFormatJSON,
+		},
+		{
+			name:      "yml alias",
+			input:     "yml",
+			expOutput: ExportFormatYAML,
+		},
+		{
+			name:      "csv",
+			input:     " CSV ",
+			expOutput: ExportFormatCSV,
+		},
+		{
+			name:     "unexpected value",
+			input:    "xml",
+			expError: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		var ef ExportFormat
+		err := ef.Set(tc.input)
+		if tc.expError {
+			r.Errorf(err, tc.name)
+			continue
+		}
+		r.NoErrorf(err, tc.name)
+		r.Equalf(tc.expOutput, ef, tc.name)
+	}
+
+	r.Equal(DataFormatYAML, ExportFormatYAML.DataFormat())
+	r.Equal(DataFormat(""), ExportFormatCSV.DataFormat())
+}
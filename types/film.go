@@ -1,17 +1,50 @@
 package types
 
-import "time"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
 
 // Film model to store all the data about the film itself
 type Film struct {
-	ID                  *int64
-	CameraID            *uint8
-	Title               *string
-	FilmLoadedTimestamp *time.Time
-	FrameCount          *int64
-	ISO                 *int64
-	Remarks             *string
-	Frames              []*Frame
+	ID                  *int64       `json:"id,omitempty"`
+	CameraID            *uint8       `json:"camera_id,omitempty"`
+	Title               *string      `json:"title,omitempty"`
+	Make                *string      `json:"make,omitempty"`
+	Model               *string      `json:"model,omitempty"`
+	Source              *InputFormat `json:"source,omitempty"`
+	FilmLoadedTimestamp *time.Time   `json:"film_loaded_timestamp,omitempty"`
+	FrameCount          *int64       `json:"frame_count,omitempty"`
+	ISO                 *int64       `json:"iso,omitempty"`
+	Remarks             *string      `json:"remarks,omitempty"`
+	Stock               *FilmStock   `json:"stock,omitempty"`
+	Lens                *Lens        `json:"lens,omitempty"`
+	Copyright           *string      `json:"copyright,omitempty"`
+	Location            *string      `json:"location,omitempty"`
+	Developer           *string      `json:"developer,omitempty"`
+	Lab                 *string      `json:"lab,omitempty"`
+	PushPull            *float64     `json:"push_pull,omitempty"`
+	Frames              []*Frame     `json:"frames,omitempty"`
+}
+
+// FullID returns film ID in ES-E1 notation: camera ID and film ID
+// separated by dash, e.g. `01-139`
+func (f *Film) FullID() string {
+	var cameraID uint8
+	if f.CameraID != nil {
+		cameraID = *f.CameraID
+	}
+
+	var filmID int64
+	if f.ID != nil {
+		filmID = *f.ID
+	}
+
+	return fmt.Sprintf("%02d-%03d", cameraID, filmID)
 }
 
 // IsEmpty checks if Film object is empty
@@ -36,3 +69,24 @@ func (f *Film) IsEmpty() bool {
 	}
 	return true
 }
+
+// ParseFullID parses film ID in ES-E1 notation (e.g. `01-139`) into camera
+// ID and film ID
+func ParseFullID(s string) (uint8, int64, error) {
+	parts := strings.SplitN(strings.TrimSpace(s), "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, errors.Errorf("invalid film ID `%s`: `<camera ID>-<film ID>` expected", s)
+	}
+
+	cameraID, err := strconv.ParseUint(parts[0], 10, 8)
+	if err != nil {
+		return 0, 0, errors.Errorf("invalid camera ID in film ID `%s`", s)
+	}
+
+	filmID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, errors.Errorf("invalid film ID `%s`", s)
+	}
+
+	return uint8(cameraID), filmID, nil
+}
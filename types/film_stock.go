@@ -0,0 +1,132 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// FilmProcess ...
+type FilmProcess string
+
+const (
+	// FilmProcessC41 ...
+	FilmProcessC41 FilmProcess = "C-41"
+
+	// FilmProcessE6 ...
+	FilmProcessE6 FilmProcess = "E-6"
+
+	// FilmProcessECN2 ...
+	FilmProcessECN2 FilmProcess = "ECN-2"
+
+	// FilmProcessBW ...
+	FilmProcessBW FilmProcess = "B&W"
+)
+
+// FilmStock model to store film stock catalog entry
+type FilmStock struct {
+	// ID is the catalog key the stock is stored under
+	ID string `yaml:"-" json:"id,omitempty"`
+
+	Manufacturer string      `yaml:"manufacturer" json:"manufacturer"`
+	Name         string      `yaml:"name" json:"name"`
+	BoxSpeed     int64       `yaml:"box-speed" json:"box_speed"`
+	Process      FilmProcess `yaml:"process" json:"process"`
+	Format       string      `yaml:"format" json:"format,omitempty"`
+
+	// TitlePattern is a regular expression matched against film title
+	// to assign the stock to films automatically
+	TitlePattern string `yaml:"title-pattern" json:"title_pattern,omitempty"`
+}
+
+func (fs *FilmStock) String() string {
+	return strings.TrimSpace(fs.Manufacturer + " " + fs.Name)
+}
+
+// PushPull returns the difference between shot ISO and box speed in
+// stops rounded to 1/3 stop: positive for push, negative for pull
+func (fs *FilmStock) PushPull(iso int64) float64 {
+	if fs.BoxSpeed <= 0 || iso <= 0 {
+		return 0
+	}
+
+	stops := math.Log2(float64(iso) / float64(fs.BoxSpeed))
+	return math.Round(stops*3) / 3
+}
+
+// FormatStops formats exposure stops difference as `+1`, `-2/3`, `+1 1/3`
+func FormatStops(stops float64) string {
+	thirds := int(math.Round(stops * 3))
+	if thirds == 0 {
+		return "0"
+	}
+
+	sign := "+"
+	if thirds < 0 {
+		sign = "-"
+		thirds = -thirds
+	}
+
+	whole, rem := thirds/3, thirds%3
+	switch {
+	case rem == 0:
+		return fmt.Sprintf("%s%d", sign, whole)
+	case whole == 0:
+		return fmt.Sprintf("%s%d/3", sign, rem)
+	}
+	return fmt.Sprintf("%s%d %d/3", sign, whole, rem)
+}
+
+// ParseStops parses exposure stops difference written as `+1`, `-2/3`,
+// `+1 1/3` or a decimal number
+func ParseStops(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrEmptyValue
+	}
+
+	sign := 1.0
+	body := s
+	switch body[0] {
+	case '-':
+		sign = -1
+		body = body[1:]
+	case '+':
+		body = body[1:]
+	}
+
+	var stops float64
+	for _, part := range strings.Fields(body) {
+		v, err := parseFraction(part)
+		if err != nil {
+			return 0, errors.Errorf("invalid stops value `%s`", s)
+		}
+		stops += v
+	}
+
+	if body == "" || len(strings.Fields(body)) > 2 {
+		return 0, errors.Errorf("invalid stops value `%s`", s)
+	}
+
+	return sign * stops, nil
+}
+
+func parseFraction(s string) (float64, error) {
+	parts := strings.SplitN(s, "/", 2)
+	num, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil || len(parts) == 1 {
+		return num, err
+	}
+
+	den, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return 0, err
+	}
+	if den == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return num / den, nil
+}
@@ -0,0 +1,69 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilmStockPushPull(t *testing.T) {
+	r := require.New(t)
+
+	type testCase struct {
+		name      string
+		boxSpeed  int64
+		iso       int64
+		expStops  float64
+		expString string
+	}
+
+	tcs := []testCase{
+		{name: "box speed", boxSpeed: 400, iso: 400, expStops: 0, expString: "0"},
+		{name: "push 1 stop", boxSpeed: 400, iso: 800, expStops: 1, expString: "+1"},
+		{name: "push 1 1/3 stop", boxSpeed: 400, iso: 1000, expStops: 4.0 / 3, expString: "+1 1/3"},
+		{name: "pull 1/3 stop", boxSpeed: 400, iso: 320, expStops: -1.0 / 3, expString: "-1/3"},
+		{name: "pull 2 stops", boxSpeed: 400, iso: 100, expStops: -2, expString: "-2"},
+		{name: "no box speed", boxSpeed: 0, iso: 100, expStops: 0, expString: "0"},
+	}
+
+	for _, tc := range tcs {
+		fs := &FilmStock{BoxSpeed: tc.boxSpeed}
+		stops := fs.PushPull(tc.iso)
+		r.InDeltaf(tc.expStops, stops, 1e-9, tc.name)
+		r.Equalf(tc.expString, FormatStops(stops), tc.name)
+	}
+}
+
+func TestParseStops(t *testing.T) {
+	r := require.New(t)
+
+	type testCase struct {
+		name     string
+		input    string
+		expStops float64
+		expError bool
+	}
+
+	tcs := []testCase{
+		{name: "zero", input: "0", expStops: 0},
+		{name: "whole stops", input: "+1", expStops: 1},
+		{name: "negative fraction", input: "-2/3", expStops: -2.0 / 3},
+		{name: "mixed fraction", input: "+1 1/3", expStops: 4.0 / 3},
+		{name: "negative mixed fraction", input: "-1 2/3", expStops: -5.0 / 3},
+		{name: "decimal", input: "0.5", expStops: 0.5},
+		{name: "empty", input: " ", expError: true},
+		{name: "sign only", input: "+", expError: true},
+		{name: "garbage", input: "one stop", expError: true},
+		{name: "zero denominator", input: "1/0", expError: true},
+	}
+
+	for _, tc := range tcs {
+		stops, err := ParseStops(tc.input)
+		if tc.expError {
+			r.Errorf(err, tc.name)
+			continue
+		}
+		r.NoErrorf(err, tc.name)
+		r.InDeltaf(tc.expStops, stops, 1e-9, tc.name)
+	}
+}
@@ -68,3 +68,64 @@ func TestFilmIsEmpty(t *testing.T) {
 		r.Equalf(tc.expResult, tc.filmSample.IsEmpty(), tc.name)
 	}
 }
+
+func TestFilmFullID(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal("01-139", (&Film{ID: PtrInt64(139), CameraID: PtrUint8(1)}).FullID())
+	r.Equal("12-007", (&Film{ID: PtrInt64(7), CameraID: PtrUint8(12)}).FullID())
+	r.Equal("00-000", (&Film{}).FullID())
+}
+
+func TestParseFullID(t *testing.T) {
+	r := require.New(t)
+
+	type testCase struct {
+		name        string
+		in          string
+		expCameraID uint8
+		expFilmID   int64
+		expError    bool
+	}
+
+	tcs := []testCase{
+		{
+			name:        "ES-E1 notation",
+			in:          "01-139",
+			expCameraID: 1,
+			expFilmID:   139,
+		},
+		{
+			name:        "no padding",
+			in:          "9-5",
+			expCameraID: 9,
+			expFilmID:   5,
+		},
+		{
+			name:     "no dash",
+			in:       "01139",
+			expError: true,
+		},
+		{
+			name:     "camera ID out of range",
+			in:       "256-1",
+			expError: true,
+		},
+		{
+			name:     "non-numeric film ID",
+			in:       "01-abc",
+			expError: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		cameraID, filmID, err := ParseFullID(tc.in)
+		if tc.expError {
+			r.Errorf(err, tc.name)
+			continue
+		}
+		r.NoErrorf(err, tc.name)
+		r.Equalf(tc.expCameraID, cameraID, tc.name)
+		r.Equalf(tc.expFilmID, filmID, tc.name)
+	}
+}
@@ -1,23 +1,38 @@
 package types
 
+import "time"
+
 // Flags repository interface
 type Flags interface {
 	PrintUsageString()
 	PrintVersionString()
 
-	GetCSVPath() string
+	GetInputPaths() []string
 
 	GetDisplayHelp() bool
 	GetDisplayVersion() bool
+	GetConfigPath() string
+	GetShowConfig() bool
+	GetExport() DataFormat
+	GetFormat() ExportFormat
+	GetColumns() string
+	GetJSON() bool
+	GetOutput() string
+	GetThumbnailSize() int
+	GetInputFormat() InputFormat
+	GetClockOffset() map[uint8]time.Duration
 	GetCopyright() string
 	GetExiftoolBinary() string
 	GetFilenamePattern() string
 	GetFileSource() FileSource
 	GetGeotag() string
-	GetMake() string
-	GetModel() string
-	GetSerialNumber() string
+	GetGeotagMaxGap() time.Duration
+	GetGeotagOffset() time.Duration
+	GetMake() map[uint8]string
+	GetModel() map[uint8]string
+	GetSerialNumber() map[uint8]string
+	GetSidecarDir() string
 	GetSetDigitized() bool
 	GetTimestampFormat() TimestampFormat
-	GetTimezone() Timezone
+	GetTimezone() map[uint8]Timezone
 }
@@ -4,23 +4,26 @@ import "time"
 
 // Frame model to store all the data about particular frame
 type Frame struct {
-	Flag                 *bool
-	Number               *int64
-	FocalLength          *int64
-	MaxAperture          *Aperture
-	Tv                   *string
-	Av                   *Aperture
-	ISO                  *int64
-	ExposureCompensation *float64
-	FlashCompensation    *float64
-	FlashMode            *FlashMode
-	MeteringMode         *MeteringMode
-	ShootingMode         *ShootingMode
-	FilmAdvanceMode      *FilmAdvanceMode
-	AFMode               *AFMode
-	BulbExposureTime     *string
-	Timestamp            *time.Time
-	MultipleExposure     *MultipleExposure
-	BatteryLoadedDate    *time.Time
-	Remarks              *string
+	Flag                 *bool             `json:"flag,omitempty"`
+	Number               *int64            `json:"number,omitempty"`
+	FocalLength          *int64            `json:"focal_length,omitempty"`
+	Lens                 *string           `json:"lens,omitempty"`
+	MaxAperture          *Aperture         `json:"max_aperture,omitempty"`
+	Tv                   *string           `json:"tv,omitempty"`
+	Av                   *Aperture         `json:"av,omitempty"`
+	ISO                  *int64            `json:"iso,omitempty"`
+	ExposureCompensation *float64          `json:"exposure_compensation,omitempty"`
+	FlashCompensation    *float64          `json:"flash_compensation,omitempty"`
+	FlashMode            *FlashMode        `json:"flash_mode,omitempty"`
+	MeteringMode         *MeteringMode     `json:"metering_mode,omitempty"`
+	ShootingMode         *ShootingMode     `json:"shooting_mode,omitempty"`
+	FilmAdvanceMode      *FilmAdvanceMode  `json:"film_advance_mode,omitempty"`
+	AFMode               *AFMode           `json:"af_mode,omitempty"`
+	BulbExposureTime     *string           `json:"bulb_exposure_time,omitempty"`
+	Timestamp            *time.Time        `json:"timestamp,omitempty"`
+	Date                 *time.Time        `json:"date,omitempty"`
+	MultipleExposure     *MultipleExposure `json:"multiple_exposure,omitempty"`
+	BatteryLoadedDate    *time.Time        `json:"battery_loaded_date,omitempty"`
+	Remarks              *string           `json:"remarks,omitempty"`
+	Position             *Position         `json:"position,omitempty"`
 }
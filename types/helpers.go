@@ -14,6 +14,9 @@ func PtrFloat64(t float64) *float64 { return &t }
 // PtrString ...
 func PtrString(t string) *string { return &t }
 
+// PtrDuration ...
+func PtrDuration(d time.Duration) *time.Duration { return &d }
+
 // PtrTime ...
 func PtrTime(t time.Time) *time.Time { return &t }
 
@@ -89,3 +92,11 @@ func PtrTimestampFormat(tf TimestampFormat) *TimestampFormat {
 
 // PtrUint8 ...
 func PtrUint8(u uint8) *uint8 { return &u }
+
+// PtrInputFormat ...
+func PtrInputFormat(f InputFormat) *InputFormat {
+	if f == "" {
+		return nil
+	}
+	return &f
+}
@@ -0,0 +1,64 @@
+package types
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	_ flag.Value   = (*InputFormat)(nil)
+	_ fmt.Stringer = (*InputFormat)(nil)
+)
+
+// InputFormat is the name of the format of input file films are read from.
+// Formats are registered in the parser package so the value is checked
+// against the registry when the file is opened.
+type InputFormat string
+
+var (
+	// InputFormatCSV is ES-E1 CSV export
+	InputFormatCSV InputFormat = "csv"
+
+	// InputFormatJSON is JSON export document
+	InputFormatJSON InputFormat = "json"
+
+	// InputFormatYAML is YAML export document
+	InputFormatYAML InputFormat = "yaml"
+
+	// InputFormatFilmLog is hand-written film log
+	InputFormatFilmLog InputFormat = "log"
+
+	// InputFormatExifNotes is JSON roll export of Exif Notes app
+	InputFormatExifNotes InputFormat = "exifnotes"
+
+	// InputFormatNikon is CSV export of Nikon data memory transfer tools
+	InputFormatNikon InputFormat = "nikon"
+
+	// InputFormatAuto is detected from the file content
+	InputFormatAuto InputFormat = ""
+)
+
+// Set ...
+func (f *InputFormat) Set(value string) error {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return errors.New("empty input format")
+	}
+
+	switch value {
+	case "yml":
+		value = string(InputFormatYAML)
+	case "auto":
+		value = string(InputFormatAuto)
+	}
+
+	*f = InputFormat(value)
+	return nil
+}
+
+func (f *InputFormat) String() string {
+	return string(*f)
+}
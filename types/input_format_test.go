@@ -0,0 +1,62 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInputFormat(t *testing.T) {
+	r := require.New(t)
+
+	type testCase struct {
+		name      string
+		input     string
+		expOutput InputFormat
+		expError  bool
+	}
+
+	tcs := []testCase{
+		{
+			name:      "csv",
+			input:     "CSV",
+			expOutput: InputFormatCSV,
+		},
+		{
+			name:      "json",
+			input:     "json",
+			expOutput: InputFormatJSON,
+		},
+		{
+			name:      "yml alias",
+			input:     "yml",
+			expOutput: InputFormatYAML,
+		},
+		{
+			name:      "format registered elsewhere",
+			input:     " Nikon ",
+			expOutput: InputFormatNikon,
+		},
+		{
+			name:      "auto",
+			input:     "auto",
+			expOutput: InputFormatAuto,
+		},
+		{
+			name:     "empty value",
+			input:    " ",
+			expError: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		var f InputFormat
+		err := f.Set(tc.input)
+		if tc.expError {
+			r.Errorf(err, tc.name)
+			continue
+		}
+		r.NoErrorf(err, tc.name)
+		r.Equalf(tc.expOutput, f, tc.name)
+	}
+}
@@ -0,0 +1,97 @@
+package types
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Lens model to store lens catalog entry
+type Lens struct {
+	Name         string      `yaml:"name" json:"name"`
+	Make         string      `yaml:"make,omitempty" json:"make,omitempty"`
+	FocalLength  Range       `yaml:"focal-length" json:"focal_length"`
+	MaxAperture  Range       `yaml:"max-aperture" json:"max_aperture"`
+	SerialNumber string      `yaml:"serial-number,omitempty" json:"serial_number,omitempty"`
+	Owned        []DateRange `yaml:"owned,omitempty" json:"owned,omitempty"`
+}
+
+// Range is an inclusive range of values. In YAML it could be set as
+// a single value (`50`), a dash separated pair (`24-70`) or a mapping
+// with `min` and `max` keys.
+type Range struct {
+	Min float64 `yaml:"min" json:"min"`
+	Max float64 `yaml:"max" json:"max"`
+}
+
+// DateRange is an inclusive range of dates, both ends are optional
+type DateRange struct {
+	From *time.Time `yaml:"from,omitempty" json:"from,omitempty"`
+	To   *time.Time `yaml:"to,omitempty" json:"to,omitempty"`
+}
+
+// NewRangeFromString parses range from `50` or `24-70` notation
+func NewRangeFromString(s string) (*Range, error) {
+	parts := strings.SplitN(strings.TrimSpace(s), "-", 2)
+
+	min, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return nil, errors.Errorf("invalid range value `%s`", s)
+	}
+
+	max := min
+	if len(parts) == 2 {
+		max, err = strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil {
+			return nil, errors.Errorf("invalid range value `%s`", s)
+		}
+	}
+
+	return &Range{Min: min, Max: max}, nil
+}
+
+// UnmarshalYAML is a part of yaml.Unmarshaler implementation
+func (r *Range) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err == nil {
+		v, err := NewRangeFromString(s)
+		if err != nil {
+			return err
+		}
+		*r = *v
+		return nil
+	}
+
+	type plain Range
+	var v plain
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	if v.Max == 0 {
+		v.Max = v.Min
+	}
+	*r = Range(v)
+
+	return nil
+}
+
+// Contains checks if the value is within the range with tolerance
+func (r Range) Contains(v, tolerance float64) bool {
+	return v >= r.Min-tolerance && v <= r.Max+tolerance
+}
+
+// Contains checks if the timestamp date is within the range
+func (d DateRange) Contains(t time.Time) bool {
+	date := t.Format("2006-01-02")
+	if d.From != nil && date < d.From.Format("2006-01-02") {
+		return false
+	}
+
+	if d.To != nil && date > d.To.Format("2006-01-02") {
+		return false
+	}
+
+	return true
+}
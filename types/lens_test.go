@@ -0,0 +1,65 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestRangeFromYAML(t *testing.T) {
+	r := require.New(t)
+
+	type testCase struct {
+		name      string
+		input     string
+		expOutput Range
+		expError  string
+	}
+
+	tcs := []testCase{
+		{
+			name:      "single integer value",
+			input:     `50`,
+			expOutput: Range{Min: 50, Max: 50},
+		},
+		{
+			name:      "single float value",
+			input:     `1.4`,
+			expOutput: Range{Min: 1.4, Max: 1.4},
+		},
+		{
+			name:      "dash separated pair",
+			input:     `"3.5-5.6"`,
+			expOutput: Range{Min: 3.5, Max: 5.6},
+		},
+		{
+			name:      "mapping",
+			input:     `{min: 24, max: 105}`,
+			expOutput: Range{Min: 24, Max: 105},
+		},
+		{
+			name:      "mapping with min only",
+			input:     `{min: 35}`,
+			expOutput: Range{Min: 35, Max: 35},
+		},
+		{
+			name:     "invalid value",
+			input:    `"wide"`,
+			expError: "invalid range value `wide`",
+		},
+	}
+
+	for _, tc := range tcs {
+		var v Range
+		err := yaml.Unmarshal([]byte(tc.input), &v)
+		if tc.expError != "" {
+			r.Errorf(err, tc.name)
+			r.Equalf(tc.expError, err.Error(), tc.name)
+			continue
+		}
+
+		r.NoErrorf(err, tc.name)
+		r.Equalf(tc.expOutput, v, tc.name)
+	}
+}
@@ -0,0 +1,105 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Film and Frame are marshalled to YAML through their JSON representation
+// so both formats share the same field names, string enums and RFC3339
+// timestamps.
+
+type (
+	film  Film
+	frame Frame
+)
+
+// MarshalYAML ...
+func (f Film) MarshalYAML() (interface{}, error) {
+	return jsonToYAML(film(f))
+}
+
+// UnmarshalYAML ...
+func (f *Film) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return yamlToJSON(unmarshal, (*film)(f))
+}
+
+// MarshalYAML ...
+func (f Frame) MarshalYAML() (interface{}, error) {
+	return jsonToYAML(frame(f))
+}
+
+// UnmarshalYAML ...
+func (f *Frame) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return yamlToJSON(unmarshal, (*frame)(f))
+}
+
+func jsonToYAML(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON is valid YAML, decoding into MapSlice keeps the field order
+	var ms yaml.MapSlice
+	if err := yaml.Unmarshal(data, &ms); err != nil {
+		return nil, err
+	}
+	return ms, nil
+}
+
+func yamlToJSON(unmarshal func(interface{}) error, v interface{}) error {
+	var raw interface{}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	jv, err := jsonValue(raw)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(jv)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// jsonValue converts YAML mappings decoded as map[interface{}]interface{}
+// into JSON compatible map[string]interface{}
+func jsonValue(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			ks, ok := k.(string)
+			if !ok {
+				return nil, errors.Errorf("non-string key `%v`", k)
+			}
+			jv, err := jsonValue(val)
+			if err != nil {
+				return nil, err
+			}
+			m[ks] = jv
+		}
+		return m, nil
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, val := range v {
+			jv, err := jsonValue(val)
+			if err != nil {
+				return nil, err
+			}
+			s[i] = jv
+		}
+		return s, nil
+	}
+	return v, nil
+}
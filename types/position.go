@@ -0,0 +1,8 @@
+package types
+
+// Position model to store geographic location
+type Position struct {
+	Latitude  float64  `json:"latitude"`
+	Longitude float64  `json:"longitude"`
+	Altitude  *float64 `json:"altitude,omitempty"`
+}
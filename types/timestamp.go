@@ -1,6 +0,0 @@
-package types
-
-const (
-	// GeoTimeFormat ...
-	GeoTimeFormat = "2006:01:02 15:04:05-0700"
-)
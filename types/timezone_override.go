@@ -0,0 +1,28 @@
+package types
+
+import "time"
+
+// TimezoneOverride sets timezone for the frames of particular film and/or
+// shot within particular date range instead of the camera timezone
+type TimezoneOverride struct {
+	CameraID *uint8     `yaml:"camera-id,omitempty"`
+	FilmID   *int64     `yaml:"film-id,omitempty"`
+	From     *time.Time `yaml:"from,omitempty"`
+	To       *time.Time `yaml:"to,omitempty"`
+	Timezone Timezone   `yaml:"timezone"`
+}
+
+// Matches checks if the override is applicable to the film and the
+// timestamp. From and To dates are inclusive and compared against the
+// timestamp wall clock date.
+func (o *TimezoneOverride) Matches(film *Film, t time.Time) bool {
+	if o.CameraID != nil && (film.CameraID == nil || *film.CameraID != *o.CameraID) {
+		return false
+	}
+
+	if o.FilmID != nil && (film.ID == nil || *film.ID != *o.FilmID) {
+		return false
+	}
+
+	return DateRange{From: o.From, To: o.To}.Contains(t)
+}
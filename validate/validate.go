@@ -0,0 +1,121 @@
+package validate
+
+import (
+	"fmt"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+// Severity of the problem
+type Severity string
+
+const (
+	// SeverityError is the problem leading to wrong or missing tags
+	SeverityError Severity = "error"
+
+	// SeverityWarning is suspicious data which could be right
+	SeverityWarning Severity = "warning"
+)
+
+// Problem found in parsed film or frame
+type Problem struct {
+	Severity Severity
+	FilmID   string
+
+	// FrameNo is nil for problems of the film itself
+	FrameNo *int64
+
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.FrameNo == nil {
+		return fmt.Sprintf("%s: film %s: %s", p.Severity, p.FilmID, p.Message)
+	}
+	return fmt.Sprintf("%s: film %s frame %d: %s", p.Severity, p.FilmID, *p.FrameNo, p.Message)
+}
+
+// Films checks parsed films for problems: missing and duplicate frame
+// numbers, frame numbers beyond the frame count, missing exposure data and
+// timestamps, timestamps going backwards or preceding the film load.
+// Problems are returned in the order of films and frames.
+func Films(films []*types.Film) []Problem {
+	problems := []Problem{}
+	for _, f := range films {
+		problems = append(problems, film(f)...)
+	}
+	return problems
+}
+
+// HasErrors reports whether any of the problems is an error
+func HasErrors(problems []Problem) bool {
+	for _, p := range problems {
+		if p.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func film(f *types.Film) []Problem {
+	problems := []Problem{}
+	report := func(severity Severity, frameNo *int64, format string, args ...interface{}) {
+		problems = append(problems, Problem{
+			Severity: severity,
+			FilmID:   f.FullID(),
+			FrameNo:  frameNo,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	if f.ID == nil || f.CameraID == nil {
+		report(SeverityError, nil, "film ID or camera ID is not set")
+	}
+
+	if len(f.Frames) == 0 {
+		report(SeverityWarning, nil, "no frames recorded")
+	}
+
+	seen := map[int64]bool{}
+	var prev *types.Frame
+	for i, fr := range f.Frames {
+		if fr.Number == nil {
+			report(SeverityError, nil, "frame #%d in the list has no frame number", i+1)
+			continue
+		}
+		no := fr.Number
+
+		if seen[*no] {
+			report(SeverityError, no, "duplicate frame number")
+		}
+		seen[*no] = true
+
+		if f.FrameCount != nil && *no > *f.FrameCount {
+			report(SeverityWarning, no, "frame number is beyond the frame count %d", *f.FrameCount)
+		}
+
+		if fr.Tv == nil && fr.Av == nil {
+			report(SeverityWarning, no, "neither shutter speed nor aperture recorded")
+		}
+
+		if fr.Timestamp == nil || fr.Timestamp.IsZero() {
+			report(SeverityWarning, no, "no timestamp recorded")
+			continue
+		}
+
+		if f.FilmLoadedTimestamp != nil && fr.Timestamp.Before(*f.FilmLoadedTimestamp) {
+			report(SeverityError, no, "timestamp %s is before the film was loaded at %s",
+				fr.Timestamp.Format(timeFormat), f.FilmLoadedTimestamp.Format(timeFormat))
+		}
+
+		if prev != nil && fr.Timestamp.Before(*prev.Timestamp) {
+			report(SeverityError, no, "timestamp %s is before the one of frame %d (%s)",
+				fr.Timestamp.Format(timeFormat), *prev.Number, prev.Timestamp.Format(timeFormat))
+		}
+		prev = fr
+	}
+
+	return problems
+}
+
+const timeFormat = "2006-01-02 15:04:05"
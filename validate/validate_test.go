@@ -0,0 +1,96 @@
+package validate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	types "github.com/teran/eos-1v-tagger/types"
+)
+
+func TestFilms(t *testing.T) {
+	r := require.New(t)
+
+	ts := func(hour, min int) *time.Time {
+		return types.PtrTime(time.Date(2020, 5, 1, hour, min, 0, 0, time.UTC))
+	}
+
+	type testCase struct {
+		name     string
+		films    []*types.Film
+		expected []string
+	}
+
+	tcs := []testCase{
+		{
+			name: "valid film",
+			films: []*types.Film{
+				{
+					ID:                  types.PtrInt64(1),
+					CameraID:            types.PtrUint8(1),
+					FilmLoadedTimestamp: ts(9, 0),
+					FrameCount:          types.PtrInt64(2),
+					Frames: []*types.Frame{
+						{Number: types.PtrInt64(1), Tv: types.PtrString("1/60"), Timestamp: ts(10, 0)},
+						{Number: types.PtrInt64(2), Av: types.PtrAperture(8), Timestamp: ts(10, 0)},
+					},
+				},
+			},
+			expected: []string{},
+		},
+		{
+			name: "film without frames",
+			films: []*types.Film{
+				{ID: types.PtrInt64(2), CameraID: types.PtrUint8(1)},
+			},
+			expected: []string{
+				"warning: film 01-002: no frames recorded",
+			},
+		},
+		{
+			name: "frame problems",
+			films: []*types.Film{
+				{
+					ID:                  types.PtrInt64(3),
+					CameraID:            types.PtrUint8(1),
+					FilmLoadedTimestamp: ts(9, 0),
+					FrameCount:          types.PtrInt64(3),
+					Frames: []*types.Frame{
+						{Number: types.PtrInt64(1), Tv: types.PtrString("1/60"), Timestamp: ts(8, 30)},
+						{Number: types.PtrInt64(2), Tv: types.PtrString("1/60"), Timestamp: ts(10, 0)},
+						{Number: types.PtrInt64(2), Tv: types.PtrString("1/60"), Timestamp: ts(9, 30)},
+						{Number: types.PtrInt64(4)},
+						{Tv: types.PtrString("1/60")},
+					},
+				},
+			},
+			expected: []string{
+				"error: film 01-003 frame 1: timestamp 2020-05-01 08:30:00 is before the film was loaded at 2020-05-01 09:00:00",
+				"error: film 01-003 frame 2: duplicate frame number",
+				"error: film 01-003 frame 2: timestamp 2020-05-01 09:30:00 is before the one of frame 2 (2020-05-01 10:00:00)",
+				"warning: film 01-003 frame 4: frame number is beyond the frame count 3",
+				"warning: film 01-003 frame 4: neither shutter speed nor aperture recorded",
+				"warning: film 01-003 frame 4: no timestamp recorded",
+				"error: film 01-003: frame #5 in the list has no frame number",
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		problems := Films(tc.films)
+		messages := []string{}
+		for _, p := range problems {
+			messages = append(messages, p.String())
+		}
+		r.Equalf(tc.expected, messages, tc.name)
+	}
+}
+
+func TestHasErrors(t *testing.T) {
+	r := require.New(t)
+
+	r.False(HasErrors(nil))
+	r.False(HasErrors([]Problem{{Severity: SeverityWarning}}))
+	r.True(HasErrors([]Problem{{Severity: SeverityWarning}, {Severity: SeverityError}}))
+}